@@ -0,0 +1,113 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// AuditEntry describes a single mutating API call
+type AuditEntry struct {
+	Time     time.Time `json:"time"`
+	Endpoint string    `json:"endpoint"`
+	// Subdomain is the record name of the call, whichever parameter the endpoint takes it in
+	Subdomain string     `json:"subdomain,omitempty"`
+	Params    url.Values `json:"params"`
+	Result    string     `json:"result"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// AuditHook receives an entry for every mutating API call
+type AuditHook func(entry AuditEntry)
+
+// readOnlyEndpoints lists the endpoints that don't change anything and are not audited
+var readOnlyEndpoints = map[string]bool{
+	"zone/get_resource_records": true,
+	"service/get_list":          true,
+}
+
+// maskedValue replaces credentials in audited and logged parameters
+const maskedValue = "****"
+
+// sensitiveParams lists the request parameters masked before they are audited or logged
+var sensitiveParams = []string{"username", "password"}
+
+// sanitizeParams returns a copy of the parameters with credentials masked
+func sanitizeParams(params url.Values) url.Values {
+	sanitized := make(url.Values, len(params))
+	for key, values := range params {
+		sanitized[key] = append([]string(nil), values...)
+	}
+	for _, key := range sensitiveParams {
+		if _, ok := sanitized[key]; ok {
+			sanitized.Set(key, maskedValue)
+		}
+	}
+	return sanitized
+}
+
+// audit reports a completed mutating call to the audit hook
+func (c *Client) audit(endpoint, subdomain string, params url.Values, err error) {
+	if c.AuditHook == nil || readOnlyEndpoints[endpoint] {
+		return
+	}
+
+	entry := AuditEntry{
+		Time:      time.Now().UTC(),
+		Endpoint:  endpoint,
+		Subdomain: subdomain,
+		Params:    sanitizeParams(params),
+		Result:    "success",
+	}
+	if err != nil {
+		entry.Result = "error"
+		entry.Error = err.Error()
+	}
+
+	c.AuditHook(entry)
+}
+
+// NewFileAuditHook returns an audit hook appending entries to the file at path as JSON lines.
+// The file is opened for each entry rather than held open, so configuring the provider again
+// leaves no file descriptor behind. An entry that can't be written is logged as a warning.
+func NewFileAuditHook(path string) (AuditHook, error) {
+	// Check up front that the file can be written, so a bad path fails the configuration
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+
+	var mu sync.Mutex
+	return func(entry AuditEntry) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err := appendAuditEntry(path, entry); err != nil {
+			log.Printf("[WARN] Failed to write audit log entry for %s to %s: %v", entry.Endpoint, path, err)
+		}
+	}, nil
+}
+
+// appendAuditEntry appends the entry to the file at path as a JSON line
+func appendAuditEntry(path string, entry AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,116 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAuditCreate(t *testing.T) {
+	c := newTestClient(t, respond(http.StatusOK, successResponse))
+	path := filepath.Join(t.TempDir(), "audit.log")
+	hook, err := NewFileAuditHook(path)
+	if err != nil {
+		t.Fatalf("NewFileAuditHook: %v", err)
+	}
+	c.AuditHook = hook
+
+	// A create reads the zone and adds the record; only the add is audited
+	if _, err := c.GetRecords("example.com"); err != nil {
+		t.Fatalf("GetRecords: %v", err)
+	}
+	if _, err := c.AddRecord("A", "example.com", "www", "192.0.2.1", nil); err != nil {
+		t.Fatalf("AddRecord: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading audit log: %v", err)
+	}
+	if strings.Contains(string(data), "secret") {
+		t.Errorf("audit log %q contains the password", data)
+	}
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("audit entry %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("audit entries = %+v, want one", entries)
+	}
+
+	entry := entries[0]
+	if entry.Endpoint != "zone/add_alias" || entry.Result != "success" {
+		t.Errorf("entry = %s %s, want zone/add_alias success", entry.Endpoint, entry.Result)
+	}
+	for _, key := range []string{"username", "password"} {
+		if got := entry.Params.Get(key); got != maskedValue {
+			t.Errorf("%s = %q, want it masked", key, got)
+		}
+	}
+	if got := entry.Params.Get("subdomain"); got != "www" {
+		t.Errorf("subdomain = %q, want www", got)
+	}
+}
+
+func TestDebugLogMasksCredentials(t *testing.T) {
+	var output bytes.Buffer
+	log.SetOutput(&output)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	c := newTestClient(t, respond(http.StatusOK, successResponse))
+	if _, err := c.AddRecord("A", "example.com", "www", "192.0.2.1", nil); err != nil {
+		t.Fatalf("AddRecord: %v", err)
+	}
+
+	logged := output.String()
+	if strings.Contains(logged, "secret") {
+		t.Errorf("debug log contains the password:\n%s", logged)
+	}
+	if !strings.Contains(logged, "password="+url.QueryEscape(maskedValue)) {
+		t.Errorf("debug log does not show the masked password:\n%s", logged)
+	}
+	if !strings.Contains(logged, "ipaddr=192.0.2.1") {
+		t.Errorf("debug log does not show the other parameters:\n%s", logged)
+	}
+}
+
+func TestAuditWriteFailureLogged(t *testing.T) {
+	var output bytes.Buffer
+	log.SetOutput(&output)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	dir := filepath.Join(t.TempDir(), "audit")
+	if err := os.Mkdir(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	c := newTestClient(t, respond(http.StatusOK, successResponse))
+	hook, err := NewFileAuditHook(filepath.Join(dir, "audit.log"))
+	if err != nil {
+		t.Fatalf("NewFileAuditHook: %v", err)
+	}
+	c.AuditHook = hook
+
+	// The audit log can no longer be written once its directory is gone
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.AddRecord("A", "example.com", "www", "192.0.2.1", nil); err != nil {
+		t.Fatalf("AddRecord: %v", err)
+	}
+	if logged := output.String(); !strings.Contains(logged, "[WARN] Failed to write audit log entry for zone/add_alias") {
+		t.Errorf("log does not warn about the lost audit entry:\n%s", logged)
+	}
+}
@@ -1,12 +1,21 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync/atomic"
+	"terraform-provider-regru/version"
+	"time"
+
+	"golang.org/x/time/rate"
 )
 
 // Client структура для работы с API Reg.ru
@@ -14,6 +23,47 @@ type Client struct {
 	Username string
 	Password string
 	BaseURL  string
+
+	// StrictErrors makes unrecognized API error codes fail with the raw response attached
+	StrictErrors bool
+
+	// Endpoints overrides the default record type to add endpoint mapping
+	Endpoints map[string]string
+
+	// SubdomainParams overrides the name of the subdomain parameter per endpoint
+	SubdomainParams map[string]string
+
+	// RateLimitRetries is the number of retries for requests rejected by the API rate limit,
+	// and for requests that failed transiently where repeating them is safe
+	RateLimitRetries int
+	// RateLimitDelay is the base delay before retrying a request, doubled on each attempt up
+	// to MaxRateLimitDelay
+	RateLimitDelay time.Duration
+
+	// MaxResponseSize is the maximum size in bytes of an API response body
+	MaxResponseSize int64
+
+	// HTTPClient sends the API requests. It is shared with copies of the client so that
+	// connections to the API are reused across all operations.
+	HTTPClient *http.Client
+
+	// ctx cancels the client's requests, e.g. when Terraform is interrupted
+	ctx context.Context
+
+	// AuditHook, when set, is called for every mutating API call
+	AuditHook AuditHook
+
+	// MaxRequests caps the number of API requests made by the client, 0 means unlimited
+	MaxRequests int64
+
+	// requestSlots limits the number of in-flight API requests when non-nil
+	requestSlots chan struct{}
+
+	// limiter spaces API requests to the configured rate when non-nil, shared with copies of the client
+	limiter *rate.Limiter
+
+	// requestCount counts the API requests made, shared with copies of the client
+	requestCount *atomic.Int64
 }
 
 // APIError represents the error response structure
@@ -37,17 +87,314 @@ type APIResponse struct {
 	Result string `json:"result"`
 }
 
-// NewClient создает новый экземпляр клиента
-func NewClient(username, password string) *Client {
+// DefaultEndpoints maps record types to the API endpoints used to add them
+var DefaultEndpoints = map[string]string{
+	"A":     "zone/add_alias",
+	"AAAA":  "zone/add_aaaa",
+	"CNAME": "zone/add_cname",
+	"MX":    "zone/add_mx",
+	"NS":    "zone/add_ns",
+	"SRV":   "zone/add_srv",
+	"CAA":   "zone/add_caa",
+	"TXT":   "zone/add_txt",
+	"PTR":   "zone/add_ptr",
+	"SSHFP": "zone/add_sshfp",
+	"TLSA":  "zone/add_tlsa",
+	"DNAME": "zone/add_dname",
+}
+
+// CodedError is an API error carrying the Reg.ru error code
+type CodedError struct {
+	Code string
+	Err  error
+}
+
+func (e *CodedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CodedError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorCode returns the Reg.ru error code, letting packages that don't depend on the client
+// recognize API errors
+func (e *CodedError) ErrorCode() string {
+	return e.Code
+}
+
+// ErrorCode returns the Reg.ru error code of an API error, or an empty string
+func ErrorCode(err error) string {
+	var codedErr *CodedError
+	if errors.As(err, &codedErr) {
+		return codedErr.Code
+	}
+	return ""
+}
+
+// transientError is a request failure unrelated to the request's content, such as a refused
+// connection or a 5xx response
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string {
+	return e.err.Error()
+}
+
+func (e *transientError) Unwrap() error {
+	return e.err
+}
+
+// IsTransientError reports whether a request failed for a reason unrelated to its content, so
+// that repeating it may succeed: the connection couldn't be established, or the API answered
+// with a 5xx status or a rate limit error. A request that timed out waiting for the response
+// is not transient, as it may have been applied.
+func IsTransientError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var transient *transientError
+	return errors.As(err, &transient) || IsRateLimitError(err)
+}
+
+// isConnectionError reports whether a request failed before reaching the API
+func isConnectionError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
+// UserAgent identifies the provider and its version in API requests, so that Reg.ru support can
+// tell its traffic apart, e.g. when investigating rate limits
+var UserAgent = "terraform-provider-regru/" + version.Full()
+
+// DefaultBaseURL is the address of the Reg.ru API used when no other is configured
+const DefaultBaseURL = "https://api.reg.ru/api/regru2"
+
+// Defaults for retrying requests rejected by the API rate limit. The delay between retries
+// doubles on each attempt but never exceeds MaxRateLimitDelay.
+const (
+	DefaultRateLimitRetries = 5
+	DefaultRateLimitDelay   = time.Second
+	MaxRateLimitDelay       = 30 * time.Second
+)
+
+// DefaultRequestTimeout is the default limit on the duration of a single API request,
+// so that a hung endpoint cannot block an apply forever
+const DefaultRequestTimeout = 60 * time.Second
+
+// DefaultMaxResponseSize is the default limit on the size of an API response body
+const DefaultMaxResponseSize = 4 << 20
+
+// newHTTPClient creates the HTTP client shared by all requests of a client, keeping enough
+// idle connections to the API host for concurrent requests to reuse them
+func newHTTPClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = 100
+	transport.MaxIdleConnsPerHost = 16
+	transport.IdleConnTimeout = 90 * time.Second
+
+	return &http.Client{Transport: transport, Timeout: DefaultRequestTimeout}
+}
+
+// NewClient создает новый экземпляр клиента. An empty baseURL selects DefaultBaseURL.
+func NewClient(username, password, baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
 	return &Client{
 		Username: username,
 		Password: password,
-		BaseURL:  "https://api.reg.ru/api/regru2",
+		BaseURL:  strings.TrimRight(baseURL, "/"),
+
+		RateLimitRetries: DefaultRateLimitRetries,
+		RateLimitDelay:   DefaultRateLimitDelay,
+		MaxResponseSize:  DefaultMaxResponseSize,
+		HTTPClient:       newHTTPClient(),
+
+		limiter:      newRequestLimiter(DefaultRequestsPerSecond),
+		requestCount: &atomic.Int64{},
+	}
+}
+
+// SetEndpoint overrides the add endpoint used for a record type
+func (c *Client) SetEndpoint(recordType, endpoint string) {
+	if c.Endpoints == nil {
+		c.Endpoints = make(map[string]string)
 	}
+	c.Endpoints[recordType] = endpoint
+}
+
+// endpointFor returns the add endpoint for a record type, honoring any override
+func (c *Client) endpointFor(recordType string) string {
+	if endpoint, ok := c.Endpoints[recordType]; ok && endpoint != "" {
+		return endpoint
+	}
+	if endpoint, ok := DefaultEndpoints[recordType]; ok {
+		return endpoint
+	}
+	return DefaultEndpoints["TXT"]
+}
+
+// DefaultSubdomainParam is the parameter carrying the record name, used by all known zone endpoints
+const DefaultSubdomainParam = "subdomain"
+
+// SetSubdomainParam overrides the name of the subdomain parameter sent to an endpoint
+func (c *Client) SetSubdomainParam(endpoint, name string) {
+	if c.SubdomainParams == nil {
+		c.SubdomainParams = make(map[string]string)
+	}
+	c.SubdomainParams[endpoint] = name
+}
+
+// applySubdomainParam renames the subdomain parameter for endpoints expecting another name
+func (c *Client) applySubdomainParam(endpoint string, params url.Values) {
+	name, ok := c.SubdomainParams[endpoint]
+	if !ok || name == "" || name == DefaultSubdomainParam {
+		return
+	}
+	if values, ok := params[DefaultSubdomainParam]; ok {
+		params.Del(DefaultSubdomainParam)
+		params[name] = values
+	}
+}
+
+// RequestCount returns the number of API requests made by the client and its copies
+func (c *Client) RequestCount() int64 {
+	if c.requestCount == nil {
+		return 0
+	}
+	return c.requestCount.Load()
+}
+
+// SetMaxConcurrentRequests limits the number of API requests that may be in flight at once.
+// A value of zero or less removes the limit.
+func (c *Client) SetMaxConcurrentRequests(limit int) {
+	if limit <= 0 {
+		c.requestSlots = nil
+		return
+	}
+	c.requestSlots = make(chan struct{}, limit)
+}
+
+// acquireRequestSlot blocks until a request slot is available and returns its release function.
+// It gives up with an error when the client's context is cancelled while waiting.
+func (c *Client) acquireRequestSlot() (func(), error) {
+	slots := c.requestSlots
+	if slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-c.context().Done():
+		return nil, c.context().Err()
+	}
+}
+
+// IsRateLimitError reports whether a request was rejected by the API rate limit, so that
+// repeating it after a pause may succeed
+func IsRateLimitError(err error) bool {
+	switch ErrorCode(err) {
+	case "IP_EXCEEDED_ALLOWED_CONNECTION_RATE", "RATE_LIMIT_EXCEEDED":
+		return true
+	default:
+		return false
+	}
+}
+
+// withRetry repeats a request while the API rejects it for exceeding the rate limit, backing
+// off exponentially. A request that failed transiently may have been applied before the failure,
+// so it is only repeated when retryable is set and reports that sending it again is safe. Any
+// other error is returned immediately.
+func (c *Client) withRetry(endpoint string, request func() ([]byte, error), retryable func() bool) ([]byte, error) {
+	delay := c.RateLimitDelay
+	for attempt := 0; ; attempt++ {
+		body, err := request()
+		if err == nil || attempt >= c.RateLimitRetries {
+			return body, err
+		}
+		switch {
+		case IsRateLimitError(err):
+			log.Printf("[WARN] %s was rate limited (attempt %d of %d): %v", endpoint, attempt+1, c.RateLimitRetries+1, err)
+		case IsTransientError(err) && retryable != nil && retryable():
+			log.Printf("[WARN] %s failed transiently (attempt %d of %d): %v", endpoint, attempt+1, c.RateLimitRetries+1, err)
+		default:
+			return body, err
+		}
+
+		if delay > MaxRateLimitDelay {
+			delay = MaxRateLimitDelay
+		}
+		log.Printf("[DEBUG] Retrying %s in %s", endpoint, delay)
+		if err := c.wait(delay); err != nil {
+			return nil, err
+		}
+		delay *= 2
+	}
+}
+
+// wait pauses before a retry, returning early with an error when the client's context is cancelled
+func (c *Client) wait(delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-c.context().Done():
+		return c.context().Err()
+	}
+}
+
+// context returns the context the client's requests run in
+func (c *Client) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
 
 // formatHumanReadableError creates user-friendly error messages for common API errors
 func formatHumanReadableError(errorCode, errorText string, errorParams map[string]string) error {
+	return &CodedError{
+		Code: errorCode,
+		Err:  humanReadableError(errorCode, errorText, errorParams),
+	}
+}
+
+// isKnownErrorCode reports whether an error code has a dedicated user-friendly message
+func isKnownErrorCode(errorCode string) bool {
+	switch errorCode {
+	case "ACCESS_DENIED_FROM_IP", "IP_EXCEEDED_ALLOWED_CONNECTION_RATE", "INVALID_USERNAME_OR_PASSWORD",
+		"DOMAIN_NOT_FOUND", "RECORD_NOT_FOUND", "INVALID_RECORD_TYPE", "DUPLICATE_RECORD",
+		"INVALID_IP_ADDRESS", "RATE_LIMIT_EXCEEDED":
+		return true
+	default:
+		return false
+	}
+}
+
+// apiError builds the error for an API error code. In strict mode, unrecognized codes
+// carry the raw response so that new API error conditions are easy to diagnose.
+func (c *Client) apiError(errorCode, errorText string, errorParams map[string]string, body []byte) error {
+	err := formatHumanReadableError(errorCode, errorText, errorParams)
+	if c.StrictErrors && !isKnownErrorCode(errorCode) {
+		return &CodedError{
+			Code: errorCode,
+			Err:  fmt.Errorf("unrecognized API error code %q: %w\nRaw response: %s", errorCode, err, string(body)),
+		}
+	}
+	return err
+}
+
+// humanReadableError maps an API error code to a user-friendly message
+func humanReadableError(errorCode, errorText string, errorParams map[string]string) error {
 	// Handle specific error codes with user-friendly messages
 	switch errorCode {
 	case "ACCESS_DENIED_FROM_IP":
@@ -80,30 +427,99 @@ func formatHumanReadableError(errorCode, errorText string, errorParams map[strin
 
 // doRequest выполняет HTTP POST запрос с form-данными
 func (c *Client) doRequest(endpoint string, params url.Values) ([]byte, error) {
+	return c.doRetriedRequest(endpoint, params, nil)
+}
+
+// doRetriedRequest performs a request like doRequest, also repeating it after a transient
+// failure whenever retryable reports that doing so is safe
+func (c *Client) doRetriedRequest(endpoint string, params url.Values, retryable func() bool) ([]byte, error) {
+	// The record name is audited as given, before the parameter is renamed for the endpoint
+	subdomain := params.Get(DefaultSubdomainParam)
+	c.applySubdomainParam(endpoint, params)
+	body, err := c.withRetry(endpoint, func() ([]byte, error) {
+		return c.send(endpoint, params)
+	}, retryable)
+	c.audit(endpoint, subdomain, params, err)
+	return body, err
+}
+
+// send выполняет запрос к API и разбирает ошибки в ответе
+func (c *Client) send(endpoint string, params url.Values) ([]byte, error) {
 	// Добавляем логин и пароль в параметры
-	params.Add("username", c.Username)
-	params.Add("password", c.Password)
+	params.Set("username", c.Username)
+	params.Set("password", c.Password)
 
 	// Формируем URL
 	fullURL := fmt.Sprintf("%s/%s", c.BaseURL, endpoint)
 
 	log.Printf("[DEBUG] Making request to: %s", fullURL)
-	log.Printf("[DEBUG] Request params: %s", params.Encode())
+	log.Printf("[DEBUG] Request params: %s", sanitizeParams(params).Encode())
+
+	// Stay under the API connection rate limit instead of relying on retries
+	if c.limiter != nil {
+		if err := c.limiter.Wait(c.context()); err != nil {
+			return nil, err
+		}
+	}
+
+	// Ограничиваем количество одновременных запросов
+	release, err := c.acquireRequestSlot()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// Считаем запросы и соблюдаем лимит; запросы в очереди не считаются отправленными
+	if c.requestCount != nil {
+		count := c.requestCount.Add(1)
+		if c.MaxRequests > 0 && count > c.MaxRequests {
+			return nil, &CodedError{
+				Code: "REQUEST_LIMIT_REACHED",
+				Err:  fmt.Errorf("the limit of %d API requests for this run has been reached", c.MaxRequests),
+			}
+		}
+		log.Printf("[DEBUG] API request #%d", count)
+	}
 
 	// Выполняем POST запрос
-	resp, err := http.PostForm(fullURL, params)
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(c.context(), http.MethodPost, fullURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", UserAgent)
+
+	resp, err := httpClient.Do(req)
 	if err != nil {
+		if isConnectionError(err) {
+			return nil, &transientError{err: fmt.Errorf("failed to make request: %w", err)}
+		}
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	log.Printf("[DEBUG] Response status: %s", resp.Status)
 
-	// Читаем тело ответа
-	body, err := io.ReadAll(resp.Body)
+	// Читаем тело ответа, ограничивая его размер
+	limit := c.MaxResponseSize
+	if limit <= 0 {
+		limit = DefaultMaxResponseSize
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
+	if int64(len(body)) > limit {
+		// Retrying would only download the oversized body again
+		return nil, &CodedError{
+			Code: "RESPONSE_TOO_LARGE",
+			Err:  fmt.Errorf("API response from %s exceeds the maximum size of %d bytes", endpoint, limit),
+		}
+	}
 
 	log.Printf("[DEBUG] Response body: %s", string(body))
 
@@ -112,7 +528,7 @@ func (c *Client) doRequest(endpoint string, params url.Values) ([]byte, error) {
 	var directError APIError
 	if err := json.Unmarshal(body, &directError); err == nil {
 		if directError.Result == "error" {
-			return nil, formatHumanReadableError(directError.ErrorCode, directError.ErrorText, directError.ErrorParams)
+			return nil, c.apiError(directError.ErrorCode, directError.ErrorText, directError.ErrorParams, body)
 		}
 	}
 
@@ -125,7 +541,7 @@ func (c *Client) doRequest(endpoint string, params url.Values) ([]byte, error) {
 			if len(apiResp.Answer.Domains) > 0 {
 				domain := apiResp.Answer.Domains[0]
 				if domain.ErrorCode != "" {
-					return nil, formatHumanReadableError(domain.ErrorCode, domain.ErrorText, domain.ErrorParams)
+					return nil, c.apiError(domain.ErrorCode, domain.ErrorText, domain.ErrorParams, body)
 				}
 			}
 			return nil, fmt.Errorf("API error: overall result is error")
@@ -134,77 +550,151 @@ func (c *Client) doRequest(endpoint string, params url.Values) ([]byte, error) {
 		// Check if any domain has an error
 		for _, domain := range apiResp.Answer.Domains {
 			if domain.Result == "error" {
-				return nil, formatHumanReadableError(domain.ErrorCode, domain.ErrorText, domain.ErrorParams)
+				return nil, c.apiError(domain.ErrorCode, domain.ErrorText, domain.ErrorParams, body)
+			}
+
+			// In strict mode, an unrecognized error code fails even without an error result
+			if c.StrictErrors && domain.ErrorCode != "" && !isKnownErrorCode(domain.ErrorCode) {
+				return nil, c.apiError(domain.ErrorCode, domain.ErrorText, domain.ErrorParams, body)
 			}
 		}
 	}
 
+	// A failed request without an API error in its body, e.g. an HTML error page from a proxy
+	if resp.StatusCode >= 400 {
+		return nil, httpStatusError(resp, body)
+	}
+
 	return body, nil
 }
 
+// maxErrorBodyLength limits how much of an unexpected response body is included in an error
+const maxErrorBodyLength = 200
+
+// httpStatusError builds the error for a response with a 4xx or 5xx status. Status 429 is
+// reported as a rate limit error so that it is retried with backoff, and 5xx statuses as
+// transient errors; other client errors are not retried.
+func httpStatusError(resp *http.Response, body []byte) error {
+	excerpt := strings.TrimSpace(string(body))
+	if len(excerpt) > maxErrorBodyLength {
+		excerpt = excerpt[:maxErrorBodyLength] + "..."
+	}
+	err := fmt.Errorf("API returned HTTP %s: %s", resp.Status, excerpt)
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return &CodedError{Code: "RATE_LIMIT_EXCEEDED", Err: err}
+	case resp.StatusCode >= 500:
+		return &transientError{err: err}
+	default:
+		return &CodedError{Code: fmt.Sprintf("HTTP_%d", resp.StatusCode), Err: err}
+	}
+}
+
+// WithContext returns a copy of the client whose requests are cancelled with ctx
+func (c *Client) WithContext(ctx context.Context) *Client {
+	scoped := *c
+	scoped.ctx = ctx
+	return &scoped
+}
+
+// WithCredentials returns a copy of the client authenticating with other credentials
+func (c *Client) WithCredentials(username, password string) *Client {
+	scoped := *c
+	scoped.Username = username
+	scoped.Password = password
+	return &scoped
+}
+
+// normalizeSubdomain brings a record name into the form the API uses for subdomain, so that
+// add and remove requests refer to the same record: the apex is always "@", names are
+// lowercased and a trailing dot is dropped. Wildcards such as "*" and "*.sub" pass through.
+func normalizeSubdomain(subdomain string) string {
+	subdomain = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(subdomain), "."))
+	if subdomain == "" {
+		return "@"
+	}
+	return subdomain
+}
+
+// AddRecord adds a record of a simple type. MX and NS records take an optional priority;
+// SRV, CAA, SSHFP and TLSA records carry extra fields and must use their dedicated methods.
 func (c *Client) AddRecord(recordType, domainName, subdomain, value string, priority *int) ([]byte, error) {
+	return c.AddRecordWithTTL(recordType, domainName, subdomain, value, priority, nil)
+}
+
+// AddRecordWithTTL adds a record of a simple type like AddRecord, setting its TTL in seconds
+// when ttl is not nil. Without a TTL the record gets the zone default.
+func (c *Client) AddRecordWithTTL(recordType, domainName, subdomain, value string, priority, ttl *int) ([]byte, error) {
+	endpoint, params, err := c.recordParams(recordType, domainName, subdomain, value, priority)
+	if err != nil {
+		return nil, err
+	}
+
+	if ttl != nil {
+		params.Add("ttl", fmt.Sprintf("%d", *ttl))
+	}
+
+	// Выполнение запроса
+	return c.doRequest(endpoint, params)
+}
+
+// recordParams выбирает эндпоинт и параметры для добавления записи простого типа
+func (c *Client) recordParams(recordType, domainName, subdomain, value string, priority *int) (string, url.Values, error) {
 	// Параметры для запроса
 	params := url.Values{}
 	params.Add("domain_name", domainName)
-	params.Add("subdomain", subdomain)
+	params.Add("subdomain", normalizeSubdomain(subdomain))
 	params.Add("output_content_type", "plain")
 
 	// Выбор эндпоинта и параметров в зависимости от типа записи
 
-	var endpoint string
+	endpoint := c.endpointFor(recordType)
 	switch recordType {
-	case "A":
-		endpoint = "zone/add_alias"
-		params.Add("ipaddr", value)
-	case "AAAA":
-		endpoint = "zone/add_aaaa"
+	case "A", "AAAA":
 		params.Add("ipaddr", value)
 	case "CNAME":
-		endpoint = "zone/add_cname"
 		params.Add("canonical_name", value)
+	case "PTR":
+		params.Add("ptr_name", value)
+	case "DNAME":
+		params.Add("dname", value)
 	case "MX":
-		endpoint = "zone/add_mx"
+		// zone/add_mx only accepts a priority; MX records have no weight
 		params.Add("mail_server", value)
 		if priority != nil {
 			params.Add("priority", fmt.Sprintf("%d", *priority)) // Преобразование приоритета в строку
 		}
 	case "NS":
-		endpoint = "zone/add_ns"
 		params.Add("dns_server", value)
 		if priority != nil {
 			params.Add("priority", fmt.Sprintf("%d", *priority))
 		}
 	case "SRV":
-		endpoint = "zone/add_srv"
-		params.Add("target", value)
-		if priority != nil {
-			params.Add("priority", fmt.Sprintf("%d", *priority))
-		}
-		// Note: Weight and port will need to be added separately
-		// as the current function signature doesn't support them
+		// zone/add_srv requires weight and port, which this signature cannot carry
+		return "", nil, fmt.Errorf("SRV records must be added with AddSRVRecord")
 	case "CAA":
-		endpoint = "zone/add_caa"
-		params.Add("value", value)
-		// Note: Flag and tag will need to be added separately
-		// as the current function signature doesn't support them
-	case "TXT":
-		endpoint = "zone/add_txt"
-		params.Add("text", value)
+		// zone/add_caa requires flags and tag, which this signature cannot carry
+		return "", nil, fmt.Errorf("CAA records must be added with AddCAARecord")
+	case "SSHFP":
+		// zone/add_sshfp requires the algorithm and fingerprint type
+		return "", nil, fmt.Errorf("SSHFP records must be added with AddSSHFPRecord")
+	case "TLSA":
+		// zone/add_tlsa requires the usage, selector and matching type
+		return "", nil, fmt.Errorf("TLSA records must be added with AddTLSARecord")
 	default:
 		// Если тип записи не поддерживается, используем TXT как универсальный
-		endpoint = "zone/add_txt"
 		params.Add("text", value)
 	}
 
-	// Выполнение запроса
-	return c.doRequest(endpoint, params)
+	return endpoint, params, nil
 }
 
 // AddSRVRecord adds an SRV record with priority, weight, and port
 func (c *Client) AddSRVRecord(domainName, subdomain, target string, priority, weight, port *int) ([]byte, error) {
 	params := url.Values{}
 	params.Add("domain_name", domainName)
-	params.Add("subdomain", subdomain)
+	params.Add("subdomain", normalizeSubdomain(subdomain))
 	params.Add("output_content_type", "plain")
 	params.Add("target", target)
 
@@ -218,14 +708,14 @@ func (c *Client) AddSRVRecord(domainName, subdomain, target string, priority, we
 		params.Add("port", fmt.Sprintf("%d", *port))
 	}
 
-	return c.doRequest("zone/add_srv", params)
+	return c.doRequest(c.endpointFor("SRV"), params)
 }
 
 // AddCAARecord adds a CAA record with flag and tag
 func (c *Client) AddCAARecord(domainName, subdomain, value string, flag *int, tag *string) ([]byte, error) {
 	params := url.Values{}
 	params.Add("domain_name", domainName)
-	params.Add("subdomain", subdomain)
+	params.Add("subdomain", normalizeSubdomain(subdomain))
 	params.Add("output_content_type", "plain")
 	params.Add("value", value)
 
@@ -253,14 +743,67 @@ func (c *Client) AddCAARecord(domainName, subdomain, value string, flag *int, ta
 
 	log.Printf("[DEBUG] Final parameters: %v", params)
 
-	return c.doRequest("zone/add_caa", params)
+	// The record may have been added before a server error, so it is only added again when
+	// the zone doesn't hold it
+	return c.doRetriedRequest(c.endpointFor("CAA"), params, func() bool {
+		exists, err := c.hasCAARecord(domainName, subdomain, value, params.Get("flags"), params.Get("tag"))
+		if err != nil {
+			log.Printf("[WARN] Could not check whether the CAA record %s was added, not retrying: %v", value, err)
+			return false
+		}
+		return !exists
+	})
+}
+
+// hasCAARecord reports whether the zone holds the CAA record with the value, flag and tag
+func (c *Client) hasCAARecord(domainName, subdomain, value, flag, tag string) (bool, error) {
+	body, err := c.GetRecords(domainName)
+	if err != nil {
+		return false, err
+	}
+
+	var zone struct {
+		Answer struct {
+			Domains []struct {
+				Rrs []struct {
+					Subname string      `json:"subname"`
+					Rectype string      `json:"rectype"`
+					Content string      `json:"content"`
+					Flag    json.Number `json:"flag"`
+					Tag     string      `json:"tag"`
+				} `json:"rrs"`
+			} `json:"domains"`
+		} `json:"answer"`
+	}
+	if err := json.Unmarshal(body, &zone); err != nil {
+		return false, fmt.Errorf("failed to parse DNS records response: %w", err)
+	}
+
+	for _, domain := range zone.Answer.Domains {
+		for _, rr := range domain.Rrs {
+			if rr.Rectype != "CAA" || normalizeSubdomain(rr.Subname) != normalizeSubdomain(subdomain) {
+				continue
+			}
+			// The content is either the bare value or the whole record, e.g. 0 issue "letsencrypt.org"
+			fields := strings.Fields(rr.Content)
+			if len(fields) == 3 {
+				rr.Flag, rr.Tag, rr.Content = json.Number(fields[0]), fields[1], fields[2]
+			}
+			if strings.EqualFold(strings.Trim(rr.Content, "\""), value) &&
+				(rr.Flag == "" || rr.Flag.String() == flag) &&
+				(rr.Tag == "" || strings.EqualFold(rr.Tag, tag)) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
 }
 
 // RemoveCAARecord removes a CAA record with flag and tag
 func (c *Client) RemoveCAARecord(domainName, subdomain, value string, flag *int, tag *string) ([]byte, error) {
 	params := url.Values{}
 	params.Add("domain_name", domainName)
-	params.Add("subdomain", subdomain)
+	params.Add("subdomain", normalizeSubdomain(subdomain))
 	params.Add("output_content_type", "plain")
 	params.Add("record_type", "CAA")
 	params.Add("content", value)
@@ -281,15 +824,91 @@ func (c *Client) RemoveCAARecord(domainName, subdomain, value string, flag *int,
 		params.Add("tag", "issue")
 	}
 
-	// Use the generic remove_record endpoint
+	// Use the generic remove_record endpoint. Removing again is harmless: a record already
+	// removed is reported as not found.
+	return c.doRetriedRequest("zone/remove_record", params, func() bool { return true })
+}
+
+// AddSSHFPRecord adds an SSHFP record with the key algorithm and fingerprint type
+func (c *Client) AddSSHFPRecord(domainName, subdomain, fingerprint string, algorithm, fpType *int) ([]byte, error) {
+	params := url.Values{}
+	params.Add("domain_name", domainName)
+	params.Add("subdomain", normalizeSubdomain(subdomain))
+	params.Add("output_content_type", "plain")
+	params.Add("fingerprint", fingerprint)
+
+	if algorithm != nil {
+		params.Add("algorithm", fmt.Sprintf("%d", *algorithm))
+	}
+	if fpType != nil {
+		params.Add("fp_type", fmt.Sprintf("%d", *fpType))
+	}
+
+	return c.doRequest(c.endpointFor("SSHFP"), params)
+}
+
+// RemoveSSHFPRecord removes an SSHFP record with the key algorithm and fingerprint type
+func (c *Client) RemoveSSHFPRecord(domainName, subdomain, fingerprint string, algorithm, fpType *int) ([]byte, error) {
+	params := url.Values{}
+	params.Add("domain_name", domainName)
+	params.Add("subdomain", normalizeSubdomain(subdomain))
+	params.Add("output_content_type", "plain")
+	params.Add("record_type", "SSHFP")
+	params.Add("content", fingerprint)
+
+	if algorithm != nil {
+		params.Add("algorithm", fmt.Sprintf("%d", *algorithm))
+	}
+	if fpType != nil {
+		params.Add("fp_type", fmt.Sprintf("%d", *fpType))
+	}
+
 	return c.doRequest("zone/remove_record", params)
 }
 
+// AddTLSARecord adds a TLSA record with the certificate usage, selector, and matching type
+func (c *Client) AddTLSARecord(domainName, subdomain, certificate string, usage, selector, matchingType *int) ([]byte, error) {
+	params := url.Values{}
+	params.Add("domain_name", domainName)
+	params.Add("subdomain", normalizeSubdomain(subdomain))
+	params.Add("output_content_type", "plain")
+	params.Add("certificate", certificate)
+	addTLSAParams(params, usage, selector, matchingType)
+
+	return c.doRequest(c.endpointFor("TLSA"), params)
+}
+
+// RemoveTLSARecord removes a TLSA record with the certificate usage, selector, and matching type
+func (c *Client) RemoveTLSARecord(domainName, subdomain, certificate string, usage, selector, matchingType *int) ([]byte, error) {
+	params := url.Values{}
+	params.Add("domain_name", domainName)
+	params.Add("subdomain", normalizeSubdomain(subdomain))
+	params.Add("output_content_type", "plain")
+	params.Add("record_type", "TLSA")
+	params.Add("content", certificate)
+	addTLSAParams(params, usage, selector, matchingType)
+
+	return c.doRequest("zone/remove_record", params)
+}
+
+// addTLSAParams adds the numeric TLSA fields that are set
+func addTLSAParams(params url.Values, usage, selector, matchingType *int) {
+	if usage != nil {
+		params.Add("usage", fmt.Sprintf("%d", *usage))
+	}
+	if selector != nil {
+		params.Add("selector", fmt.Sprintf("%d", *selector))
+	}
+	if matchingType != nil {
+		params.Add("matching_type", fmt.Sprintf("%d", *matchingType))
+	}
+}
+
 // RemoveSRVRecord removes an SRV record with priority, weight, and port
 func (c *Client) RemoveSRVRecord(domainName, subdomain, target string, priority, weight, port *int) ([]byte, error) {
 	params := url.Values{}
 	params.Add("domain_name", domainName)
-	params.Add("subdomain", subdomain)
+	params.Add("subdomain", normalizeSubdomain(subdomain))
 	params.Add("output_content_type", "plain")
 	params.Add("record_type", "SRV")
 	params.Add("content", target)
@@ -312,7 +931,7 @@ func (c *Client) RemoveSRVRecord(domainName, subdomain, target string, priority,
 func (c *Client) RemoveRecord(domainName, subdomain, recordType, content string, priority *int) ([]byte, error) {
 	params := url.Values{}
 	params.Add("domain_name", domainName)
-	params.Add("subdomain", subdomain)
+	params.Add("subdomain", normalizeSubdomain(subdomain))
 	params.Add("record_type", recordType)
 	params.Add("content", content)
 	params.Add("output_content_type", "plain")
@@ -332,3 +951,11 @@ func (c *Client) GetRecords(domainName string) ([]byte, error) {
 
 	return c.doRequest("zone/get_resource_records", params)
 }
+
+// GetDomains получает список доменов аккаунта
+func (c *Client) GetDomains() ([]byte, error) {
+	params := url.Values{}
+	params.Add("servtype", "domain")
+
+	return c.doRequest("service/get_list", params)
+}
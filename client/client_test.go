@@ -0,0 +1,797 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"terraform-provider-regru/version"
+)
+
+// newTestClient returns a client of the API served by handler, without rate limiting and
+// with short retry delays
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c := NewClient("test", "secret", server.URL)
+	c.SetRequestsPerSecond(0)
+	c.RateLimitDelay = time.Millisecond
+	return c
+}
+
+// respond returns a handler answering every request with the status and body
+func respond(status int, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}
+}
+
+const successResponse = `{"result":"success","answer":{"domains":[{"dname":"example.com","result":"success"}]}}`
+
+// caaAPI returns a handler for a zone whose CAA adds fail with a server error the first time,
+// after adding the record when added is set. It counts the requests made to each endpoint.
+func caaAPI(added bool, requests map[string]int, mutex *sync.Mutex) http.HandlerFunc {
+	var stored bool
+	return func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		requests[r.URL.Path]++
+
+		switch r.URL.Path {
+		case "/zone/add_caa":
+			if requests[r.URL.Path] == 1 {
+				stored = added
+				http.Error(w, "bad gateway", http.StatusBadGateway)
+				return
+			}
+			stored = true
+			fmt.Fprint(w, successResponse)
+		case "/zone/get_resource_records":
+			rrs := "[]"
+			if stored {
+				rrs = `[{"subname":"@","rectype":"CAA","content":"letsencrypt.org","flag":0,"tag":"issue"}]`
+			}
+			fmt.Fprintf(w, `{"result":"success","answer":{"domains":[{"dname":"example.com","result":"success","rrs":%s}]}}`, rrs)
+		default:
+			http.NotFound(w, r)
+		}
+	}
+}
+
+func TestAddCAARecordRetriesServerErrors(t *testing.T) {
+	var mutex sync.Mutex
+	requests := make(map[string]int)
+	c := newTestClient(t, caaAPI(false, requests, &mutex))
+	var audited int
+	c.AuditHook = func(AuditEntry) { audited++ }
+
+	if _, err := c.AddCAARecord("example.com", "@", "letsencrypt.org", nil, nil); err != nil {
+		t.Fatalf("AddCAARecord: %v", err)
+	}
+	// The zone is checked before adding the record again
+	if got := requests["/zone/add_caa"]; got != 2 {
+		t.Errorf("add requests = %d, want 2", got)
+	}
+	if got := requests["/zone/get_resource_records"]; got != 1 {
+		t.Errorf("zone reads = %d, want 1", got)
+	}
+	if audited != 1 {
+		t.Errorf("audited calls = %d, want 1", audited)
+	}
+}
+
+func TestAddCAARecordNotRepeatedWhenApplied(t *testing.T) {
+	var mutex sync.Mutex
+	requests := make(map[string]int)
+	c := newTestClient(t, caaAPI(true, requests, &mutex))
+
+	// The record was added although the API answered with a server error
+	if _, err := c.AddCAARecord("example.com", "@", "letsencrypt.org", nil, nil); err == nil {
+		t.Fatal("AddCAARecord succeeded, want the server error")
+	}
+	if got := requests["/zone/add_caa"]; got != 1 {
+		t.Errorf("add requests = %d, want 1", got)
+	}
+}
+
+func TestRetriesBoundedByRateLimitRetries(t *testing.T) {
+	var requests atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+	c.RateLimitRetries = 2
+
+	if _, err := c.RemoveCAARecord("example.com", "@", "letsencrypt.org", nil, nil); err == nil {
+		t.Fatal("RemoveCAARecord succeeded, want the server error")
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("requests = %d, want 3", got)
+	}
+}
+
+func TestRateLimitedRequestsRetried(t *testing.T) {
+	for _, tc := range []struct {
+		code     string
+		requests int32
+	}{
+		{"IP_EXCEEDED_ALLOWED_CONNECTION_RATE", 3},
+		{"RATE_LIMIT_EXCEEDED", 3},
+		{"INVALID_IP_ADDRESS", 1},
+	} {
+		t.Run(tc.code, func(t *testing.T) {
+			var requests atomic.Int32
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if requests.Add(1) <= 2 {
+					fmt.Fprintf(w, `{"result":"error","error_code":%q,"error_text":"rejected"}`, tc.code)
+					return
+				}
+				fmt.Fprint(w, successResponse)
+			})
+
+			_, err := c.AddRecord("A", "example.com", "www", "192.0.2.1", nil)
+			if got := requests.Load(); got != tc.requests {
+				t.Errorf("requests = %d, want %d", got, tc.requests)
+			}
+			// Only rate limit errors are retried, other errors fail immediately
+			if rateLimited := tc.requests > 1; rateLimited != (err == nil) {
+				t.Errorf("AddRecord error = %v", err)
+			}
+		})
+	}
+}
+
+func TestAddCAARecordNotRetriedAfterResponseTimeout(t *testing.T) {
+	var requests atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		time.Sleep(100 * time.Millisecond)
+		fmt.Fprint(w, successResponse)
+	})
+	c.HTTPClient.Timeout = 20 * time.Millisecond
+
+	// The record may have been added, so adding it again could create a duplicate
+	_, err := c.AddCAARecord("example.com", "@", "letsencrypt.org", nil, nil)
+	if err == nil {
+		t.Fatal("AddCAARecord succeeded, want a timeout")
+	}
+	if IsTransientError(err) {
+		t.Errorf("timeout reported as transient: %v", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestTransientErrors(t *testing.T) {
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	for _, tc := range []struct {
+		name      string
+		handler   http.HandlerFunc
+		baseURL   string
+		transient bool
+	}{
+		{
+			name:      "connection refused",
+			baseURL:   closed.URL,
+			transient: true,
+		},
+		{
+			name:      "server error",
+			handler:   respond(http.StatusServiceUnavailable, "unavailable"),
+			transient: true,
+		},
+		{
+			name:      "too many requests",
+			handler:   respond(http.StatusTooManyRequests, "slow down"),
+			transient: true,
+		},
+		{
+			name:    "overall result is error",
+			handler: respond(http.StatusOK, `{"result":"error","answer":{"domains":[]}}`),
+		},
+		{
+			name:    "client error",
+			handler: respond(http.StatusNotFound, "not found"),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var c *Client
+			if tc.handler != nil {
+				c = newTestClient(t, tc.handler)
+			} else {
+				c = NewClient("test", "secret", tc.baseURL)
+				c.SetRequestsPerSecond(0)
+			}
+			c.RateLimitRetries = 0
+
+			_, err := c.GetRecords("example.com")
+			if err == nil {
+				t.Fatal("GetRecords succeeded, want an error")
+			}
+			if got := IsTransientError(err); got != tc.transient {
+				t.Errorf("IsTransientError(%v) = %v, want %v", err, got, tc.transient)
+			}
+		})
+	}
+}
+
+func TestHTTPStatusErrors(t *testing.T) {
+	page := "<html><body>" + strings.Repeat("Internal Server Error ", 50) + "</body></html>"
+
+	for _, tc := range []struct {
+		name      string
+		status    int
+		body      string
+		code      string
+		transient bool
+		requests  int32 // only rate limits are retried for requests not known to be safe to repeat
+	}{
+		{"server error page", http.StatusInternalServerError, page, "", true, 1},
+		{"too many requests", http.StatusTooManyRequests, "slow down", "RATE_LIMIT_EXCEEDED", true, 3},
+		{"client error", http.StatusForbidden, "forbidden", "HTTP_403", false, 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests atomic.Int32
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				w.WriteHeader(tc.status)
+				fmt.Fprint(w, tc.body)
+			})
+			c.RateLimitRetries = 2
+
+			_, err := c.GetRecords("example.com")
+			if err == nil {
+				t.Fatal("GetRecords succeeded, want an error")
+			}
+			if !strings.Contains(err.Error(), fmt.Sprint(tc.status)) {
+				t.Errorf("error %q does not include the status", err)
+			}
+			if len(err.Error()) > 400 {
+				t.Errorf("error of %d characters does not truncate the body", len(err.Error()))
+			}
+			if code := ErrorCode(err); code != tc.code {
+				t.Errorf("ErrorCode = %q, want %q", code, tc.code)
+			}
+			if transient := IsTransientError(err); transient != tc.transient {
+				t.Errorf("IsTransientError = %t, want %t", transient, tc.transient)
+			}
+			if got := requests.Load(); got != tc.requests {
+				t.Errorf("requests = %d, want %d", got, tc.requests)
+			}
+		})
+	}
+}
+
+func TestResponseSizeLimit(t *testing.T) {
+	padding := strings.Repeat(" ", 1024)
+	c := newTestClient(t, respond(http.StatusOK, successResponse+padding))
+
+	c.MaxResponseSize = int64(len(successResponse) + len(padding))
+	if _, err := c.GetRecords("example.com"); err != nil {
+		t.Fatalf("GetRecords of a response at the limit: %v", err)
+	}
+
+	c.MaxResponseSize = int64(len(successResponse))
+	_, err := c.GetRecords("example.com")
+	if code := ErrorCode(err); code != "RESPONSE_TOO_LARGE" {
+		t.Errorf("GetRecords of a response over the limit: err = %v, want RESPONSE_TOO_LARGE", err)
+	}
+}
+
+func TestMaxConcurrentRequests(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			highest := peak.Load()
+			if current <= highest || peak.CompareAndSwap(highest, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		fmt.Fprint(w, successResponse)
+	})
+	c.SetMaxConcurrentRequests(3)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.WithContext(context.Background()).GetRecords("example.com"); err != nil {
+				t.Errorf("GetRecords: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := peak.Load(); got > 3 || got < 2 {
+		t.Errorf("peak in-flight requests = %d, want 2 or 3", got)
+	}
+}
+
+func TestConnectionsReused(t *testing.T) {
+	var connections atomic.Int32
+	server := httptest.NewUnstartedServer(respond(http.StatusOK, successResponse))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+
+	c := NewClient("test", "secret", server.URL)
+	c.SetRequestsPerSecond(0)
+	for i := 0; i < 5; i++ {
+		// Copies of the client share its HTTP client and so its idle connections
+		if _, err := c.WithContext(context.Background()).GetRecords("example.com"); err != nil {
+			t.Fatalf("GetRecords: %v", err)
+		}
+	}
+	if got := connections.Load(); got != 1 {
+		t.Errorf("connections opened = %d, want 1", got)
+	}
+}
+
+func TestQueuedRequestCancelled(t *testing.T) {
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-unblock
+		fmt.Fprint(w, successResponse)
+	})
+	c.SetMaxConcurrentRequests(1)
+
+	// A stuck request holds the only slot
+	done := make(chan error)
+	go func() {
+		_, err := c.GetRecords("example.com")
+		done <- err
+	}()
+	<-started
+
+	// A request waiting for the slot gives up when its operation times out
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.WithContext(ctx).GetRecords("example.com"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("queued GetRecords: err = %v, want the context deadline", err)
+	}
+	if got := c.RequestCount(); got != 1 {
+		t.Errorf("RequestCount = %d, want 1 as the queued request was never sent", got)
+	}
+
+	close(unblock)
+	if err := <-done; err != nil {
+		t.Errorf("stuck GetRecords: %v", err)
+	}
+}
+
+// recordForm returns a client that records the endpoint and form of the requests it makes
+func recordForm(t *testing.T) (*Client, *[]string, *url.Values) {
+	var endpoints []string
+	form := url.Values{}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		endpoints = append(endpoints, r.URL.Path)
+		form = r.PostForm
+		fmt.Fprint(w, successResponse)
+	})
+	return c, &endpoints, &form
+}
+
+func TestInFlightRequestCancelled(t *testing.T) {
+	unblock := make(chan struct{})
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-unblock:
+		case <-r.Context().Done():
+		}
+	})
+	defer close(unblock)
+
+	// Cancelling the operation aborts a request the API never answers
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := c.WithContext(ctx).GetRecords("example.com")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("GetRecords: err = %v, want the context cancellation", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("GetRecords returned after %s, want it to stop when cancelled", elapsed)
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	var userAgent string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.UserAgent()
+		fmt.Fprint(w, successResponse)
+	})
+
+	if _, err := c.GetRecords("example.com"); err != nil {
+		t.Fatalf("GetRecords: %v", err)
+	}
+	if want := "terraform-provider-regru/" + version.Full(); userAgent != want {
+		t.Errorf("User-Agent = %q, want %q", userAgent, want)
+	}
+}
+
+func TestRequestsPerSecond(t *testing.T) {
+	var requests atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		fmt.Fprint(w, successResponse)
+	})
+	c.SetRequestsPerSecond(10)
+
+	// A burst of up to the rate starts at once, the rest are spaced out
+	start := time.Now()
+	for i := 0; i < 12; i++ {
+		if _, err := c.GetRecords("example.com"); err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("12 requests at 10 per second took %s, want at least 150ms", elapsed)
+	}
+
+	// An operation cancelled while waiting for its turn makes no request
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.WithContext(ctx).GetRecords("example.com"); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetRecords: err = %v, want the context cancellation", err)
+	}
+	if got := requests.Load(); got != 12 {
+		t.Errorf("requests = %d, want 12", got)
+	}
+}
+
+func TestRequestsPerSecondCancelledWait(t *testing.T) {
+	c := newTestClient(t, respond(http.StatusOK, successResponse))
+	c.SetRequestsPerSecond(10)
+	for i := 0; i < 10; i++ {
+		if _, err := c.GetRecords("example.com"); err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+	}
+
+	// An operation cancelled while waiting gives its turn back, so the next request waits
+	// for one turn rather than two
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if _, err := c.WithContext(ctx).GetRecords("example.com"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetRecords: err = %v, want the context cancellation", err)
+	}
+	start := time.Now()
+	if _, err := c.GetRecords("example.com"); err != nil {
+		t.Fatalf("GetRecords: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("request after a cancelled wait took %s, want at most 150ms", elapsed)
+	}
+}
+
+func TestAddRecordParameters(t *testing.T) {
+	priority, weight, port, flag, tag := 10, 5, 5060, 128, "iodef"
+
+	for _, tc := range []struct {
+		name     string
+		add      func(c *Client) ([]byte, error)
+		endpoint string
+		want     url.Values
+	}{
+		{
+			name: "A",
+			add: func(c *Client) ([]byte, error) {
+				return c.AddRecord("A", "example.com", "www", "192.0.2.1", nil)
+			},
+			endpoint: "/zone/add_alias",
+			want:     url.Values{"subdomain": {"www"}, "ipaddr": {"192.0.2.1"}},
+		},
+		{
+			name: "A with TTL",
+			add: func(c *Client) ([]byte, error) {
+				ttl := 600
+				return c.AddRecordWithTTL("A", "example.com", "www", "192.0.2.1", nil, &ttl)
+			},
+			endpoint: "/zone/add_alias",
+			want:     url.Values{"subdomain": {"www"}, "ipaddr": {"192.0.2.1"}, "ttl": {"600"}},
+		},
+		{
+			name: "AAAA",
+			add: func(c *Client) ([]byte, error) {
+				return c.AddRecord("AAAA", "example.com", "www", "2001:db8::1", nil)
+			},
+			endpoint: "/zone/add_aaaa",
+			want:     url.Values{"subdomain": {"www"}, "ipaddr": {"2001:db8::1"}},
+		},
+		{
+			name: "CNAME",
+			add: func(c *Client) ([]byte, error) {
+				return c.AddRecord("CNAME", "example.com", "docs", "docs.example.net.", nil)
+			},
+			endpoint: "/zone/add_cname",
+			want:     url.Values{"subdomain": {"docs"}, "canonical_name": {"docs.example.net."}},
+		},
+		{
+			name: "TXT",
+			add: func(c *Client) ([]byte, error) {
+				return c.AddRecord("TXT", "example.com", "@", "v=spf1 -all", nil)
+			},
+			endpoint: "/zone/add_txt",
+			want:     url.Values{"subdomain": {"@"}, "text": {"v=spf1 -all"}},
+		},
+		{
+			name: "PTR",
+			add: func(c *Client) ([]byte, error) {
+				return c.AddRecord("PTR", "2.0.192.in-addr.arpa", "1", "host.example.com.", nil)
+			},
+			endpoint: "/zone/add_ptr",
+			want:     url.Values{"domain_name": {"2.0.192.in-addr.arpa"}, "subdomain": {"1"}, "ptr_name": {"host.example.com."}},
+		},
+		{
+			name: "DNAME",
+			add: func(c *Client) ([]byte, error) {
+				return c.AddRecord("DNAME", "example.com", "old", "example.net.", nil)
+			},
+			endpoint: "/zone/add_dname",
+			want:     url.Values{"subdomain": {"old"}, "dname": {"example.net."}},
+		},
+		{
+			name: "NS",
+			add: func(c *Client) ([]byte, error) {
+				return c.AddRecord("NS", "example.com", "dept", "ns1.example.net.", &priority)
+			},
+			endpoint: "/zone/add_ns",
+			want:     url.Values{"subdomain": {"dept"}, "dns_server": {"ns1.example.net."}, "priority": {"10"}},
+		},
+		{
+			name: "MX",
+			add: func(c *Client) ([]byte, error) {
+				return c.AddRecord("MX", "example.com", "@", "mx.example.com.", &priority)
+			},
+			endpoint: "/zone/add_mx",
+			want:     url.Values{"subdomain": {"@"}, "mail_server": {"mx.example.com."}, "priority": {"10"}},
+		},
+		{
+			name: "SRV",
+			add: func(c *Client) ([]byte, error) {
+				return c.AddSRVRecord("example.com", "_sip._tcp", "sip.example.com.", &priority, &weight, &port)
+			},
+			endpoint: "/zone/add_srv",
+			want:     url.Values{"subdomain": {"_sip._tcp"}, "target": {"sip.example.com."}, "priority": {"10"}, "weight": {"5"}, "port": {"5060"}},
+		},
+		{
+			name: "CAA",
+			add: func(c *Client) ([]byte, error) {
+				return c.AddCAARecord("example.com", "@", "mailto:security@example.com", &flag, &tag)
+			},
+			endpoint: "/zone/add_caa",
+			want:     url.Values{"subdomain": {"@"}, "value": {"mailto:security@example.com"}, "flags": {"128"}, "tag": {"iodef"}},
+		},
+		{
+			name: "CAA defaults",
+			add: func(c *Client) ([]byte, error) {
+				return c.AddCAARecord("example.com", "@", "letsencrypt.org", nil, nil)
+			},
+			endpoint: "/zone/add_caa",
+			want:     url.Values{"subdomain": {"@"}, "value": {"letsencrypt.org"}, "flags": {"0"}, "tag": {"issue"}},
+		},
+		{
+			name: "SSHFP",
+			add: func(c *Client) ([]byte, error) {
+				algorithm, fpType := 4, 2
+				return c.AddSSHFPRecord("example.com", "host", "abcdef", &algorithm, &fpType)
+			},
+			endpoint: "/zone/add_sshfp",
+			want:     url.Values{"subdomain": {"host"}, "fingerprint": {"abcdef"}, "algorithm": {"4"}, "fp_type": {"2"}},
+		},
+		{
+			name: "TLSA",
+			add: func(c *Client) ([]byte, error) {
+				usage, selector, matchingType := 3, 1, 1
+				return c.AddTLSARecord("example.com", "_443._tcp", "abcdef", &usage, &selector, &matchingType)
+			},
+			endpoint: "/zone/add_tlsa",
+			want:     url.Values{"subdomain": {"_443._tcp"}, "certificate": {"abcdef"}, "usage": {"3"}, "selector": {"1"}, "matching_type": {"1"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, endpoints, form := recordForm(t)
+			if _, err := tc.add(c); err != nil {
+				t.Fatalf("add: %v", err)
+			}
+			if !reflect.DeepEqual(*endpoints, []string{tc.endpoint}) {
+				t.Errorf("endpoints = %q, want %s", *endpoints, tc.endpoint)
+			}
+
+			// Exactly these parameters are sent, besides the common ones
+			want := url.Values{
+				"username":            {"test"},
+				"password":            {"secret"},
+				"domain_name":         {"example.com"},
+				"output_content_type": {"plain"},
+			}
+			for key, values := range tc.want {
+				want[key] = values
+			}
+			if !reflect.DeepEqual(*form, want) {
+				t.Errorf("form = %v, want %v", *form, want)
+			}
+		})
+	}
+}
+
+func TestRemoveRecordSubdomainMatchesAdd(t *testing.T) {
+	for _, tc := range []struct {
+		name, subdomain string
+	}{
+		{"@", "@"},
+		{"", "@"},
+		{"*", "*"},
+		{"*.sub", "*.sub"},
+		{"*.Sub.", "*.sub"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// The API only removes a record named exactly as it was added
+			stored := map[string]bool{}
+			var sent []string
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				r.ParseForm()
+				subdomain := r.PostForm.Get("subdomain")
+				sent = append(sent, subdomain)
+				if r.URL.Path == "/zone/remove_record" {
+					if !stored[subdomain] {
+						fmt.Fprint(w, `{"result":"success","answer":{"domains":[{"dname":"example.com","result":"error","error_code":"RR_NOT_FOUND"}]}}`)
+						return
+					}
+					delete(stored, subdomain)
+				} else {
+					stored[subdomain] = true
+				}
+				fmt.Fprint(w, successResponse)
+			})
+
+			if _, err := c.AddRecord("A", "example.com", tc.name, "192.0.2.1", nil); err != nil {
+				t.Fatalf("AddRecord: %v", err)
+			}
+			if _, err := c.RemoveRecord("example.com", tc.name, "A", "192.0.2.1", nil); err != nil {
+				t.Fatalf("RemoveRecord: %v", err)
+			}
+			if want := []string{tc.subdomain, tc.subdomain}; !reflect.DeepEqual(sent, want) {
+				t.Errorf("subdomain sent = %q, want %q", sent, want)
+			}
+			if len(stored) != 0 {
+				t.Errorf("records left = %v, want none", stored)
+			}
+		})
+	}
+}
+
+func TestSubdomainParamOverride(t *testing.T) {
+	c, endpoints, form := recordForm(t)
+	c.SetSubdomainParam("zone/add_srv", "service")
+
+	priority, weight, port := 10, 5, 5060
+	if _, err := c.AddSRVRecord("example.com", "_sip._tcp", "sip.example.com.", &priority, &weight, &port); err != nil {
+		t.Fatalf("AddSRVRecord: %v", err)
+	}
+	if got := form.Get("service"); got != "_sip._tcp" {
+		t.Errorf("service = %q, want _sip._tcp", got)
+	}
+	if _, ok := (*form)["subdomain"]; ok {
+		t.Errorf("subdomain sent along with the overridden parameter: %v", *form)
+	}
+
+	// Other endpoints keep the default parameter
+	if _, err := c.AddRecord("A", "example.com", "www", "192.0.2.1", nil); err != nil {
+		t.Fatalf("AddRecord: %v", err)
+	}
+	if got := form.Get("subdomain"); got != "www" {
+		t.Errorf("subdomain = %q, want www", got)
+	}
+	if want := []string{"/zone/add_srv", "/zone/add_alias"}; !reflect.DeepEqual(*endpoints, want) {
+		t.Errorf("endpoints = %q, want %q", *endpoints, want)
+	}
+}
+
+func TestAddRecordRejectsTypesWithExtraFields(t *testing.T) {
+	c, endpoints, _ := recordForm(t)
+	for _, recordType := range []string{"SRV", "CAA", "SSHFP", "TLSA"} {
+		if _, err := c.AddRecord(recordType, "example.com", "@", "value", nil); err == nil {
+			t.Errorf("AddRecord %s succeeded, want an error pointing at the dedicated method", recordType)
+		}
+	}
+	if len(*endpoints) != 0 {
+		t.Errorf("requests made = %q, want none", *endpoints)
+	}
+}
+
+func TestEndpointOverride(t *testing.T) {
+	var mutex sync.Mutex
+	var paths []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		paths = append(paths, r.URL.Path)
+		mutex.Unlock()
+		fmt.Fprint(w, successResponse)
+	})
+	c.SetEndpoint("A", "zone/add_ipv4")
+	c.SetEndpoint("CAA", "zone/add_caa_v2")
+
+	if _, err := c.AddRecord("A", "example.com", "www", "192.0.2.1", nil); err != nil {
+		t.Fatalf("AddRecord A: %v", err)
+	}
+	if _, err := c.AddRecord("AAAA", "example.com", "www", "2001:db8::1", nil); err != nil {
+		t.Fatalf("AddRecord AAAA: %v", err)
+	}
+	if _, err := c.AddCAARecord("example.com", "@", "letsencrypt.org", nil, nil); err != nil {
+		t.Fatalf("AddCAARecord: %v", err)
+	}
+
+	want := []string{"/zone/add_ipv4", "/zone/add_aaaa", "/zone/add_caa_v2"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("requested paths = %q, want %q", paths, want)
+	}
+}
+
+func TestStrictErrors(t *testing.T) {
+	const (
+		failed  = `{"result":"error","error_code":"NEW_CONDITION","error_text":"Something new"}`
+		flagged = `{"result":"success","answer":{"domains":[{"dname":"example.com","result":"success","error_code":"NEW_CONDITION"}]}}`
+		known   = `{"result":"error","error_code":"DOMAIN_NOT_FOUND","error_text":"Domain not found"}`
+	)
+
+	for _, tc := range []struct {
+		name    string
+		body    string
+		strict  bool
+		wantErr bool
+		wantRaw bool
+	}{
+		{name: "unknown code", body: failed, wantErr: true},
+		{name: "unknown code in strict mode", body: failed, strict: true, wantErr: true, wantRaw: true},
+		{name: "unknown code on success", body: flagged},
+		{name: "unknown code on success in strict mode", body: flagged, strict: true, wantErr: true, wantRaw: true},
+		{name: "known code in strict mode", body: known, strict: true, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newTestClient(t, respond(http.StatusOK, tc.body))
+			c.StrictErrors = tc.strict
+
+			_, err := c.GetRecords("example.com")
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err = %v, want an error: %v", err, tc.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			if raw := strings.Contains(err.Error(), "Raw response: "+tc.body); raw != tc.wantRaw {
+				t.Errorf("error %q carries the raw response: %v, want %v", err, raw, tc.wantRaw)
+			}
+			if tc.wantRaw && ErrorCode(err) != "NEW_CONDITION" {
+				t.Errorf("error code = %q, want NEW_CONDITION", ErrorCode(err))
+			}
+		})
+	}
+}
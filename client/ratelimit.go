@@ -0,0 +1,28 @@
+package client
+
+import (
+	"math"
+
+	"golang.org/x/time/rate"
+)
+
+// DefaultRequestsPerSecond is the default request rate, high enough not to slow down small
+// configurations while smoothing the bursts of large applies
+const DefaultRequestsPerSecond = 5
+
+// newRequestLimiter creates a token bucket limiter allowing requestsPerSecond requests per
+// second. Up to a second's worth of requests may start at once after a quiet period.
+func newRequestLimiter(requestsPerSecond float64) *rate.Limiter {
+	burst := int(math.Max(1, math.Ceil(requestsPerSecond)))
+	return rate.NewLimiter(rate.Limit(requestsPerSecond), burst)
+}
+
+// SetRequestsPerSecond limits the rate at which the client and its copies start API requests.
+// A value of zero or less removes the limit.
+func (c *Client) SetRequestsPerSecond(requestsPerSecond float64) {
+	if requestsPerSecond <= 0 {
+		c.limiter = nil
+		return
+	}
+	c.limiter = newRequestLimiter(requestsPerSecond)
+}
@@ -2,7 +2,10 @@ module terraform-provider-regru
 
 go 1.22.4
 
-require github.com/hashicorp/terraform-plugin-sdk/v2 v2.36.0
+require (
+	github.com/hashicorp/terraform-plugin-sdk/v2 v2.36.0
+	golang.org/x/time v0.10.0
+)
 
 require (
 	github.com/agext/levenshtein v1.2.2 // indirect
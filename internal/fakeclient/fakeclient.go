@@ -0,0 +1,343 @@
+// Package fakeclient provides an in-memory API client for exercising strategies and resources
+// without HTTP. Only tests import it, so it is not built into the provider.
+package fakeclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"terraform-provider-regru/resource/base"
+)
+
+// Client is an in-memory implementation of base.CachedClientInterface. It keeps the records of
+// each zone and answers reads in the shape of the API responses, so strategies parse them
+// exactly as they parse real ones.
+type Client struct {
+	mutex    sync.Mutex
+	zones    map[string][]base.DNSRecord
+	registry *base.RecordRegistry
+
+	// Calls lists the write operations performed, e.g. "add A www 192.0.2.1"
+	Calls []string
+}
+
+// New creates a fake client holding the given zones, all without records
+func New(zones ...string) *Client {
+	f := &Client{
+		zones:    make(map[string][]base.DNSRecord),
+		registry: base.NewRecordRegistry(),
+	}
+	for _, zone := range zones {
+		f.AddZone(zone)
+	}
+	return f
+}
+
+// AddZone adds an empty zone, keeping the records of a zone that already exists
+func (f *Client) AddZone(zone string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	key := fakeZoneKey(zone)
+	if _, ok := f.zones[key]; !ok {
+		f.zones[key] = []base.DNSRecord{}
+	}
+}
+
+// Records returns a copy of the records held for a zone
+func (f *Client) Records(zone string) []base.DNSRecord {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]base.DNSRecord(nil), f.zones[fakeZoneKey(zone)]...)
+}
+
+// SetRecords replaces the records held for a zone, e.g. to simulate changes made outside Terraform
+func (f *Client) SetRecords(zone string, records []base.DNSRecord) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.zones[fakeZoneKey(zone)] = append([]base.DNSRecord(nil), records...)
+}
+
+// NewRun forgets the records registered and written so far, as a new provider run would
+func (f *Client) NewRun() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.registry = base.NewRecordRegistry()
+}
+
+// RecordRegistry returns the record registry of the fake client
+func (f *Client) RecordRegistry() *base.RecordRegistry {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.registry
+}
+
+// AddRecord adds a record of a simple type
+func (f *Client) AddRecord(recordType, domainName, subdomain, value string, priority *int) ([]byte, error) {
+	return f.AddRecordWithTTL(recordType, domainName, subdomain, value, priority, nil)
+}
+
+// AddRecordWithTTL adds a record of a simple type with the given TTL
+func (f *Client) AddRecordWithTTL(recordType, domainName, subdomain, value string, priority, ttl *int) ([]byte, error) {
+	record := base.DNSRecord{Rectype: recordType, Content: value}
+	if priority != nil {
+		record.Prio = *priority
+	}
+	if ttl != nil {
+		record.TTL = *ttl
+	}
+	return f.add(domainName, subdomain, record)
+}
+
+// RemoveRecord removes the records of the type matching the content and, when given, the priority
+func (f *Client) RemoveRecord(domainName, subdomain, recordType, content string, priority *int) ([]byte, error) {
+	return f.remove(domainName, subdomain, recordType, func(rr base.DNSRecord) bool {
+		return base.ComparableContent(rr.Content) == base.ComparableContent(content) && (priority == nil || rr.Prio == *priority)
+	})
+}
+
+// GetRecords returns the records of a zone in the shape of the API response
+func (f *Client) GetRecords(domainName string) ([]byte, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	records, ok := f.zones[fakeZoneKey(domainName)]
+	if !ok {
+		return json.Marshal(map[string]interface{}{
+			"result": "success",
+			"answer": map[string]interface{}{"domains": []interface{}{}},
+		})
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"result": "success",
+		"answer": map[string]interface{}{
+			"domains": []interface{}{
+				map[string]interface{}{
+					"dname":  fakeZoneKey(domainName),
+					"result": "success",
+					"rrs":    records,
+				},
+			},
+		},
+	})
+}
+
+// AddSRVRecord adds an SRV record
+func (f *Client) AddSRVRecord(domainName, subdomain, target string, priority, weight, port *int) ([]byte, error) {
+	record := base.DNSRecord{Rectype: "SRV", Content: target}
+	if priority != nil {
+		record.Prio = *priority
+	}
+	if weight != nil {
+		record.Weight = *weight
+	}
+	if port != nil {
+		record.Port = *port
+	}
+	return f.add(domainName, subdomain, record)
+}
+
+// RemoveSRVRecord removes the SRV records matching the target and the given numeric fields
+func (f *Client) RemoveSRVRecord(domainName, subdomain, target string, priority, weight, port *int) ([]byte, error) {
+	return f.remove(domainName, subdomain, "SRV", func(rr base.DNSRecord) bool {
+		return base.ComparableContent(rr.Content) == base.ComparableContent(target) &&
+			(priority == nil || rr.Prio == *priority) &&
+			(weight == nil || rr.Weight == *weight) &&
+			(port == nil || rr.Port == *port)
+	})
+}
+
+// AddCAARecord adds a CAA record
+func (f *Client) AddCAARecord(domainName, subdomain, value string, flag *int, tag *string) ([]byte, error) {
+	record := base.DNSRecord{Rectype: "CAA", Content: value, Tag: "issue"}
+	if flag != nil {
+		record.Flag = *flag
+	}
+	if tag != nil {
+		record.Tag = *tag
+	}
+	return f.add(domainName, subdomain, record)
+}
+
+// RemoveCAARecord removes the CAA records matching the value and the given flag and tag
+func (f *Client) RemoveCAARecord(domainName, subdomain, value string, flag *int, tag *string) ([]byte, error) {
+	return f.remove(domainName, subdomain, "CAA", func(rr base.DNSRecord) bool {
+		return base.ComparableContent(rr.Content) == base.ComparableContent(value) &&
+			(flag == nil || rr.Flag == *flag) &&
+			(tag == nil || strings.EqualFold(rr.Tag, *tag))
+	})
+}
+
+// AddSSHFPRecord adds an SSHFP record, storing its content in zone-file notation
+func (f *Client) AddSSHFPRecord(domainName, subdomain, fingerprint string, algorithm, fpType *int) ([]byte, error) {
+	record := base.Record{Value: fingerprint}
+	if algorithm != nil {
+		record.Algorithm = *algorithm
+	}
+	if fpType != nil {
+		record.FpType = *fpType
+	}
+	return f.add(domainName, subdomain, base.DNSRecord{Rectype: "SSHFP", Content: record.Format("SSHFP")})
+}
+
+// RemoveSSHFPRecord removes the SSHFP records matching the fingerprint and the given algorithm and type
+func (f *Client) RemoveSSHFPRecord(domainName, subdomain, fingerprint string, algorithm, fpType *int) ([]byte, error) {
+	return f.remove(domainName, subdomain, "SSHFP", func(rr base.DNSRecord) bool {
+		record := base.ParseSSHFPContent(rr)
+		return strings.EqualFold(record.Value, fingerprint) &&
+			(algorithm == nil || record.Algorithm == *algorithm) &&
+			(fpType == nil || record.FpType == *fpType)
+	})
+}
+
+// AddTLSARecord adds a TLSA record, storing its content in zone-file notation
+func (f *Client) AddTLSARecord(domainName, subdomain, certificate string, usage, selector, matchingType *int) ([]byte, error) {
+	record := base.Record{Value: certificate}
+	if usage != nil {
+		record.Usage = *usage
+	}
+	if selector != nil {
+		record.Selector = *selector
+	}
+	if matchingType != nil {
+		record.MatchingType = *matchingType
+	}
+	return f.add(domainName, subdomain, base.DNSRecord{Rectype: "TLSA", Content: record.Format("TLSA")})
+}
+
+// RemoveTLSARecord removes the TLSA records matching the certificate and the given numeric fields
+func (f *Client) RemoveTLSARecord(domainName, subdomain, certificate string, usage, selector, matchingType *int) ([]byte, error) {
+	return f.remove(domainName, subdomain, "TLSA", func(rr base.DNSRecord) bool {
+		record := base.ParseTLSAContent(rr)
+		return strings.EqualFold(record.Value, certificate) &&
+			(usage == nil || record.Usage == *usage) &&
+			(selector == nil || record.Selector == *selector) &&
+			(matchingType == nil || record.MatchingType == *matchingType)
+	})
+}
+
+// GetDomains lists the zones of the fake client in the shape of the API response
+func (f *Client) GetDomains() ([]byte, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	services := make([]interface{}, 0, len(f.zones))
+	for zone := range f.zones {
+		services = append(services, map[string]interface{}{"dname": zone, "servtype": "domain"})
+	}
+	return json.Marshal(map[string]interface{}{
+		"result": "success",
+		"answer": map[string]interface{}{"services": services},
+	})
+}
+
+// GetRecordsWithCache returns the records of a zone; the fake client has no cache
+func (f *Client) GetRecordsWithCache(domainName string) ([]byte, error) {
+	return f.GetRecords(domainName)
+}
+
+// GetRecordsByType returns the records of the type at zone/name
+func (f *Client) GetRecordsByType(zone, name, recordType string) ([]base.DNSRecord, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	records, ok := f.zones[fakeZoneKey(zone)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", base.ErrZoneNotFound, zone)
+	}
+
+	var matching []base.DNSRecord
+	for _, rr := range records {
+		if rr.Rectype == recordType && base.MatchesSubname(rr.Subname, name) {
+			matching = append(matching, rr)
+		}
+	}
+	return matching, nil
+}
+
+// GetRecord returns the record of the type at zone/name matching the content, or nil
+func (f *Client) GetRecord(zone, name, recordType, content string) (*base.DNSRecord, error) {
+	records, err := f.GetRecordsByType(zone, name, recordType)
+	if err != nil {
+		return nil, err
+	}
+	for i := range records {
+		if base.ComparableContent(records[i].Content) == base.ComparableContent(content) {
+			return &records[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// InvalidateZoneCache does nothing, the fake client has no cache
+func (f *Client) InvalidateZoneCache(zone string) {}
+
+// ClearZoneCache does nothing, the fake client has no cache
+func (f *Client) ClearZoneCache() {}
+
+// add stores a record at the subdomain of a zone
+func (f *Client) add(domainName, subdomain string, record base.DNSRecord) ([]byte, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	key := fakeZoneKey(domainName)
+	if _, ok := f.zones[key]; !ok {
+		return nil, fmt.Errorf("%w: %s", base.ErrZoneNotFound, domainName)
+	}
+
+	record.Subname = normalizeSubdomain(subdomain)
+	record.State = "A"
+	f.zones[key] = append(f.zones[key], record)
+	f.Calls = append(f.Calls, fmt.Sprintf("add %s %s %s", record.Rectype, record.Subname, record.Content))
+	return fakeSuccess(key)
+}
+
+// remove deletes the records of the type at the subdomain of a zone accepted by match
+func (f *Client) remove(domainName, subdomain, recordType string, match func(base.DNSRecord) bool) ([]byte, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	key := fakeZoneKey(domainName)
+	records, ok := f.zones[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", base.ErrZoneNotFound, domainName)
+	}
+
+	kept := records[:0]
+	for _, rr := range records {
+		if rr.Rectype == recordType && base.MatchesSubname(rr.Subname, subdomain) && match(rr) {
+			f.Calls = append(f.Calls, fmt.Sprintf("remove %s %s %s", rr.Rectype, rr.Subname, rr.Content))
+			continue
+		}
+		kept = append(kept, rr)
+	}
+	f.zones[key] = kept
+	return fakeSuccess(key)
+}
+
+// normalizeSubdomain maps the apex spellings to "@" and ignores letter case, like the API
+func normalizeSubdomain(subdomain string) string {
+	if subdomain == "" {
+		return "@"
+	}
+	return strings.ToLower(subdomain)
+}
+
+// fakeZoneKey normalizes a zone name for the fake client's zone map
+func fakeZoneKey(zone string) string {
+	return strings.ToLower(strings.TrimSuffix(zone, "."))
+}
+
+// fakeSuccess returns a successful write response for a zone
+func fakeSuccess(zone string) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"result": "success",
+		"answer": map[string]interface{}{
+			"domains": []interface{}{map[string]interface{}{"dname": zone, "result": "success"}},
+		},
+	})
+}
+
+var _ base.CachedClientInterface = (*Client)(nil)
@@ -0,0 +1,181 @@
+package provider
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"terraform-provider-regru/client"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ProviderConfig holds the provider settings decoded from the provider block
+type ProviderConfig struct {
+	Username string
+	Password string
+	APIURL   string
+
+	DisableTrailingDot bool
+	StrictErrors       bool
+
+	MaxResponseSize       int64
+	MaxAPIRequests        int64
+	MaxConcurrentRequests int
+	RequestsPerSecond     float64
+
+	RateLimitRetries int
+	RateLimitDelay   time.Duration
+	RequestTimeout   time.Duration
+
+	CacheTTL     time.Duration
+	CacheEnabled bool
+
+	// Endpoints maps upper-case record types to the API endpoint used to add them
+	Endpoints map[string]string
+	// SubdomainParams maps API endpoints to the name of their subdomain parameter
+	SubdomainParams map[string]string
+	AuditLog        string
+	SummaryFile     string
+
+	ZoneChangeCheck      string
+	FailFastMissingZones bool
+	AllowedTTLs          []int
+}
+
+// NewProviderConfig decodes the provider block into a config, applying defaults and validating it
+func NewProviderConfig(d *schema.ResourceData) (*ProviderConfig, error) {
+	config := &ProviderConfig{
+		Username:              d.Get("username").(string),
+		Password:              d.Get("password").(string),
+		APIURL:                strings.TrimRight(d.Get("api_url").(string), "/"),
+		DisableTrailingDot:    d.Get("disable_trailing_dot").(bool),
+		StrictErrors:          d.Get("strict_errors").(bool),
+		MaxResponseSize:       int64(d.Get("max_response_size").(int)),
+		MaxAPIRequests:        int64(d.Get("max_api_requests").(int)),
+		MaxConcurrentRequests: d.Get("max_concurrent_requests").(int),
+		RequestsPerSecond:     d.Get("requests_per_second").(float64),
+		RateLimitRetries:      d.Get("rate_limit_retries").(int),
+		CacheEnabled:          d.Get("cache_enabled").(bool),
+		Endpoints:             make(map[string]string),
+		SubdomainParams:       make(map[string]string),
+		AuditLog:              d.Get("audit_log").(string),
+		SummaryFile:           d.Get("summary_file").(string),
+		ZoneChangeCheck:       d.Get("zone_change_check").(string),
+		FailFastMissingZones:  d.Get("fail_fast_missing_zones").(bool),
+	}
+	if delay := d.Get("rate_limit_delay").(string); delay != "" {
+		parsed, err := time.ParseDuration(delay)
+		if err != nil {
+			return nil, fmt.Errorf("rate_limit_delay must be a duration such as \"1s\": %w", err)
+		}
+		config.RateLimitDelay = parsed
+	}
+	if timeout := d.Get("request_timeout").(string); timeout != "" {
+		parsed, err := time.ParseDuration(timeout)
+		if err != nil {
+			return nil, fmt.Errorf("request_timeout must be a duration such as \"30s\": %w", err)
+		}
+		config.RequestTimeout = parsed
+	} else {
+		config.RequestTimeout = client.DefaultRequestTimeout
+	}
+	if ttl := d.Get("cache_ttl").(string); ttl != "" {
+		parsed, err := time.ParseDuration(ttl)
+		if err != nil {
+			return nil, fmt.Errorf("cache_ttl must be a duration such as \"30s\": %w", err)
+		}
+		config.CacheTTL = parsed
+	} else {
+		config.CacheTTL = DefaultCacheTTL
+	}
+	for recordType, endpoint := range d.Get("endpoints").(map[string]interface{}) {
+		config.Endpoints[strings.ToUpper(recordType)] = endpoint.(string)
+	}
+	for endpoint, name := range d.Get("subdomain_params").(map[string]interface{}) {
+		config.SubdomainParams[strings.Trim(endpoint, "/")] = name.(string)
+	}
+	for _, ttl := range d.Get("allowed_ttls").([]interface{}) {
+		config.AllowedTTLs = append(config.AllowedTTLs, ttl.(int))
+	}
+
+	config.setDefaults()
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// setDefaults fills in settings left at their zero value
+func (c *ProviderConfig) setDefaults() {
+	if c.APIURL == "" {
+		c.APIURL = client.DefaultBaseURL
+	}
+	if c.RateLimitDelay == 0 {
+		c.RateLimitDelay = client.DefaultRateLimitDelay
+	}
+	if c.ZoneChangeCheck == "" {
+		c.ZoneChangeCheck = ZoneCheckOff
+	}
+}
+
+// Validate checks the settings for consistency
+func (c *ProviderConfig) Validate() error {
+	if c.Username == "" {
+		return fmt.Errorf("username must be set in the provider block or with the REGRU_USERNAME environment variable")
+	}
+	if c.Password == "" {
+		return fmt.Errorf("password must be set in the provider block or with the REGRU_PASSWORD environment variable")
+	}
+	if u, err := url.Parse(c.APIURL); err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("api_url must be an absolute URL, got %q", c.APIURL)
+	}
+	if c.MaxResponseSize < 0 {
+		return fmt.Errorf("max_response_size must not be negative, got %d", c.MaxResponseSize)
+	}
+	if c.MaxAPIRequests < 0 {
+		return fmt.Errorf("max_api_requests must not be negative, got %d", c.MaxAPIRequests)
+	}
+	if c.MaxConcurrentRequests < 0 {
+		return fmt.Errorf("max_concurrent_requests must not be negative, got %d", c.MaxConcurrentRequests)
+	}
+	if c.RequestsPerSecond < 0 {
+		return fmt.Errorf("requests_per_second must not be negative, got %g", c.RequestsPerSecond)
+	}
+	if c.RateLimitRetries < 0 {
+		return fmt.Errorf("rate_limit_retries must not be negative, got %d", c.RateLimitRetries)
+	}
+	if c.RequestTimeout < 0 {
+		return fmt.Errorf("request_timeout must not be negative, got %s", c.RequestTimeout)
+	}
+	if c.RateLimitDelay < 0 {
+		return fmt.Errorf("rate_limit_delay must not be negative, got %s", c.RateLimitDelay)
+	}
+	if c.CacheTTL <= 0 {
+		return fmt.Errorf("cache_ttl must be positive, got %s; set cache_enabled = false to disable caching", c.CacheTTL)
+	}
+
+	switch c.ZoneChangeCheck {
+	case ZoneCheckOff, ZoneCheckWarn, ZoneCheckFail:
+	default:
+		return fmt.Errorf("zone_change_check must be one of %s, %s or %s, got %q", ZoneCheckOff, ZoneCheckWarn, ZoneCheckFail, c.ZoneChangeCheck)
+	}
+
+	for _, ttl := range c.AllowedTTLs {
+		if ttl <= 0 {
+			return fmt.Errorf("allowed_ttls must contain positive values, got %d", ttl)
+		}
+	}
+	for recordType, endpoint := range c.Endpoints {
+		if endpoint == "" {
+			return fmt.Errorf("endpoint for %s records must not be empty", recordType)
+		}
+	}
+	for endpoint, name := range c.SubdomainParams {
+		if name == "" {
+			return fmt.Errorf("subdomain parameter name for %s must not be empty", endpoint)
+		}
+	}
+	return nil
+}
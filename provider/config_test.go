@@ -0,0 +1,154 @@
+package provider
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"terraform-provider-regru/client"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// providerData returns the provider block with the given settings
+func providerData(t *testing.T, raw map[string]interface{}) *schema.ResourceData {
+	t.Helper()
+	return schema.TestResourceDataRaw(t, Provider().Schema, raw)
+}
+
+func TestProviderConfigDefaults(t *testing.T) {
+	config, err := NewProviderConfig(providerData(t, map[string]interface{}{
+		"username": "test",
+		"password": "secret",
+	}))
+	if err != nil {
+		t.Fatalf("NewProviderConfig: %v", err)
+	}
+
+	want := &ProviderConfig{
+		Username:             "test",
+		Password:             "secret",
+		APIURL:               client.DefaultBaseURL,
+		MaxResponseSize:      client.DefaultMaxResponseSize,
+		RequestsPerSecond:    client.DefaultRequestsPerSecond,
+		RateLimitRetries:     client.DefaultRateLimitRetries,
+		RateLimitDelay:       client.DefaultRateLimitDelay,
+		RequestTimeout:       client.DefaultRequestTimeout,
+		CacheTTL:             DefaultCacheTTL,
+		CacheEnabled:         true,
+		Endpoints:            map[string]string{},
+		SubdomainParams:      map[string]string{},
+		ZoneChangeCheck:      ZoneCheckOff,
+		FailFastMissingZones: true,
+	}
+	if !reflect.DeepEqual(config, want) {
+		t.Errorf("config = %+v, want %+v", config, want)
+	}
+}
+
+func TestProviderConfigOverrides(t *testing.T) {
+	config, err := NewProviderConfig(providerData(t, map[string]interface{}{
+		"username":                "test",
+		"password":                "secret",
+		"api_url":                 "https://api.example.net/v2/",
+		"max_concurrent_requests": 4,
+		"rate_limit_retries":      2,
+		"rate_limit_delay":        "250ms",
+		"request_timeout":         "10s",
+		"cache_ttl":               "2m",
+		"cache_enabled":           false,
+		"endpoints":               map[string]interface{}{"caa": "zone/add_caa_v2"},
+		"subdomain_params":        map[string]interface{}{"/zone/add_srv/": "service"},
+		"zone_change_check":       ZoneCheckFail,
+		"allowed_ttls":            []interface{}{300, 3600},
+	}))
+	if err != nil {
+		t.Fatalf("NewProviderConfig: %v", err)
+	}
+
+	if config.APIURL != "https://api.example.net/v2" {
+		t.Errorf("APIURL = %q, want it without the trailing slash", config.APIURL)
+	}
+	if config.MaxConcurrentRequests != 4 || config.RateLimitRetries != 2 {
+		t.Errorf("MaxConcurrentRequests, RateLimitRetries = %d, %d, want 4, 2", config.MaxConcurrentRequests, config.RateLimitRetries)
+	}
+	if config.RateLimitDelay != 250*time.Millisecond || config.RequestTimeout != 10*time.Second || config.CacheTTL != 2*time.Minute {
+		t.Errorf("RateLimitDelay, RequestTimeout, CacheTTL = %s, %s, %s, want 250ms, 10s, 2m", config.RateLimitDelay, config.RequestTimeout, config.CacheTTL)
+	}
+	if config.CacheEnabled {
+		t.Error("CacheEnabled = true, want false")
+	}
+	if want := map[string]string{"CAA": "zone/add_caa_v2"}; !reflect.DeepEqual(config.Endpoints, want) {
+		t.Errorf("Endpoints = %v, want %v", config.Endpoints, want)
+	}
+	if want := map[string]string{"zone/add_srv": "service"}; !reflect.DeepEqual(config.SubdomainParams, want) {
+		t.Errorf("SubdomainParams = %v, want %v", config.SubdomainParams, want)
+	}
+	if config.ZoneChangeCheck != ZoneCheckFail {
+		t.Errorf("ZoneChangeCheck = %q, want %q", config.ZoneChangeCheck, ZoneCheckFail)
+	}
+	if want := []int{300, 3600}; !reflect.DeepEqual(config.AllowedTTLs, want) {
+		t.Errorf("AllowedTTLs = %v, want %v", config.AllowedTTLs, want)
+	}
+}
+
+func TestProviderConfigInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		raw  map[string]interface{}
+		want string
+	}{
+		{"duration", map[string]interface{}{"request_timeout": "soon"}, "request_timeout"},
+		{"cache TTL", map[string]interface{}{"cache_ttl": "0s"}, "cache_ttl must be positive"},
+		{"API URL", map[string]interface{}{"api_url": "api.example.net"}, "api_url must be an absolute URL"},
+		{"allowed TTL", map[string]interface{}{"allowed_ttls": []interface{}{300, -1}}, "allowed_ttls"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			raw := map[string]interface{}{"username": "test", "password": "secret"}
+			for key, value := range tc.raw {
+				raw[key] = value
+			}
+			_, err := NewProviderConfig(providerData(t, raw))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("NewProviderConfig error = %v, want one mentioning %s", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestProviderConfigCredentialsFromEnvironment(t *testing.T) {
+	t.Setenv("REGRU_USERNAME", "env-user")
+	t.Setenv("REGRU_PASSWORD", "env-secret")
+
+	config, err := NewProviderConfig(providerData(t, map[string]interface{}{}))
+	if err != nil {
+		t.Fatalf("NewProviderConfig: %v", err)
+	}
+	if config.Username != "env-user" || config.Password != "env-secret" {
+		t.Errorf("credentials = %q, %q, want them from the environment", config.Username, config.Password)
+	}
+
+	// Attributes take precedence over the environment
+	config, err = NewProviderConfig(providerData(t, map[string]interface{}{"username": "test", "password": "secret"}))
+	if err != nil {
+		t.Fatalf("NewProviderConfig: %v", err)
+	}
+	if config.Username != "test" || config.Password != "secret" {
+		t.Errorf("credentials = %q, %q, want the configured ones", config.Username, config.Password)
+	}
+}
+
+func TestProviderConfigMissingCredentials(t *testing.T) {
+	t.Setenv("REGRU_USERNAME", "")
+	t.Setenv("REGRU_PASSWORD", "")
+
+	_, err := NewProviderConfig(providerData(t, map[string]interface{}{"password": "secret"}))
+	if err == nil || !strings.Contains(err.Error(), "REGRU_USERNAME") {
+		t.Errorf("NewProviderConfig error = %v, want one mentioning REGRU_USERNAME", err)
+	}
+	_, err = NewProviderConfig(providerData(t, map[string]interface{}{"username": "test"}))
+	if err == nil || !strings.Contains(err.Error(), "REGRU_PASSWORD") {
+		t.Errorf("NewProviderConfig error = %v, want one mentioning REGRU_PASSWORD", err)
+	}
+}
@@ -1,33 +1,61 @@
 package provider
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"terraform-provider-regru/client"
+	"terraform-provider-regru/resource/base"
 	"terraform-provider-regru/resource/resources"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// DefaultCacheTTL is how long zone records are cached when cache_ttl is not set
+const DefaultCacheTTL = 30 * time.Second
+
+// NegativeCacheTTL is how long a transient failure to read a zone is cached, so that the
+// resources in the zone don't each repeat the failing request. It never exceeds cache_ttl.
+const NegativeCacheTTL = 5 * time.Second
+
 // Global cache manager that persists across all resource operations
 var (
 	globalZoneCache  = NewZoneCache()
 	globalCacheMutex sync.RWMutex
+
+	// globalZoneFetches collapses concurrent cold reads of a zone into one API request
+	globalZoneFetches = newZoneFetchGroup()
 )
 
 // ZoneCache provides caching for zone records to prevent multiple API calls
 type ZoneCache struct {
 	cache map[string]*ZoneCacheEntry
 	mutex sync.RWMutex
+
+	// hits and misses count lookups, for tuning cache_ttl; failureHits counts lookups answered
+	// with a cached failure, which are neither
+	hits        atomic.Int64
+	misses      atomic.Int64
+	failureHits atomic.Int64
 }
 
-// ZoneCacheEntry represents cached zone data
+// ZoneCacheEntry represents cached zone data, or a failed read of the zone
 type ZoneCacheEntry struct {
 	Data      []byte
 	Timestamp time.Time
 	TTL       time.Duration
+	Err       error // the error of a failed read, nil for zone data
 }
 
 // NewZoneCache creates a new zone cache
@@ -53,9 +81,13 @@ func (zc *ZoneCache) Get(zone string) ([]byte, bool) {
 
 	log.Printf("[DEBUG] ZoneCache.Get: zone %s found in cache, timestamp: %v, TTL: %v", zone, entry.Timestamp, entry.TTL)
 
+	// Failed reads are returned by Failure
+	if entry.Err != nil {
+		return nil, false
+	}
+
 	if time.Since(entry.Timestamp) > entry.TTL {
-		log.Printf("[DEBUG] ZoneCache.Get: zone %s cache expired, removing", zone)
-		delete(zc.cache, zone)
+		log.Printf("[DEBUG] ZoneCache.Get: zone %s cache expired", zone)
 		return nil, false
 	}
 
@@ -63,8 +95,12 @@ func (zc *ZoneCache) Get(zone string) ([]byte, bool) {
 	return entry.Data, true
 }
 
-// Set stores zone data in cache
-func (zc *ZoneCache) Set(zone string, data []byte) {
+// Set stores zone data in cache for the given TTL, or DefaultCacheTTL when it is zero
+func (zc *ZoneCache) Set(zone string, data []byte, ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+
 	zc.mutex.Lock()
 	defer zc.mutex.Unlock()
 
@@ -74,13 +110,69 @@ func (zc *ZoneCache) Set(zone string, data []byte) {
 	zc.cache[zone] = &ZoneCacheEntry{
 		Data:      data,
 		Timestamp: time.Now(),
-		TTL:       30 * time.Second, // Cache for 30 seconds
+		TTL:       ttl,
 	}
 
 	log.Printf("[DEBUG] ZoneCache.Set: zone %s stored in cache", zone)
 	log.Printf("[DEBUG] ZoneCache.Set: current cache contents: %v", zc.cache)
 }
 
+// SetFailure caches a failed read of the zone for the given TTL, replacing any zone data
+func (zc *ZoneCache) SetFailure(zone string, err error, ttl time.Duration) {
+	zc.mutex.Lock()
+	defer zc.mutex.Unlock()
+
+	log.Printf("[DEBUG] ZoneCache.SetFailure: caching failed read of zone %s for %v", zone, ttl)
+	zc.cache[zone] = &ZoneCacheEntry{
+		Timestamp: time.Now(),
+		TTL:       ttl,
+		Err:       err,
+	}
+}
+
+// Failure returns the error of a failed read of the zone cached within its TTL
+func (zc *ZoneCache) Failure(zone string) error {
+	zc.mutex.RLock()
+	defer zc.mutex.RUnlock()
+
+	entry, exists := zc.cache[zone]
+	if !exists || entry.Err == nil || time.Since(entry.Timestamp) > entry.TTL {
+		return nil
+	}
+	return entry.Err
+}
+
+// cacheLookup is the outcome of a zone cache lookup
+type cacheLookup int
+
+const (
+	cacheMiss cacheLookup = iota
+	cacheHit
+	cacheFailureHit
+)
+
+// record counts a lookup by its outcome and logs the running hit ratio
+func (zc *ZoneCache) record(lookup cacheLookup) {
+	switch lookup {
+	case cacheHit:
+		zc.hits.Add(1)
+	case cacheFailureHit:
+		zc.failureHits.Add(1)
+	default:
+		zc.misses.Add(1)
+	}
+	hits, misses, failureHits := zc.Stats()
+	total := hits + misses + failureHits
+	log.Printf("[DEBUG] Zone cache hit ratio: %d of %d lookups (%.0f%%), %d answered with a cached failure",
+		hits, total, 100*float64(hits)/float64(total), failureHits)
+}
+
+// Stats returns the number of lookups served with zone data from the cache, the number not found
+// in the cache and the number answered with a cached failure
+func (zc *ZoneCache) Stats() (hits, misses, failureHits int64) {
+	return zc.hits.Load(), zc.misses.Load(), zc.failureHits.Load()
+}
+
 // Invalidate removes a specific zone from cache
 func (zc *ZoneCache) Invalidate(zone string) {
 	zc.mutex.Lock()
@@ -95,35 +187,425 @@ func (zc *ZoneCache) Clear() {
 	zc.cache = make(map[string]*ZoneCacheEntry)
 }
 
+// zoneFetch is an in-flight read of a zone whose result is shared by every caller waiting on it
+type zoneFetch struct {
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+// zoneFetchGroup deduplicates concurrent reads of the same zone, so that resources refreshed in
+// parallel on a cold cache make one request per zone instead of one each
+type zoneFetchGroup struct {
+	mutex   sync.Mutex
+	fetches map[string]*zoneFetch
+}
+
+// newZoneFetchGroup creates an empty fetch group
+func newZoneFetchGroup() *zoneFetchGroup {
+	return &zoneFetchGroup{
+		fetches: make(map[string]*zoneFetch),
+	}
+}
+
+// do runs fetch for the key, unless a fetch for it is already in flight, in which case it
+// waits for that fetch and returns its result
+func (g *zoneFetchGroup) do(key string, fetch func() ([]byte, error)) ([]byte, error) {
+	g.mutex.Lock()
+	if inFlight, exists := g.fetches[key]; exists {
+		g.mutex.Unlock()
+		log.Printf("[DEBUG] Waiting for in-flight read of zone %s", key)
+		<-inFlight.done
+		return inFlight.data, inFlight.err
+	}
+
+	current := &zoneFetch{done: make(chan struct{})}
+	g.fetches[key] = current
+	g.mutex.Unlock()
+
+	current.data, current.err = fetch()
+	close(current.done)
+
+	g.mutex.Lock()
+	if g.fetches[key] == current {
+		delete(g.fetches, key)
+	}
+	g.mutex.Unlock()
+
+	return current.data, current.err
+}
+
+// forget stops later callers from joining the in-flight fetch of the key, e.g. because the zone
+// was written to since the fetch started
+func (g *zoneFetchGroup) forget(key string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	delete(g.fetches, key)
+}
+
 // CachedClient wraps the original client with caching capabilities
 type CachedClient struct {
 	*client.Client
+
+	// config holds the provider settings the client was configured with
+	config *ProviderConfig
+
+	// summary aggregates the records changed in the run, when a summary file is configured
+	summary *operationSummary
+
+	registry *base.RecordRegistry
+
+	// cacheScope separates cached zones of clients using resource-level credentials
+	cacheScope string
+
+	// cacheTTL is how long zones read by this client stay cached
+	cacheTTL time.Duration
+
+	// cacheDisabled makes every zone read go to the API, for debugging state drift
+	cacheDisabled bool
+
+	// missingZones remembers zones the API reported as not found, when non-nil
+	missingZones *missingZoneSet
+
+	// writeGuard detects zones modified outside this provider run between writes
+	writeGuard *zoneWriteGuard
+}
+
+// missingZoneSet records zones the API reported as not found, so that further operations
+// on them fail immediately instead of each resource repeating the failing request. Zones are
+// keyed by their cache key, as a zone missing for one set of credentials may exist for another.
+type missingZoneSet struct {
+	mutex sync.RWMutex
+	zones map[string]error
+}
+
+// check returns the error recorded for a missing zone under the key
+func (m *missingZoneSet) check(key, zone string) error {
+	if m == nil {
+		return nil
+	}
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if err, missing := m.zones[strings.ToLower(key)]; missing {
+		return fmt.Errorf("zone %s was not found in the account, skipping further requests: %w", zone, err)
+	}
+	return nil
+}
+
+// note records the zone under the key as missing when the error reports it as not found
+func (m *missingZoneSet) note(key string, err error) error {
+	if m == nil || client.ErrorCode(err) != "DOMAIN_NOT_FOUND" {
+		return err
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.zones[strings.ToLower(key)] = err
+	return err
+}
+
+// reset forgets all missing zones
+func (m *missingZoneSet) reset() {
+	if m == nil {
+		return
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.zones = make(map[string]error)
+}
+
+// Zone change check modes
+const (
+	ZoneCheckOff  = "off"
+	ZoneCheckWarn = "warn"
+	ZoneCheckFail = "fail"
+)
+
+// zoneWriteGuard serializes writes per zone and tracks the contents each zone is expected to
+// have, as a fingerprint of its records
+type zoneWriteGuard struct {
+	mode         string
+	mutex        sync.Mutex
+	locks        map[string]*sync.Mutex
+	fingerprints map[string]string
+}
+
+// newZoneWriteGuard creates a write guard using the given zone change check mode
+func newZoneWriteGuard(mode string) *zoneWriteGuard {
+	return &zoneWriteGuard{
+		mode:         mode,
+		locks:        make(map[string]*sync.Mutex),
+		fingerprints: make(map[string]string),
+	}
+}
+
+// zoneLock returns the lock serializing writes to the zone
+func (g *zoneWriteGuard) zoneLock(zone string) *sync.Mutex {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	lock, exists := g.locks[zone]
+	if !exists {
+		lock = &sync.Mutex{}
+		g.locks[zone] = lock
+	}
+	return lock
+}
+
+// observe records the fingerprint of zone records read by this run, unless the run already
+// saw the zone: the first read is what later writes are checked against
+func (g *zoneWriteGuard) observe(zone string, data []byte) {
+	if g == nil || g.mode == ZoneCheckOff {
+		return
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if _, seen := g.fingerprints[zone]; seen {
+		return
+	}
+	fingerprint, err := zoneFingerprint(zone, data)
+	if err != nil {
+		log.Printf("[DEBUG] Zone %s read by this run could not be fingerprinted: %v", zone, err)
+		return
+	}
+	g.fingerprints[zone] = fingerprint
+}
+
+// expectedFingerprint returns the fingerprint of the zone after this run's last write, or
+// when this run first read it
+func (g *zoneWriteGuard) expectedFingerprint(zone string) string {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	return g.fingerprints[zone]
+}
+
+// setFingerprint records the fingerprint of the zone after a write made by this run, or
+// forgets the zone when it is empty
+func (g *zoneWriteGuard) setFingerprint(zone, fingerprint string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if fingerprint == "" {
+		delete(g.fingerprints, zone)
+		return
+	}
+	g.fingerprints[zone] = fingerprint
+}
+
+// zoneFingerprint hashes the records of the zone in a get_resource_records response. Records
+// are hashed in a fixed order, so the fingerprint only changes when the records do.
+func zoneFingerprint(zone string, data []byte) (string, error) {
+	var zoneResponse base.DNSZoneResponse
+	if err := json.Unmarshal(data, &zoneResponse); err != nil {
+		return "", fmt.Errorf("failed to parse records of zone %s: %w", zone, err)
+	}
+
+	var records []string
+	for _, domain := range zoneResponse.Answer.Domains {
+		if !base.MatchesZone(domain.Dname, zone) {
+			continue
+		}
+		for _, rr := range domain.Rrs {
+			encoded, err := json.Marshal(rr)
+			if err != nil {
+				return "", err
+			}
+			records = append(records, string(encoded))
+		}
+	}
+	sort.Strings(records)
+
+	sum := sha256.Sum256([]byte(strings.Join(records, "\n")))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// currentFingerprint reads the zone from the API, bypassing the cache, and returns the
+// fingerprint of its records
+func (cc *CachedClient) currentFingerprint(zone string) (string, error) {
+	data, err := cc.GetRecords(zone)
+	if err != nil {
+		return "", err
+	}
+	return zoneFingerprint(zone, data)
+}
+
+// RecordRegistry returns the registry of record types managed in this provider run
+func (cc *CachedClient) RecordRegistry() *base.RecordRegistry {
+	return cc.registry
+}
+
+// derive returns a cached client sharing this client's state around another API client
+func (cc *CachedClient) derive(apiClient *client.Client) *CachedClient {
+	return &CachedClient{
+		Client:     apiClient,
+		config:     cc.config,
+		summary:    cc.summary,
+		registry:   cc.registry,
+		cacheScope: cc.cacheScope,
+		writeGuard: cc.writeGuard,
+
+		cacheTTL:      cc.cacheTTL,
+		cacheDisabled: cc.cacheDisabled,
+
+		missingZones: cc.missingZones,
+	}
+}
+
+// WithContext returns a cached client whose requests are cancelled with ctx
+func (cc *CachedClient) WithContext(ctx context.Context) base.CachedClientInterface {
+	return cc.derive(cc.Client.WithContext(ctx))
+}
+
+// WithCredentials returns a cached client authenticating with other credentials.
+// Its zones are cached separately from those read with any other credentials.
+func (cc *CachedClient) WithCredentials(username, password string) base.CachedClientInterface {
+	scoped := cc.derive(cc.Client.WithCredentials(username, password))
+	scoped.cacheScope = credentialsScope(username, password)
+	return scoped
+}
+
+// credentialsScope returns the cache scope of a set of credentials. The password is part of
+// it, as the same username may be given different passwords, but only its hash is kept.
+func credentialsScope(username, password string) string {
+	sum := sha256.Sum256([]byte(username + "\x00" + password))
+	return hex.EncodeToString(sum[:])
+}
+
+// cacheKey returns the key the zone is cached under for this client
+func (cc *CachedClient) cacheKey(zone string) string {
+	if cc.cacheScope == "" {
+		return zone
+	}
+	return cc.cacheScope + "|" + zone
+}
+
+// TrailingDotDisabled reports whether targets are sent without adding a trailing dot
+func (cc *CachedClient) TrailingDotDisabled() bool {
+	return cc.config != nil && cc.config.DisableTrailingDot
+}
+
+// checkZone returns the error recorded when the zone was found missing for this client's credentials
+func (cc *CachedClient) checkZone(zone string) error {
+	return cc.missingZones.check(cc.cacheKey(zone), zone)
+}
+
+// noteZone records the zone as missing for this client's credentials when the error reports it as not found
+func (cc *CachedClient) noteZone(zone string, err error) error {
+	return cc.missingZones.note(cc.cacheKey(zone), err)
+}
+
+// AllowedTTLs returns the TTL values records may use, or nil when any TTL is allowed
+func (cc *CachedClient) AllowedTTLs() []int {
+	if cc.config == nil {
+		return nil
+	}
+	return cc.config.AllowedTTLs
+}
+
+// GuardZoneWrite runs a batch of writes to the zone, checking beforehand that the zone's
+// records are still those this run last saw. Different records mean the zone was modified
+// concurrently, e.g. by a parallel pipeline, which is reported as a warning or an error. So is
+// a zone whose records can't be read for the check.
+func (cc *CachedClient) GuardZoneWrite(zone string, write func() error) error {
+	if cc.writeGuard == nil || cc.writeGuard.mode == ZoneCheckOff {
+		return write()
+	}
+
+	lock := cc.writeGuard.zoneLock(zone)
+	lock.Lock()
+	defer lock.Unlock()
+
+	current, err := cc.currentFingerprint(zone)
+	if err != nil {
+		if cc.writeGuard.mode == ZoneCheckFail {
+			return fmt.Errorf("cannot check zone %s for concurrent modifications, not writing to it: %w", zone, err)
+		}
+		log.Printf("[WARN] Cannot check zone %s for concurrent modifications: %v", zone, err)
+	} else if expected := cc.writeGuard.expectedFingerprint(zone); expected != "" && expected != current {
+		if cc.writeGuard.mode == ZoneCheckFail {
+			return fmt.Errorf("zone %s was modified concurrently since this run read it; refresh and apply again", zone)
+		}
+		log.Printf("[WARN] Zone %s was modified concurrently since this run read it", zone)
+	}
+
+	err = write()
+
+	after, fingerprintErr := cc.currentFingerprint(zone)
+	if fingerprintErr != nil {
+		log.Printf("[DEBUG] Zone %s could not be fingerprinted after writing: %v", zone, fingerprintErr)
+	}
+	cc.writeGuard.setFingerprint(zone, after)
+	return err
 }
 
 // GetRecordsWithCache gets zone records with caching using global cache
 func (cc *CachedClient) GetRecordsWithCache(zone string) ([]byte, error) {
 	log.Printf("[DEBUG] GetRecordsWithCache called for zone: %s", zone)
 
+	if err := cc.checkZone(zone); err != nil {
+		return nil, err
+	}
+
+	if cc.cacheDisabled {
+		log.Printf("[DEBUG] Zone cache disabled, making API call for zone: %s", zone)
+		data, err := cc.GetRecords(zone)
+		if err != nil {
+			return nil, cc.noteZone(zone, err)
+		}
+		cc.writeGuard.observe(zone, data)
+		return data, nil
+	}
+
 	// Try to get from global cache first
 	globalCacheMutex.RLock()
 	log.Printf("[DEBUG] Acquired global cache read lock for zone: %s", zone)
 
-	if cached, exists := globalZoneCache.Get(zone); exists {
+	if cached, exists := globalZoneCache.Get(cc.cacheKey(zone)); exists {
 		log.Printf("[DEBUG] GLOBAL CACHE HIT for zone %s, returning cached data", zone)
 		globalCacheMutex.RUnlock()
+		globalZoneCache.record(cacheHit)
+		cc.writeGuard.observe(zone, cached)
 		return cached, nil
 	}
+	if err := globalZoneCache.Failure(cc.cacheKey(zone)); err != nil {
+		log.Printf("[DEBUG] GLOBAL CACHE HIT for zone %s, returning cached failure", zone)
+		globalCacheMutex.RUnlock()
+		globalZoneCache.record(cacheFailureHit)
+		return nil, fmt.Errorf("reading zone %s failed moments ago, not retrying yet: %w", zone, err)
+	}
 
 	log.Printf("[DEBUG] GLOBAL CACHE MISS for zone %s, cache does not exist", zone)
 	globalCacheMutex.RUnlock()
+	globalZoneCache.record(cacheMiss)
 
+	// Concurrent misses for the zone share a single read
+	data, err := globalZoneFetches.do(cc.cacheKey(zone), func() ([]byte, error) {
+		return cc.fetchZone(zone)
+	})
+	if err != nil {
+		return nil, err
+	}
+	cc.writeGuard.observe(zone, data)
+	return data, nil
+}
+
+// fetchZone reads the zone from the API and stores the result in the global cache. The API
+// reports no SOA serial to revalidate an expired copy with, so zones are always read in full.
+func (cc *CachedClient) fetchZone(zone string) ([]byte, error) {
 	log.Printf("[DEBUG] Making API call for zone: %s", zone)
 
 	// If not in cache, fetch from API
 	data, err := cc.GetRecords(zone)
 	if err != nil {
 		log.Printf("[DEBUG] API call failed for zone %s: %v", zone, err)
-		return nil, err
+		// Only transient failures are cached; a cancelled operation says nothing about the zone
+		if client.IsTransientError(err) && !errors.Is(err, context.DeadlineExceeded) {
+			globalZoneCache.SetFailure(cc.cacheKey(zone), err, cc.negativeCacheTTL())
+		}
+		return nil, cc.noteZone(zone, err)
 	}
 
 	log.Printf("[DEBUG] API call successful for zone %s, storing in global cache", zone)
@@ -131,7 +613,7 @@ func (cc *CachedClient) GetRecordsWithCache(zone string) ([]byte, error) {
 	// Store in global cache
 	globalCacheMutex.Lock()
 	log.Printf("[DEBUG] Acquired global cache write lock for zone: %s", zone)
-	globalZoneCache.Set(zone, data)
+	globalZoneCache.Set(cc.cacheKey(zone), data, cc.cacheTTL)
 	log.Printf("[DEBUG] GLOBAL CACHE SET for zone %s", zone)
 	globalCacheMutex.Unlock()
 
@@ -139,10 +621,128 @@ func (cc *CachedClient) GetRecordsWithCache(zone string) ([]byte, error) {
 	return data, nil
 }
 
+// negativeCacheTTL returns how long a failed read of a zone is cached
+func (cc *CachedClient) negativeCacheTTL() time.Duration {
+	if cc.cacheTTL > 0 && cc.cacheTTL < NegativeCacheTTL {
+		return cc.cacheTTL
+	}
+	return NegativeCacheTTL
+}
+
+// ZoneCacheStats returns the number of zone reads served with data from the cache, the number
+// not found in the cache and the number answered with a cached failure
+func (cc *CachedClient) ZoneCacheStats() (hits, misses, failureHits int64) {
+	return globalZoneCache.Stats()
+}
+
+// GetRecordsByType returns the records of the given type at the name, filtered from the cached zone
+func (cc *CachedClient) GetRecordsByType(zone, name, recordType string) ([]base.DNSRecord, error) {
+	response, err := cc.GetRecordsWithCache(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	var zoneResponse base.DNSZoneResponse
+	if err := json.Unmarshal(response, &zoneResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse DNS records response: %w", err)
+	}
+	if err := zoneResponse.CheckZone(zone); err != nil {
+		return nil, err
+	}
+
+	var records []base.DNSRecord
+	for _, domain := range zoneResponse.Answer.Domains {
+		if !base.MatchesZone(domain.Dname, zone) {
+			continue
+		}
+		for _, rr := range domain.Rrs {
+			if rr.Rectype == recordType && base.MatchesSubname(rr.Subname, name) {
+				records = append(records, rr)
+			}
+		}
+	}
+
+	return records, nil
+}
+
+// GetRecord returns the record as stored by the API that matches the given content,
+// tolerating differences in trailing dots, letter case and quoting. It returns nil when
+// no such record exists.
+func (cc *CachedClient) GetRecord(zone, name, recordType, content string) (*base.DNSRecord, error) {
+	records, err := cc.GetRecordsByType(zone, name, recordType)
+	if err != nil {
+		return nil, err
+	}
+
+	expected := base.ComparableContent(content)
+	for i := range records {
+		if base.ComparableContent(records[i].Content) == expected {
+			return &records[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// AddRecord adds a record unless the zone is already known to be missing
+func (cc *CachedClient) AddRecord(recordType, domainName, subdomain, value string, priority *int) ([]byte, error) {
+	if err := cc.checkZone(domainName); err != nil {
+		return nil, err
+	}
+	response, err := cc.Client.AddRecord(recordType, domainName, subdomain, value, priority)
+	return response, cc.noteZone(domainName, err)
+}
+
+// AddRecordWithTTL adds a record with the given TTL unless the zone is already known to be missing
+func (cc *CachedClient) AddRecordWithTTL(recordType, domainName, subdomain, value string, priority, ttl *int) ([]byte, error) {
+	if err := cc.checkZone(domainName); err != nil {
+		return nil, err
+	}
+	response, err := cc.Client.AddRecordWithTTL(recordType, domainName, subdomain, value, priority, ttl)
+	return response, cc.noteZone(domainName, err)
+}
+
+// AddSRVRecord adds an SRV record unless the zone is already known to be missing
+func (cc *CachedClient) AddSRVRecord(domainName, subdomain, target string, priority, weight, port *int) ([]byte, error) {
+	if err := cc.checkZone(domainName); err != nil {
+		return nil, err
+	}
+	response, err := cc.Client.AddSRVRecord(domainName, subdomain, target, priority, weight, port)
+	return response, cc.noteZone(domainName, err)
+}
+
+// AddCAARecord adds a CAA record unless the zone is already known to be missing
+func (cc *CachedClient) AddCAARecord(domainName, subdomain, value string, flag *int, tag *string) ([]byte, error) {
+	if err := cc.checkZone(domainName); err != nil {
+		return nil, err
+	}
+	response, err := cc.Client.AddCAARecord(domainName, subdomain, value, flag, tag)
+	return response, cc.noteZone(domainName, err)
+}
+
+// AddSSHFPRecord adds an SSHFP record unless the zone is already known to be missing
+func (cc *CachedClient) AddSSHFPRecord(domainName, subdomain, fingerprint string, algorithm, fpType *int) ([]byte, error) {
+	if err := cc.checkZone(domainName); err != nil {
+		return nil, err
+	}
+	response, err := cc.Client.AddSSHFPRecord(domainName, subdomain, fingerprint, algorithm, fpType)
+	return response, cc.noteZone(domainName, err)
+}
+
+// AddTLSARecord adds a TLSA record unless the zone is already known to be missing
+func (cc *CachedClient) AddTLSARecord(domainName, subdomain, certificate string, usage, selector, matchingType *int) ([]byte, error) {
+	if err := cc.checkZone(domainName); err != nil {
+		return nil, err
+	}
+	response, err := cc.Client.AddTLSARecord(domainName, subdomain, certificate, usage, selector, matchingType)
+	return response, cc.noteZone(domainName, err)
+}
+
 // InvalidateZoneCache invalidates global cache for a specific zone
 func (cc *CachedClient) InvalidateZoneCache(zone string) {
 	globalCacheMutex.Lock()
-	globalZoneCache.Invalidate(zone)
+	globalZoneCache.Invalidate(cc.cacheKey(zone))
+	globalZoneFetches.forget(cc.cacheKey(zone))
 	log.Printf("[DEBUG] GLOBAL CACHE INVALIDATED for zone %s", zone)
 	globalCacheMutex.Unlock()
 }
@@ -151,6 +751,7 @@ func (cc *CachedClient) InvalidateZoneCache(zone string) {
 func (cc *CachedClient) ClearZoneCache() {
 	globalCacheMutex.Lock()
 	globalZoneCache.Clear()
+	cc.missingZones.reset()
 	log.Printf("[DEBUG] GLOBAL CACHE CLEARED")
 	globalCacheMutex.Unlock()
 }
@@ -161,25 +762,163 @@ func Provider() *schema.Provider {
 		Schema: map[string]*schema.Schema{
 			"username": {
 				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Reg.ru username",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("REGRU_USERNAME", nil),
+				Description: "Reg.ru username. Can also be set with the REGRU_USERNAME environment variable",
 			},
 			"password": {
 				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Reg.ru password",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("REGRU_PASSWORD", nil),
+				Description: "Reg.ru password. Can also be set with the REGRU_PASSWORD environment variable",
 				Sensitive:   true,
 			},
+			"api_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     client.DefaultBaseURL,
+				Description: "Base URL of the Reg.ru API, e.g. an internal mirror or a mock server",
+			},
+			"max_concurrent_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				Description:  "Maximum number of concurrent API requests (0 means unlimited)",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"requests_per_second": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Default:      client.DefaultRequestsPerSecond,
+				Description:  "Maximum rate at which API requests are started, smoothing bursts to stay under the API connection rate limit (0 means unlimited)",
+				ValidateFunc: validation.FloatAtLeast(0),
+			},
+			"max_api_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				Description:  "Maximum number of API requests in a run; further requests fail (0 means unlimited)",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"rate_limit_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      client.DefaultRateLimitRetries,
+				Description:  "Number of times a request rejected by the API rate limit is retried, with exponential backoff (0 disables retrying)",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"rate_limit_delay": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     client.DefaultRateLimitDelay.String(),
+				Description: "Delay before the first retry of a rate-limited request, doubled on each further retry up to 30s",
+			},
+			"request_timeout": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     client.DefaultRequestTimeout.String(),
+				Description: "Maximum duration of a single API request, e.g. \"30s\" (\"0s\" means no limit)",
+			},
+			"cache_ttl": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     DefaultCacheTTL.String(),
+				Description: "How long zone records read from the API are cached, e.g. \"1m\"",
+			},
+			"cache_enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Cache zone records between reads; disable to read every zone from the API when debugging state drift",
+			},
+			"max_response_size": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      client.DefaultMaxResponseSize,
+				Description:  "Maximum size in bytes of an API response body",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"disable_trailing_dot": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Send hostname targets without appending a trailing dot, for accounts whose API rejects them",
+			},
+			"strict_errors": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Fail on any unrecognized API error code, including the raw API response in the error",
+			},
+			"zone_change_check": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      ZoneCheckOff,
+				Description:  "Check that a zone's records are unchanged since this run read them before writing, to detect concurrent modifications: off, warn or fail",
+				ValidateFunc: validation.StringInSlice([]string{ZoneCheckOff, ZoneCheckWarn, ZoneCheckFail}, false),
+			},
+			"allowed_ttls": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "TTL values the API accepts. Record TTLs outside this list are rejected at plan time. No restriction when unset",
+				Elem: &schema.Schema{
+					Type:         schema.TypeInt,
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+			},
+			"fail_fast_missing_zones": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Stop sending requests for a zone once the API reports it as not found, failing its other resources with a single clear error",
+			},
+			"summary_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path of a file to keep a JSON summary of the records added and removed per zone during the run",
+			},
+			"audit_log": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path of a file to append an audit entry to for every change made through the API, with credentials masked",
+			},
+			"endpoints": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Overrides for the API endpoint used to add each record type (e.g. { A = \"zone/add_alias\" })",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"subdomain_params": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Overrides for the name of the subdomain parameter sent to an API endpoint (e.g. { \"zone/add_alias\" = \"subdomain\" })",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"regru_dns_a_record":     resources.ResourceDNSARecord(),
-			"regru_dns_aaaa_record":  resources.ResourceDNSAAAARecord(),
-			"regru_dns_cname_record": resources.ResourceDNSCNAMERecord(),
-			"regru_dns_mx_record":    resources.ResourceDNSMXRecord(),
-			"regru_dns_ns_record":    resources.ResourceDNSNSRecord(),
-			"regru_dns_txt_record":   resources.ResourceDNSTXTRecord(),
-			"regru_dns_srv_record":   resources.ResourceDNSSRVRecord(),
-			"regru_dns_caa_record":   resources.ResourceDNSCAARecord(),
+			"regru_dns_a_record":       resources.ResourceDNSARecord(),
+			"regru_dns_aaaa_record":    resources.ResourceDNSAAAARecord(),
+			"regru_dns_cname_record":   resources.ResourceDNSCNAMERecord(),
+			"regru_dns_ptr_record":     resources.ResourceDNSPTRRecord(),
+			"regru_dns_dname_record":   resources.ResourceDNSDNAMERecord(),
+			"regru_dns_mx_record":      resources.ResourceDNSMXRecord(),
+			"regru_dns_ns_record":      resources.ResourceDNSNSRecord(),
+			"regru_dns_txt_record":     resources.ResourceDNSTXTRecord(),
+			"regru_dns_srv_record":     resources.ResourceDNSSRVRecord(),
+			"regru_dns_sshfp_record":   resources.ResourceDNSSSHFPRecord(),
+			"regru_dns_caa_record":     resources.ResourceDNSCAARecord(),
+			"regru_dns_tlsa_record":    resources.ResourceDNSTLSARecord(),
+			"regru_dns_dkim_record":    resources.ResourceDNSDKIMRecord(),
+			"regru_dns_record_cleanup": resources.ResourceDNSRecordCleanup(),
+			"regru_dns_zone":           resources.ResourceDNSZone(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"regru_dns_api_stats":     resources.DataSourceAPIStats(),
+			"regru_dns_record":        resources.DataSourceRecord(),
+			"regru_dns_record_exists": resources.DataSourceRecordExists(),
+			"regru_dns_zone_for_fqdn": resources.DataSourceZoneForFQDN(),
+			"regru_dns_zone_records":  resources.DataSourceZoneRecords(),
+			"regru_dns_zones":         resources.DataSourceZones(),
 		},
 		ConfigureFunc: providerConfigure,
 	}
@@ -187,15 +926,55 @@ func Provider() *schema.Provider {
 
 // providerConfigure configures the provider with a cached client
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
-	username := d.Get("username").(string)
-	password := d.Get("password").(string)
+	config, err := NewProviderConfig(d)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create the base client
-	baseClient := client.NewClient(username, password)
+	baseClient := client.NewClient(config.Username, config.Password, config.APIURL)
+	baseClient.StrictErrors = config.StrictErrors
+	baseClient.MaxResponseSize = config.MaxResponseSize
+	baseClient.MaxRequests = config.MaxAPIRequests
+	baseClient.RateLimitRetries = config.RateLimitRetries
+	baseClient.RateLimitDelay = config.RateLimitDelay
+	baseClient.HTTPClient.Timeout = config.RequestTimeout
+	baseClient.SetMaxConcurrentRequests(config.MaxConcurrentRequests)
+	baseClient.SetRequestsPerSecond(config.RequestsPerSecond)
+	for recordType, endpoint := range config.Endpoints {
+		baseClient.SetEndpoint(recordType, endpoint)
+	}
+	for endpoint, name := range config.SubdomainParams {
+		baseClient.SetSubdomainParam(endpoint, name)
+	}
+	var hooks []client.AuditHook
+	if config.AuditLog != "" {
+		hook, err := client.NewFileAuditHook(config.AuditLog)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, hook)
+	}
+	var summary *operationSummary
+	if config.SummaryFile != "" {
+		summary = newOperationSummary(config.SummaryFile)
+		hooks = append(hooks, summary.hook())
+	}
+	baseClient.AuditHook = chainAuditHooks(hooks)
 
 	// Create cached client with global caching
 	cachedClient := &CachedClient{
-		Client: baseClient,
+		Client:     baseClient,
+		config:     config,
+		summary:    summary,
+		registry:   base.NewRecordRegistry(),
+		writeGuard: newZoneWriteGuard(config.ZoneChangeCheck),
+
+		cacheTTL:      config.CacheTTL,
+		cacheDisabled: !config.CacheEnabled,
+	}
+	if config.FailFastMissingZones {
+		cachedClient.missingZones = &missingZoneSet{zones: make(map[string]error)}
 	}
 
 	return cachedClient, nil
@@ -0,0 +1,649 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"terraform-provider-regru/client"
+	"terraform-provider-regru/resource/base"
+	"terraform-provider-regru/resource/resources"
+	"terraform-provider-regru/resource/strategies"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// testAPI is a stand-in for the Reg.ru API counting the requests made to each endpoint
+type testAPI struct {
+	*httptest.Server
+
+	mutex    sync.Mutex
+	requests map[string]int
+
+	// rrs is the JSON list of records reported for every zone
+	rrs string
+
+	// respond overrides the response to an endpoint when it returns true
+	respond func(w http.ResponseWriter, r *http.Request, endpoint string) bool
+}
+
+// newTestAPI starts a test API reporting empty zones
+func newTestAPI(t *testing.T) *testAPI {
+	api := &testAPI{requests: make(map[string]int), rrs: "[]"}
+	api.Server = httptest.NewServer(http.HandlerFunc(api.serve))
+	t.Cleanup(api.Close)
+	return api
+}
+
+func (api *testAPI) serve(w http.ResponseWriter, r *http.Request) {
+	endpoint := strings.TrimPrefix(r.URL.Path, "/")
+	r.ParseForm()
+
+	api.mutex.Lock()
+	api.requests[endpoint]++
+	rrs := api.rrs
+	api.mutex.Unlock()
+
+	if api.respond != nil && api.respond(w, r, endpoint) {
+		return
+	}
+
+	zone := r.PostForm.Get("dname")
+	fmt.Fprintf(w, `{"result":"success","answer":{"domains":[{"dname":%q,"result":"success","rrs":%s}]}}`, zone, rrs)
+}
+
+// count returns the number of requests made to the endpoint
+func (api *testAPI) count(endpoint string) int {
+	api.mutex.Lock()
+	defer api.mutex.Unlock()
+	return api.requests[endpoint]
+}
+
+// setRecords changes the JSON list of records reported by the API
+func (api *testAPI) setRecords(rrs string) {
+	api.mutex.Lock()
+	defer api.mutex.Unlock()
+	api.rrs = rrs
+}
+
+// newTestClient returns a cached client using the test API, caching zones for the given TTL
+func newTestClient(api *testAPI, cacheTTL time.Duration) *CachedClient {
+	apiClient := client.NewClient("test", "secret", api.URL)
+	apiClient.SetRequestsPerSecond(0)
+	apiClient.RateLimitDelay = time.Millisecond
+
+	return &CachedClient{
+		Client:   apiClient,
+		config:   &ProviderConfig{},
+		registry: base.NewRecordRegistry(),
+
+		cacheTTL: cacheTTL,
+	}
+}
+
+// expectRequests fails the test when the number of requests made to the endpoint differs from want
+func expectRequests(t *testing.T, api *testAPI, endpoint string, want int) {
+	t.Helper()
+	if got := api.count(endpoint); got != want {
+		t.Errorf("%s requests = %d, want %d", endpoint, got, want)
+	}
+}
+
+func TestExpiredZoneReadInFull(t *testing.T) {
+	api := newTestAPI(t)
+	cc := newTestClient(api, time.Millisecond)
+	zone := "expired.test"
+	t.Cleanup(func() { globalZoneCache.Invalidate(zone) })
+
+	read := func() {
+		t.Helper()
+		if _, err := cc.GetRecordsWithCache(zone); err != nil {
+			t.Fatalf("GetRecordsWithCache: %v", err)
+		}
+	}
+
+	read()
+	expectRequests(t, api, "zone/get_resource_records", 1)
+
+	// An expired copy is replaced by reading the zone again
+	time.Sleep(5 * time.Millisecond)
+	read()
+	expectRequests(t, api, "zone/get_resource_records", 2)
+}
+
+func TestZoneCacheSettings(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		disabled bool
+		requests int
+	}{
+		{"enabled", false, 1},
+		{"disabled", true, 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			api := newTestAPI(t)
+			cc := newTestClient(api, time.Minute)
+			cc.cacheDisabled = tc.disabled
+			zone := "cache-" + tc.name + ".test"
+			t.Cleanup(func() { globalZoneCache.Invalidate(zone) })
+
+			// Within the TTL a cached zone is only read once, unless caching is disabled
+			for i := 0; i < 3; i++ {
+				if _, err := cc.GetRecordsWithCache(zone); err != nil {
+					t.Fatalf("GetRecordsWithCache: %v", err)
+				}
+			}
+			expectRequests(t, api, "zone/get_resource_records", tc.requests)
+		})
+	}
+}
+
+func TestTransientZoneReadFailureCached(t *testing.T) {
+	api := newTestAPI(t)
+	var failing atomic.Bool
+	failing.Store(true)
+	api.respond = func(w http.ResponseWriter, r *http.Request, endpoint string) bool {
+		if !failing.Load() {
+			return false
+		}
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+		return true
+	}
+	cc := newTestClient(api, time.Minute)
+	cc.RateLimitRetries = 0
+	zone := "failing.test"
+	t.Cleanup(func() { globalZoneCache.Invalidate(zone) })
+	hits, misses, failureHits := cc.ZoneCacheStats()
+
+	// The failure is returned to every reader without repeating the request
+	for i := 0; i < 3; i++ {
+		if _, err := cc.GetRecordsWithCache(zone); !client.IsTransientError(err) {
+			t.Fatalf("read %d: err = %v, want a transient error", i, err)
+		}
+	}
+	expectRequests(t, api, "zone/get_resource_records", 1)
+	// Lookups answered with the failure are not counted as hits
+	if h, m, f := cc.ZoneCacheStats(); h-hits != 0 || m-misses != 1 || f-failureHits != 2 {
+		t.Errorf("cache hits, misses and failure hits = %d, %d and %d, want 0, 1 and 2", h-hits, m-misses, f-failureHits)
+	}
+
+	// Invalidating the zone drops the failure as well
+	failing.Store(false)
+	cc.InvalidateZoneCache(zone)
+	if _, err := cc.GetRecordsWithCache(zone); err != nil {
+		t.Fatalf("read after invalidation: %v", err)
+	}
+	expectRequests(t, api, "zone/get_resource_records", 2)
+}
+
+func TestConcurrentColdReadsShareOneRequest(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond = func(w http.ResponseWriter, r *http.Request, endpoint string) bool {
+		// Keep the read in flight long enough for every reader to join it
+		time.Sleep(50 * time.Millisecond)
+		return false
+	}
+	cc := newTestClient(api, time.Minute)
+	zone := "concurrent.test"
+	t.Cleanup(func() { globalZoneCache.Invalidate(zone) })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cc.WithContext(context.Background()).GetRecordsWithCache(zone); err != nil {
+				t.Errorf("GetRecordsWithCache: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	expectRequests(t, api, "zone/get_resource_records", 1)
+}
+
+func TestZonesDataSourcePrefetch(t *testing.T) {
+	zones := []string{"prefetch-a.test", "prefetch-b.test", "prefetch-c.test", "prefetch-d.test"}
+	var inFlight, maxInFlight atomic.Int32
+	api := newTestAPI(t)
+	api.respond = func(w http.ResponseWriter, r *http.Request, endpoint string) bool {
+		switch endpoint {
+		case "service/get_list":
+			var services []string
+			for _, zone := range zones {
+				services = append(services, fmt.Sprintf(`{"dname":%q,"servtype":"domain"}`, zone))
+			}
+			fmt.Fprintf(w, `{"result":"success","answer":{"services":[%s]}}`, strings.Join(services, ","))
+			return true
+		case "zone/get_resource_records":
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				seen := maxInFlight.Load()
+				if current <= seen || maxInFlight.CompareAndSwap(seen, current) {
+					break
+				}
+			}
+			// Keep the read in flight long enough for the others to start
+			time.Sleep(50 * time.Millisecond)
+		}
+		return false
+	}
+	cc := newTestClient(api, time.Minute)
+	t.Cleanup(func() {
+		for _, zone := range zones {
+			globalZoneCache.Invalidate(zone)
+		}
+	})
+
+	r := resources.DataSourceZones()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"prefetch_records": true})
+	if diags := r.ReadContext(context.Background(), d, cc); diags.HasError() {
+		t.Fatalf("Read: %v", diags)
+	}
+	expectRequests(t, api, "zone/get_resource_records", len(zones))
+	if got := maxInFlight.Load(); got < 2 {
+		t.Errorf("at most %d zone read in flight, want them fetched concurrently", got)
+	}
+
+	// Record resources are then served from the cache
+	for _, zone := range zones {
+		if _, err := cc.GetRecordsWithCache(zone); err != nil {
+			t.Fatalf("GetRecordsWithCache(%s): %v", zone, err)
+		}
+	}
+	expectRequests(t, api, "zone/get_resource_records", len(zones))
+}
+
+func TestRequestCount(t *testing.T) {
+	api := newTestAPI(t)
+	api.setRecords(`[
+		{"subname":"www","rectype":"A","content":"192.0.2.1"},
+		{"subname":"www","rectype":"A","content":"192.0.2.2"}]`)
+	cc := newTestClient(api, time.Minute)
+	t.Cleanup(func() { globalZoneCache.Invalidate("counted.test") })
+
+	// Creating two addresses adds each one and reads the zone once to confirm them
+	d := schema.TestResourceDataRaw(t, resources.ResourceDNSARecord().Schema, map[string]interface{}{
+		"zone":    "counted.test",
+		"name":    "www",
+		"records": []interface{}{"192.0.2.1", "192.0.2.2"},
+	})
+	if err := strategies.NewARecordStrategy().Create(cc, d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	// Refreshing is served from the cache
+	if err := strategies.NewARecordStrategy().Read(cc, d); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	expectRequests(t, api, "zone/add_alias", 2)
+	expectRequests(t, api, "zone/get_resource_records", 1)
+
+	stats := resources.DataSourceAPIStats()
+	statsData := schema.TestResourceDataRaw(t, stats.Schema, map[string]interface{}{})
+	if err := stats.Read(statsData, cc); err != nil {
+		t.Fatalf("read api stats: %v", err)
+	}
+	if count := statsData.Get("request_count").(int); count != 3 {
+		t.Errorf("request_count = %d, want 3", count)
+	}
+
+	// Requests beyond max_api_requests are refused without reaching the API
+	cc.MaxRequests = 3
+	if _, err := cc.AddRecord("A", "counted.test", "www", "192.0.2.3", nil); client.ErrorCode(err) != "REQUEST_LIMIT_REACHED" {
+		t.Errorf("request over the limit: err = %v, want REQUEST_LIMIT_REACHED", err)
+	}
+	expectRequests(t, api, "zone/add_alias", 2)
+}
+
+// newGuardedClient returns a test client checking zones for concurrent changes in the given mode
+func newGuardedClient(api *testAPI, mode string) *CachedClient {
+	cc := newTestClient(api, time.Minute)
+	cc.cacheDisabled = true
+	cc.writeGuard = newZoneWriteGuard(mode)
+	return cc
+}
+
+func TestZoneChangedMidBatch(t *testing.T) {
+	for _, tc := range []struct {
+		mode    string
+		wantErr bool
+	}{
+		{ZoneCheckOff, false},
+		{ZoneCheckWarn, false},
+		{ZoneCheckFail, true},
+	} {
+		t.Run(tc.mode, func(t *testing.T) {
+			api := newTestAPI(t)
+			cc := newGuardedClient(api, tc.mode)
+			zone := "guarded.test"
+
+			if _, err := cc.GetRecordsWithCache(zone); err != nil {
+				t.Fatalf("GetRecordsWithCache: %v", err)
+			}
+
+			// The first write of the batch finds the zone as this run read it
+			wrote := 0
+			write := func() error {
+				wrote++
+				return nil
+			}
+			if err := cc.GuardZoneWrite(zone, write); err != nil {
+				t.Fatalf("first write: %v", err)
+			}
+
+			// Another run adds a record before the second write
+			api.setRecords(`[{"subname":"www","rectype":"A","content":"192.0.2.1"}]`)
+
+			err := cc.GuardZoneWrite(zone, write)
+			if tc.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "modified concurrently") {
+					t.Fatalf("second write error = %v, want a concurrent modification", err)
+				}
+				if wrote != 1 {
+					t.Errorf("writes = %d, want the second write skipped", wrote)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("second write: %v", err)
+			}
+			if wrote != 2 {
+				t.Errorf("writes = %d, want 2", wrote)
+			}
+		})
+	}
+}
+
+func TestZoneUnchangedAcrossOwnWrites(t *testing.T) {
+	api := newTestAPI(t)
+	cc := newGuardedClient(api, ZoneCheckFail)
+	zone := "own.test"
+
+	if _, err := cc.GetRecordsWithCache(zone); err != nil {
+		t.Fatalf("GetRecordsWithCache: %v", err)
+	}
+
+	// Changes made by this run's own writes are not reported as concurrent
+	for i, rrs := range []string{
+		`[{"subname":"a","rectype":"A","content":"192.0.2.1"}]`,
+		`[{"subname":"a","rectype":"A","content":"192.0.2.1"},{"subname":"b","rectype":"A","content":"192.0.2.2"}]`,
+	} {
+		rrs := rrs
+		if err := cc.GuardZoneWrite(zone, func() error {
+			api.setRecords(rrs)
+			return nil
+		}); err != nil {
+			t.Fatalf("write %d: %v", i, err)
+		}
+	}
+}
+
+func TestZoneUnchangedAfterOwnCleanup(t *testing.T) {
+	api := newTestAPI(t)
+	api.setRecords(`[{"subname":"www","rectype":"A","content":"192.0.2.1"},{"subname":"_acme-challenge","rectype":"TXT","content":"token"}]`)
+	api.respond = func(w http.ResponseWriter, r *http.Request, endpoint string) bool {
+		if endpoint != "zone/remove_record" {
+			return false
+		}
+		api.setRecords(`[{"subname":"www","rectype":"A","content":"192.0.2.1"}]`)
+		fmt.Fprintf(w, `{"result":"success","answer":{"domains":[{"dname":%q,"result":"success"}]}}`, r.PostForm.Get("dname"))
+		return true
+	}
+	cc := newGuardedClient(api, ZoneCheckFail)
+	zone := "cleanup.test"
+
+	r := resources.ResourceDNSRecordCleanup()
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"zone":         zone,
+		"record_type":  "TXT",
+		"name_pattern": "_acme-challenge.*",
+	})
+	diff, err := r.Diff(context.Background(), nil, config, cc)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	if _, diags := r.Apply(context.Background(), nil, diff, cc); diags.HasError() {
+		t.Fatalf("apply: %v", diags)
+	}
+	expectRequests(t, api, "zone/remove_record", 1)
+
+	// The records removed by the cleanup are not reported as a concurrent change
+	if err := cc.GuardZoneWrite(zone, func() error { return nil }); err != nil {
+		t.Fatalf("write after cleanup: %v", err)
+	}
+}
+
+func TestZoneChangeCheckUnreadable(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond = func(w http.ResponseWriter, r *http.Request, endpoint string) bool {
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+		return true
+	}
+	cc := newGuardedClient(api, ZoneCheckFail)
+	cc.RateLimitRetries = 0
+
+	wrote := false
+	err := cc.GuardZoneWrite("unreadable.test", func() error {
+		wrote = true
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "cannot check zone") {
+		t.Fatalf("error = %v, want the zone check to fail", err)
+	}
+	if wrote {
+		t.Error("wrote to a zone that could not be checked")
+	}
+}
+
+func TestMissingZoneRequestedOnce(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond = func(w http.ResponseWriter, r *http.Request, endpoint string) bool {
+		if r.PostForm.Get("username") != "test" {
+			return false
+		}
+		fmt.Fprint(w, `{"result":"error","error_code":"DOMAIN_NOT_FOUND","error_text":"Domain not found"}`)
+		return true
+	}
+	cc := newTestClient(api, time.Minute)
+	cc.missingZones = &missingZoneSet{zones: make(map[string]error)}
+	zone := "missing.test"
+	t.Cleanup(func() { globalZoneCache.Invalidate(zone) })
+
+	for i := 0; i < 3; i++ {
+		if _, err := cc.GetRecordsWithCache(zone); client.ErrorCode(err) != "DOMAIN_NOT_FOUND" {
+			t.Fatalf("read %d: err = %v, want DOMAIN_NOT_FOUND", i, err)
+		}
+	}
+	if _, err := cc.AddRecord("A", zone, "www", "192.0.2.1", nil); client.ErrorCode(err) != "DOMAIN_NOT_FOUND" {
+		t.Fatalf("AddRecord: err = %v, want DOMAIN_NOT_FOUND", err)
+	}
+	ttl := 3600
+	if _, err := base.AddRecordWithTTL(cc, "A", zone, "www", "192.0.2.1", nil, &ttl); client.ErrorCode(err) != "DOMAIN_NOT_FOUND" {
+		t.Fatalf("AddRecordWithTTL: err = %v, want DOMAIN_NOT_FOUND", err)
+	}
+	expectRequests(t, api, "zone/get_resource_records", 1)
+	expectRequests(t, api, "zone/add_alias", 0)
+
+	// Other credentials may have access to the zone
+	scoped := cc.WithCredentials("other", "secret")
+	t.Cleanup(func() { scoped.InvalidateZoneCache(zone) })
+	if _, err := scoped.GetRecordsWithCache(zone); err != nil {
+		t.Fatalf("read with other credentials: %v", err)
+	}
+	expectRequests(t, api, "zone/get_resource_records", 2)
+}
+
+func TestZonesCachedPerCredentials(t *testing.T) {
+	api := newTestAPI(t)
+	cc := newTestClient(api, time.Minute)
+	zone := "credentials.test"
+
+	first := cc.WithCredentials("owner", "first")
+	second := cc.WithCredentials("owner", "second")
+	t.Cleanup(func() {
+		first.InvalidateZoneCache(zone)
+		second.InvalidateZoneCache(zone)
+	})
+
+	for _, scoped := range []base.CachedClientInterface{first, second, cc.WithCredentials("owner", "first")} {
+		if _, err := scoped.GetRecordsWithCache(zone); err != nil {
+			t.Fatalf("GetRecordsWithCache: %v", err)
+		}
+	}
+	// Clients differing only by password don't share cached zones
+	expectRequests(t, api, "zone/get_resource_records", 2)
+
+	if key := first.(*CachedClient).cacheKey(zone); strings.Contains(key, "first") || strings.Contains(key, "owner") {
+		t.Errorf("cache key %q reveals the credentials", key)
+	}
+}
+
+func TestGetRecordsByTypeInMultiTypeZone(t *testing.T) {
+	api := newTestAPI(t)
+	api.setRecords(`[
+		{"subname":"@","rectype":"A","content":"192.0.2.1"},
+		{"subname":"www","rectype":"A","content":"192.0.2.2"},
+		{"subname":"www","rectype":"A","content":"192.0.2.3"},
+		{"subname":"www","rectype":"AAAA","content":"2001:db8::1"},
+		{"subname":"www","rectype":"TXT","content":"v=spf1 -all"},
+		{"subname":"mail","rectype":"A","content":"192.0.2.4"},
+		{"subname":"@","rectype":"MX","content":"mail.multi.test.","prio":10}]`)
+	cc := newTestClient(api, time.Minute)
+	zone := "multi.test"
+	t.Cleanup(func() { globalZoneCache.Invalidate(zone) })
+
+	for _, tc := range []struct {
+		name, recordType string
+		want             string
+	}{
+		{"www", "A", "192.0.2.2 192.0.2.3"},
+		{"www", "AAAA", "2001:db8::1"},
+		{"www", "TXT", "v=spf1 -all"},
+		{"@", "A", "192.0.2.1"},
+		{"@", "MX", "mail.multi.test."},
+		{"mail", "AAAA", ""},
+		{"ftp", "A", ""},
+	} {
+		records, err := cc.GetRecordsByType(zone, tc.name, tc.recordType)
+		if err != nil {
+			t.Fatalf("GetRecordsByType(%s, %s): %v", tc.name, tc.recordType, err)
+		}
+		var contents []string
+		for _, rr := range records {
+			contents = append(contents, rr.Content)
+		}
+		if got := strings.Join(contents, " "); got != tc.want {
+			t.Errorf("GetRecordsByType(%s, %s) = %q, want %q", tc.name, tc.recordType, got, tc.want)
+		}
+	}
+	// Every lookup is filtered from a single read of the zone
+	expectRequests(t, api, "zone/get_resource_records", 1)
+}
+
+func TestGetRecordsByTypeWithQualifiedSubnames(t *testing.T) {
+	api := newTestAPI(t)
+	api.setRecords(`[
+		{"subname":"www.fqdn.test","rectype":"A","content":"192.0.2.1"},
+		{"subname":"fqdn.test.","rectype":"A","content":"192.0.2.2"}]`)
+	cc := newTestClient(api, time.Minute)
+	zone := "fqdn.test"
+	t.Cleanup(func() { globalZoneCache.Invalidate(zone) })
+
+	for name, want := range map[string]string{"www": "192.0.2.1", "@": "192.0.2.2"} {
+		records, err := cc.GetRecordsByType(zone, name, "A")
+		if err != nil {
+			t.Fatalf("GetRecordsByType(%s): %v", name, err)
+		}
+		if len(records) != 1 || records[0].Content != want || records[0].Subname != name {
+			t.Errorf("GetRecordsByType(%s) = %+v, want the %s record with subname %s", name, records, want, name)
+		}
+	}
+}
+
+func TestRecordsRepeatedAcrossDomainEntries(t *testing.T) {
+	api := newTestAPI(t)
+	api.respond = func(w http.ResponseWriter, r *http.Request, endpoint string) bool {
+		if endpoint != "zone/get_resource_records" {
+			return false
+		}
+		fmt.Fprint(w, `{"result":"success","answer":{"domains":[
+			{"dname":"repeated.test","result":"success","rrs":[
+				{"subname":"www","rectype":"A","content":"192.0.2.1"},
+				{"subname":"www","rectype":"A","content":"192.0.2.2"}]},
+			{"dname":"repeated.test.","result":"success","rrs":[
+				{"subname":"WWW","rectype":"A","content":"192.0.2.1"},
+				{"subname":"www","rectype":"A","content":"192.0.2.3"}]}]}}`)
+		return true
+	}
+	cc := newTestClient(api, time.Minute)
+	zone := "repeated.test"
+	t.Cleanup(func() { globalZoneCache.Invalidate(zone) })
+
+	records, err := cc.GetRecordsByType(zone, "www", "A")
+	if err != nil {
+		t.Fatalf("GetRecordsByType: %v", err)
+	}
+	var contents []string
+	for _, rr := range records {
+		contents = append(contents, rr.Content)
+	}
+	if want := "192.0.2.1 192.0.2.2 192.0.2.3"; strings.Join(contents, " ") != want {
+		t.Errorf("records = %q, want %s", contents, want)
+	}
+}
+
+func TestDisableTrailingDot(t *testing.T) {
+	for _, disabled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("disable_trailing_dot=%t", disabled), func(t *testing.T) {
+			api := newTestAPI(t)
+			var target string
+			api.respond = func(w http.ResponseWriter, r *http.Request, endpoint string) bool {
+				switch endpoint {
+				case "zone/add_cname":
+					api.mutex.Lock()
+					target = r.PostForm.Get("canonical_name")
+					api.mutex.Unlock()
+					fmt.Fprint(w, `{"result":"success","answer":{"domains":[{"dname":"trailing-dot.test","result":"success"}]}}`)
+					return true
+				case "zone/get_resource_records":
+					fmt.Fprint(w, `{"result":"success","answer":{"domains":[{"dname":"trailing-dot.test","result":"success","rrs":[
+						{"subname":"www","rectype":"CNAME","content":"target.example.net."}]}]}}`)
+					return true
+				}
+				return false
+			}
+			cc := newTestClient(api, time.Minute)
+			cc.config.DisableTrailingDot = disabled
+			t.Cleanup(func() { globalZoneCache.Invalidate("trailing-dot.test") })
+
+			d := schema.TestResourceDataRaw(t, resources.ResourceDNSCNAMERecord().Schema, map[string]interface{}{
+				"zone":  "trailing-dot.test",
+				"name":  "www",
+				"cname": "target.example.net",
+			})
+			if err := strategies.NewCNAMERecordStrategy().Create(cc, d); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+
+			want := "target.example.net."
+			if disabled {
+				want = "target.example.net"
+			}
+			if target != want {
+				t.Errorf("canonical_name sent = %q, want %q", target, want)
+			}
+			// Reads compare without the dot either way
+			if cname := d.Get("cname"); cname != "target.example.net" {
+				t.Errorf("cname after create = %q, want target.example.net", cname)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"terraform-provider-regru/client"
+)
+
+// operationSummary aggregates the records changed during a run per zone and keeps a JSON
+// summary of them in a file, rewritten after every change since the provider is not told
+// when the run ends. Only record data is included; credentials never are.
+type operationSummary struct {
+	mutex sync.Mutex
+	path  string
+	zones map[string]*zoneSummary
+}
+
+// zoneSummary lists the records changed in a zone, in "name TYPE content" notation
+type zoneSummary struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// newOperationSummary creates a summary written to the file at path
+func newOperationSummary(path string) *operationSummary {
+	return &operationSummary{
+		path:  path,
+		zones: make(map[string]*zoneSummary),
+	}
+}
+
+// hook returns an audit hook adding successful changes to the summary
+func (s *operationSummary) hook() client.AuditHook {
+	return func(entry client.AuditEntry) {
+		if entry.Result != "success" {
+			return
+		}
+		if err := s.add(entry); err != nil {
+			log.Printf("[WARN] Failed to update operation summary %s: %v", s.path, err)
+		}
+	}
+}
+
+// add records a change and rewrites the summary file
+func (s *operationSummary) add(entry client.AuditEntry) error {
+	zone := strings.ToLower(strings.TrimSuffix(entry.Params.Get("domain_name"), "."))
+	if zone == "" {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	summary, ok := s.zones[zone]
+	if !ok {
+		summary = &zoneSummary{Added: []string{}, Removed: []string{}}
+		s.zones[zone] = summary
+	}
+
+	record := summaryRecord(entry)
+	switch {
+	case strings.HasPrefix(entry.Endpoint, "zone/add_"):
+		summary.Added = append(summary.Added, record)
+	case entry.Endpoint == "zone/remove_record":
+		summary.Removed = append(summary.Removed, record)
+	default:
+		return nil
+	}
+
+	return s.write()
+}
+
+// write replaces the summary file with the current summary
+func (s *operationSummary) write() error {
+	type totals struct {
+		Added   int `json:"added"`
+		Removed int `json:"removed"`
+	}
+	output := struct {
+		UpdatedAt time.Time               `json:"updated_at"`
+		Totals    totals                  `json:"totals"`
+		Zones     map[string]*zoneSummary `json:"zones"`
+	}{
+		UpdatedAt: time.Now().UTC(),
+		Zones:     s.zones,
+	}
+	for _, zone := range s.zones {
+		output.Totals.Added += len(zone.Added)
+		output.Totals.Removed += len(zone.Removed)
+	}
+
+	data, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so readers never see a partial summary
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create summary file: %w", err)
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write summary file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// summaryParams maps the value parameters of the add endpoints to the record type they add
+var summaryParams = []struct {
+	param      string
+	recordType string
+}{
+	{"canonical_name", "CNAME"},
+	{"ptr_name", "PTR"},
+	{"dname", "DNAME"},
+	{"mail_server", "MX"},
+	{"dns_server", "NS"},
+	{"text", "TXT"},
+}
+
+// summaryRecord describes the record an API call changed in "name TYPE content" notation
+func summaryRecord(entry client.AuditEntry) string {
+	params := entry.Params
+	name := entry.Subdomain
+	if name == "" {
+		name = "@"
+	}
+
+	recordType := params.Get("record_type")
+	content := params.Get("content")
+	switch {
+	case recordType != "":
+	case params.Get("ipaddr") != "":
+		content = params.Get("ipaddr")
+		recordType = "A"
+		if strings.Contains(content, ":") {
+			recordType = "AAAA"
+		}
+	case params.Get("target") != "":
+		recordType = "SRV"
+		content = params.Get("target")
+	case params.Get("fingerprint") != "":
+		recordType = "SSHFP"
+		content = params.Get("fingerprint")
+	case params.Get("certificate") != "":
+		recordType = "TLSA"
+		content = params.Get("certificate")
+	case params.Get("tag") != "":
+		recordType = "CAA"
+		content = fmt.Sprintf("%s %s %q", params.Get("flags"), params.Get("tag"), params.Get("value"))
+	default:
+		for _, candidate := range summaryParams {
+			if value := params.Get(candidate.param); value != "" {
+				recordType = candidate.recordType
+				content = value
+				break
+			}
+		}
+	}
+
+	switch recordType {
+	case "MX", "NS":
+		if priority := params.Get("priority"); priority != "" {
+			content = priority + " " + content
+		}
+	case "SRV":
+		content = fmt.Sprintf("%s %s %s %s", params.Get("priority"), params.Get("weight"), params.Get("port"), content)
+	case "SSHFP":
+		if algorithm := params.Get("algorithm"); algorithm != "" {
+			content = fmt.Sprintf("%s %s %s", algorithm, params.Get("fp_type"), content)
+		}
+	case "TLSA":
+		if usage := params.Get("usage"); usage != "" {
+			content = fmt.Sprintf("%s %s %s %s", usage, params.Get("selector"), params.Get("matching_type"), content)
+		}
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s %s %s", name, recordType, content))
+}
+
+// chainAuditHooks returns an audit hook passing every entry to each of the hooks
+func chainAuditHooks(hooks []client.AuditHook) client.AuditHook {
+	switch len(hooks) {
+	case 0:
+		return nil
+	case 1:
+		return hooks[0]
+	}
+
+	ordered := append([]client.AuditHook(nil), hooks...)
+	return func(entry client.AuditEntry) {
+		for _, hook := range ordered {
+			hook(entry)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"terraform-provider-regru/client"
+)
+
+func TestOperationSummary(t *testing.T) {
+	api := newTestAPI(t)
+	path := filepath.Join(t.TempDir(), "summary.json")
+	summary := newOperationSummary(path)
+
+	c := client.NewClient("test", "secret", api.URL)
+	c.SetRequestsPerSecond(0)
+	c.AuditHook = summary.hook()
+	// An endpoint taking the record name in another parameter is summarized by the same name
+	c.SetSubdomainParam("zone/add_srv", "service")
+
+	priority, weight, port := 10, 5, 5060
+	for _, call := range []func() ([]byte, error){
+		func() ([]byte, error) { return c.AddRecord("A", "example.com", "www", "192.0.2.1", nil) },
+		func() ([]byte, error) { return c.AddRecord("AAAA", "example.com", "www", "2001:db8::1", nil) },
+		func() ([]byte, error) { return c.AddRecord("MX", "example.com", "@", "mx1.example.com.", &priority) },
+		func() ([]byte, error) { return c.RemoveRecord("example.com", "www", "A", "192.0.2.9", nil) },
+		func() ([]byte, error) { return c.AddRecord("TXT", "Example.net.", "", "v=spf1 -all", nil) },
+		func() ([]byte, error) {
+			return c.AddSRVRecord("example.com", "_sip._tcp", "sip.example.com.", &priority, &weight, &port)
+		},
+		// Reads are not changes and are left out
+		func() ([]byte, error) { return c.GetRecords("example.com") },
+	} {
+		if _, err := call(); err != nil {
+			t.Fatalf("API call: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading the summary: %v", err)
+	}
+	if strings.Contains(string(data), "secret") {
+		t.Errorf("summary contains the password: %s", data)
+	}
+
+	var got struct {
+		UpdatedAt time.Time `json:"updated_at"`
+		Totals    struct {
+			Added   int `json:"added"`
+			Removed int `json:"removed"`
+		} `json:"totals"`
+		Zones map[string]zoneSummary `json:"zones"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("parsing the summary: %v", err)
+	}
+	if got.UpdatedAt.IsZero() {
+		t.Error("updated_at not set")
+	}
+	if got.Totals.Added != 5 || got.Totals.Removed != 1 {
+		t.Errorf("totals = %+v, want 5 added and 1 removed", got.Totals)
+	}
+
+	want := map[string]zoneSummary{
+		"example.com": {
+			Added:   []string{"www A 192.0.2.1", "www AAAA 2001:db8::1", "@ MX 10 mx1.example.com.", "_sip._tcp SRV 10 5 5060 sip.example.com."},
+			Removed: []string{"www A 192.0.2.9"},
+		},
+		"example.net": {
+			Added:   []string{"@ TXT v=spf1 -all"},
+			Removed: []string{},
+		},
+	}
+	if !reflect.DeepEqual(got.Zones, want) {
+		t.Errorf("zones = %+v, want %+v", got.Zones, want)
+	}
+}
@@ -1,5 +1,10 @@
 package base
 
+import (
+	"context"
+	"log"
+)
+
 // CachedClientInterface defines the interface for cached client operations
 // This avoids import cycles between strategies and provider packages
 type CachedClientInterface interface {
@@ -16,8 +21,78 @@ type CachedClientInterface interface {
 	AddCAARecord(domainName, subdomain, value string, flag *int, tag *string) ([]byte, error)
 	RemoveCAARecord(domainName, subdomain, value string, flag *int, tag *string) ([]byte, error)
 
+	// Specialized SSHFP operations
+	AddSSHFPRecord(domainName, subdomain, fingerprint string, algorithm, fpType *int) ([]byte, error)
+	RemoveSSHFPRecord(domainName, subdomain, fingerprint string, algorithm, fpType *int) ([]byte, error)
+
+	// Specialized TLSA operations
+	AddTLSARecord(domainName, subdomain, certificate string, usage, selector, matchingType *int) ([]byte, error)
+	RemoveTLSARecord(domainName, subdomain, certificate string, usage, selector, matchingType *int) ([]byte, error)
+
+	// Account operations
+	GetDomains() ([]byte, error)
+
 	// Caching operations
 	GetRecordsWithCache(domainName string) ([]byte, error)
+	GetRecordsByType(zone, name, recordType string) ([]DNSRecord, error)
+	GetRecord(zone, name, recordType, content string) (*DNSRecord, error)
 	InvalidateZoneCache(zone string)
 	ClearZoneCache()
 }
+
+// ContextClient returns a client whose requests are cancelled with ctx,
+// or the client unchanged when it doesn't support contexts
+func ContextClient(client interface{}, ctx context.Context) interface{} {
+	if scoper, ok := client.(interface {
+		WithContext(ctx context.Context) CachedClientInterface
+	}); ok {
+		return scoper.WithContext(ctx)
+	}
+	return client
+}
+
+// GuardZoneWrite runs a batch of writes to the zone under the client's concurrent modification
+// check, or runs it directly when the client doesn't provide one
+func GuardZoneWrite(client interface{}, zone string, write func() error) error {
+	if guard, ok := client.(interface {
+		GuardZoneWrite(zone string, write func() error) error
+	}); ok {
+		return guard.GuardZoneWrite(zone, write)
+	}
+	return write()
+}
+
+// ScopedClient returns a client authenticating with the given credentials,
+// or the client unchanged when it doesn't support switching credentials
+func ScopedClient(client interface{}, username, password string) interface{} {
+	if scoper, ok := client.(interface {
+		WithCredentials(username, password string) CachedClientInterface
+	}); ok {
+		return scoper.WithCredentials(username, password)
+	}
+	return client
+}
+
+// TrailingDotDisabled reports whether the client sends targets without adding a trailing dot
+func TrailingDotDisabled(client interface{}) bool {
+	if dotter, ok := client.(interface {
+		TrailingDotDisabled() bool
+	}); ok {
+		return dotter.TrailingDotDisabled()
+	}
+	return false
+}
+
+// AddRecordWithTTL adds a record of a simple type with the given TTL, or with the zone default when
+// ttl is nil. A client that cannot set TTLs adds the record with the zone default.
+func AddRecordWithTTL(client CachedClientInterface, recordType, zone, name, value string, priority, ttl *int) ([]byte, error) {
+	if ttl != nil {
+		if ttlClient, ok := client.(interface {
+			AddRecordWithTTL(recordType, domainName, subdomain, value string, priority, ttl *int) ([]byte, error)
+		}); ok {
+			return ttlClient.AddRecordWithTTL(recordType, zone, name, value, priority, ttl)
+		}
+		log.Printf("[WARN] Client cannot set TTLs, adding %s record %s.%s -> %s with the zone default", recordType, name, zone, value)
+	}
+	return client.AddRecord(recordType, zone, name, value, priority)
+}
@@ -1,6 +1,13 @@
 package base
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -15,6 +22,88 @@ type DNSRecord struct {
 	Port    int    `json:"port"`
 	Flag    int    `json:"flag"`
 	Tag     string `json:"tag"`
+	TTL     int    `json:"ttl"`
+
+	// Usage, Selector and MatchingType are the TLSA fields, when the API reports them separately
+	Usage        int `json:"usage"`
+	Selector     int `json:"selector"`
+	MatchingType int `json:"matching_type"`
+
+	// CreatedAt is the creation time of the record, when the API reports one
+	CreatedAt string `json:"created_at"`
+}
+
+// UnmarshalJSON parses a DNS record, accepting the alternative field names
+// the API uses in some responses (e.g. "priority" instead of "prio")
+func (r *DNSRecord) UnmarshalJSON(data []byte) error {
+	type plainRecord DNSRecord
+	var aux struct {
+		plainRecord
+		Priority *int        `json:"priority"`
+		Weight   *int        `json:"weight"`
+		Port     *int        `json:"port"`
+		Flags    *int        `json:"flags"`
+		Flag     *int        `json:"flag"`
+		Tag      *string     `json:"tag"`
+		TTL      interface{} `json:"ttl"`
+		// CreatedAt shadows the string field, as the API may report any of these
+		// timestamps as a Unix time or a string
+		CreatedAt interface{} `json:"created_at"`
+		Created   interface{} `json:"created"`
+		CTime     interface{} `json:"ctime"`
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	*r = DNSRecord(aux.plainRecord)
+	if r.Prio == 0 && aux.Priority != nil {
+		r.Prio = *aux.Priority
+	}
+	if aux.Weight != nil {
+		r.Weight = *aux.Weight
+	}
+	if aux.Port != nil {
+		r.Port = *aux.Port
+	}
+	if aux.Flag != nil {
+		r.Flag = *aux.Flag
+	} else if aux.Flags != nil {
+		r.Flag = *aux.Flags
+	}
+	if aux.Tag != nil {
+		r.Tag = *aux.Tag
+	}
+
+	// The TTL may be returned as a number or a numeric string
+	switch ttl := aux.TTL.(type) {
+	case float64:
+		r.TTL = int(ttl)
+	case string:
+		r.TTL, _ = strconv.Atoi(ttl)
+	}
+
+	for _, created := range []interface{}{aux.CreatedAt, aux.Created, aux.CTime} {
+		if r.CreatedAt = formatTimestamp(created); r.CreatedAt != "" {
+			break
+		}
+	}
+	return nil
+}
+
+// formatTimestamp renders a timestamp from an API response, converting Unix times to RFC 3339.
+// It returns an empty string for missing values.
+func formatTimestamp(value interface{}) string {
+	switch timestamp := value.(type) {
+	case float64:
+		if timestamp > 0 {
+			return time.Unix(int64(timestamp), 0).UTC().Format(time.RFC3339)
+		}
+	case string:
+		return timestamp
+	}
+	return ""
 }
 
 // DNSZoneResponse represents the API response for zone records
@@ -28,6 +117,72 @@ type DNSZoneResponse struct {
 	} `json:"answer"`
 }
 
+// UnmarshalJSON parses a zone response, dropping records that repeat within the same zone.
+// A zone's records may be spread over several domain entries, and the same record can then
+// appear in more than one of them; only its first occurrence is kept. Subnames returned fully
+// qualified, such as www.example.com in zone example.com, are made relative to the zone.
+func (r *DNSZoneResponse) UnmarshalJSON(data []byte) error {
+	type plainResponse DNSZoneResponse
+	var aux plainResponse
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	for i := range aux.Answer.Domains {
+		domain := &aux.Answer.Domains[i]
+		zone := strings.ToLower(strings.TrimSuffix(domain.Dname, "."))
+
+		unique := domain.Rrs[:0]
+		for _, rr := range domain.Rrs {
+			rr.Subname = RelativeSubname(rr.Subname, zone)
+			key := fmt.Sprintf("%s|%s|%s|%s|%d|%d|%d", zone, normalizeSubname(rr.Subname), rr.Rectype, rr.Content, rr.Prio, rr.Weight, rr.Port)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			unique = append(unique, rr)
+		}
+		domain.Rrs = unique
+	}
+
+	*r = DNSZoneResponse(aux)
+	return nil
+}
+
+// RelativeSubname strips the zone suffix from a subname given as a fully qualified name.
+// The zone itself becomes "@"; relative subnames are returned unchanged.
+func RelativeSubname(subname, zone string) string {
+	zone = strings.ToLower(strings.TrimSuffix(zone, "."))
+	fqdn := strings.ToLower(strings.TrimSuffix(subname, "."))
+	if zone == "" {
+		return subname
+	}
+
+	switch {
+	case fqdn == zone:
+		return "@"
+	case strings.HasSuffix(fqdn, "."+zone):
+		return strings.TrimSuffix(subname, ".")[:len(fqdn)-len(zone)-1]
+	default:
+		return subname
+	}
+}
+
+// ErrZoneNotFound is returned when the API response has no entry for the requested zone
+var ErrZoneNotFound = errors.New("zone not found")
+
+// CheckZone returns ErrZoneNotFound when the response has no entry for the zone. An entry
+// without records means the zone exists but holds no records.
+func (r *DNSZoneResponse) CheckZone(zone string) error {
+	for _, domain := range r.Answer.Domains {
+		if MatchesZone(domain.Dname, zone) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s is not in the API response; check that the domain exists in the account", ErrZoneNotFound, zone)
+}
+
 // DNSRecordResource defines the interface that all DNS record resources must implement
 type DNSRecordResource interface {
 	// Core attributes
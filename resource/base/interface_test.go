@@ -0,0 +1,111 @@
+package base
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDNSRecordFieldNames(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		json string
+		want DNSRecord
+	}{
+		{
+			name: "prio",
+			json: `{"subname":"@","rectype":"MX","content":"mx.example.com.","prio":10}`,
+			want: DNSRecord{Subname: "@", Rectype: "MX", Content: "mx.example.com.", Prio: 10},
+		},
+		{
+			name: "priority",
+			json: `{"subname":"@","rectype":"MX","content":"mx.example.com.","priority":10}`,
+			want: DNSRecord{Subname: "@", Rectype: "MX", Content: "mx.example.com.", Prio: 10},
+		},
+		{
+			name: "SRV fields",
+			json: `{"subname":"_sip._tcp","rectype":"SRV","content":"sip.example.com.","priority":10,"weight":5,"port":5060}`,
+			want: DNSRecord{Subname: "_sip._tcp", Rectype: "SRV", Content: "sip.example.com.", Prio: 10, Weight: 5, Port: 5060},
+		},
+		{
+			name: "flag",
+			json: `{"subname":"@","rectype":"CAA","content":"letsencrypt.org","flag":128,"tag":"issue"}`,
+			want: DNSRecord{Subname: "@", Rectype: "CAA", Content: "letsencrypt.org", Flag: 128, Tag: "issue"},
+		},
+		{
+			name: "flags",
+			json: `{"subname":"@","rectype":"CAA","content":"letsencrypt.org","flags":128,"tag":"issue"}`,
+			want: DNSRecord{Subname: "@", Rectype: "CAA", Content: "letsencrypt.org", Flag: 128, Tag: "issue"},
+		},
+		{
+			name: "TTL as string",
+			json: `{"subname":"www","rectype":"A","content":"192.0.2.1","ttl":"3600"}`,
+			want: DNSRecord{Subname: "www", Rectype: "A", Content: "192.0.2.1", TTL: 3600},
+		},
+		{
+			name: "created_at",
+			json: `{"subname":"www","rectype":"A","content":"192.0.2.1","created_at":"2024-05-01T12:00:00Z"}`,
+			want: DNSRecord{Subname: "www", Rectype: "A", Content: "192.0.2.1", CreatedAt: "2024-05-01T12:00:00Z"},
+		},
+		{
+			name: "created_at as Unix time",
+			json: `{"subname":"www","rectype":"A","content":"192.0.2.1","created_at":1714564800}`,
+			want: DNSRecord{Subname: "www", Rectype: "A", Content: "192.0.2.1", CreatedAt: "2024-05-01T12:00:00Z"},
+		},
+		{
+			name: "created as Unix time",
+			json: `{"subname":"www","rectype":"A","content":"192.0.2.1","created":1714564800}`,
+			want: DNSRecord{Subname: "www", Rectype: "A", Content: "192.0.2.1", CreatedAt: "2024-05-01T12:00:00Z"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var record DNSRecord
+			if err := json.Unmarshal([]byte(tc.json), &record); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if record != tc.want {
+				t.Errorf("record = %+v, want %+v", record, tc.want)
+			}
+		})
+	}
+}
+
+func TestZoneResponseRelativeSubnames(t *testing.T) {
+	var response DNSZoneResponse
+	if err := json.Unmarshal([]byte(`{"result":"success","answer":{"domains":[{"dname":"example.com","result":"success","rrs":[
+		{"subname":"www.example.com","rectype":"A","content":"192.0.2.1"},
+		{"subname":"www","rectype":"A","content":"192.0.2.1"},
+		{"subname":"mail.Example.com.","rectype":"A","content":"192.0.2.2"},
+		{"subname":"example.com","rectype":"MX","content":"mail.example.com.","prio":10},
+		{"subname":"www.example.net","rectype":"CNAME","content":"example.net."}]}]}}`), &response); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	var subnames []string
+	for _, rr := range response.Answer.Domains[0].Rrs {
+		subnames = append(subnames, rr.Subname)
+	}
+	// The fully qualified www record repeats the relative one and is dropped
+	want := []string{"www", "mail", "@", "www.example.net"}
+	if !reflect.DeepEqual(subnames, want) {
+		t.Errorf("subnames = %q, want %q", subnames, want)
+	}
+}
+
+func TestRelativeSubname(t *testing.T) {
+	for _, tc := range []struct {
+		subname, zone, want string
+	}{
+		{"www", "example.com", "www"},
+		{"www.example.com", "example.com", "www"},
+		{"www.example.com.", "example.com.", "www"},
+		{"a.b.EXAMPLE.com", "example.com", "a.b"},
+		{"example.com", "example.com", "@"},
+		{"www.notexample.com", "example.com", "www.notexample.com"},
+		{"www.example.com", "", "www.example.com"},
+	} {
+		if got := RelativeSubname(tc.subname, tc.zone); got != tc.want {
+			t.Errorf("RelativeSubname(%q, %q) = %q, want %q", tc.subname, tc.zone, got, tc.want)
+		}
+	}
+}
@@ -1,10 +1,14 @@
 package base
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -17,15 +21,57 @@ func (c *CommonOperations) SetResourceID(d *schema.ResourceData, zone, name, rec
 	d.SetId(fmt.Sprintf("%s/%s", zone, name))
 }
 
-// ParseResourceID parses a resource ID into its components
+// ParseResourceID parses a resource ID into its components. A trailing record type segment,
+// as in zone/name/CAA, is accepted and ignored.
 func (c *CommonOperations) ParseResourceID(id string) (zone, name string, err error) {
 	parts := strings.Split(id, "/")
-	if len(parts) != 2 {
+	if len(parts) == 3 && isRecordTypeSegment(parts[2]) {
+		parts = parts[:2]
+	}
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
 		return "", "", fmt.Errorf("invalid resource ID format: %s", id)
 	}
 	return parts[0], parts[1], nil
 }
 
+// isRecordTypeSegment reports whether an ID segment names a record type such as CAA
+func isRecordTypeSegment(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	for _, r := range segment {
+		if !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseImportID parses an import ID given either as zone/name or as an FQDN.
+// FQDNs are split at the longest of the account's zones they belong to, so that zones such as
+// example.co.uk and delegated subzones are recognized; guessing from the number of labels would not.
+func (c *CommonOperations) ParseImportID(client interface{}, id string) (zone, name string, err error) {
+	if strings.Contains(id, "/") {
+		return c.ParseResourceID(id)
+	}
+
+	fqdn := strings.ToLower(c.NormalizeDomain(id))
+	if fqdn == "" {
+		return "", "", fmt.Errorf("invalid resource ID format: %s", id)
+	}
+
+	zones, err := AccountZones(client)
+	if err != nil {
+		return "", "", fmt.Errorf("cannot determine the zone for %s: %w; use the explicit zone/name import format (e.g. example.com/www)", id, err)
+	}
+
+	zone, name, err = SplitFQDN(fqdn, zones)
+	if err != nil {
+		return "", "", fmt.Errorf("%w; use the explicit zone/name import format instead", err)
+	}
+	return zone, name, nil
+}
+
 // SetCommonAttributes sets the common attributes for a DNS record
 func (c *CommonOperations) SetCommonAttributes(d *schema.ResourceData, zone, name, recordType string, records []interface{}) {
 	d.Set("zone", zone)
@@ -34,8 +80,12 @@ func (c *CommonOperations) SetCommonAttributes(d *schema.ResourceData, zone, nam
 	d.Set("records", records)
 }
 
-// AddTrailingDot adds a trailing dot to domain names if not present
-func (c *CommonOperations) AddTrailingDot(domain string) string {
+// AddTrailingDot adds a trailing dot to domain names if not present, unless the client sends
+// targets unchanged for an account whose API rejects trailing dots
+func (c *CommonOperations) AddTrailingDot(client interface{}, domain string) string {
+	if TrailingDotDisabled(client) {
+		return domain
+	}
 	if !strings.HasSuffix(domain, ".") {
 		return domain + "."
 	}
@@ -47,6 +97,71 @@ func (c *CommonOperations) NormalizeDomain(domain string) string {
 	return strings.TrimSuffix(domain, ".")
 }
 
+// RootTarget is the target denoting that a service is not available (RFC 2782, RFC 7505)
+const RootTarget = "."
+
+// ConfiguredForm returns the configured spelling of a domain read back from the API, so that
+// values differing only by a trailing dot or letter case do not show up as drift on refresh.
+// The root target is returned unchanged rather than normalized to an empty string.
+func (c *CommonOperations) ConfiguredForm(found string, configured []interface{}) string {
+	if strings.TrimSpace(found) == RootTarget {
+		return RootTarget
+	}
+	normalized := strings.ToLower(c.NormalizeDomain(found))
+	for _, value := range configured {
+		if str, ok := value.(string); ok && strings.ToLower(c.NormalizeDomain(str)) == normalized {
+			return str
+		}
+	}
+	return c.NormalizeDomain(found)
+}
+
+// ComparableContent normalizes record content for matching against the API's stored form
+func ComparableContent(content string) string {
+	content = strings.TrimSpace(content)
+	if len(content) >= 2 && strings.HasPrefix(content, "\"") && strings.HasSuffix(content, "\"") {
+		content = content[1 : len(content)-1]
+	}
+	return strings.ToLower(strings.TrimSuffix(content, "."))
+}
+
+// StoredContent returns the content of the record exactly as the API stores it, so that
+// removal requests match even when the server normalized the value. It falls back to the
+// given content when the record can't be found.
+func (c *CommonOperations) StoredContent(client CachedClientInterface, zone, name, recordType, content string) string {
+	record, err := client.GetRecord(zone, name, recordType, content)
+	if err != nil {
+		log.Printf("[DEBUG] Failed to look up stored %s record %s.%s -> %s: %v", recordType, name, zone, content, err)
+		return content
+	}
+	if record == nil || record.Content == content {
+		return content
+	}
+
+	log.Printf("[DEBUG] Using stored content %q instead of %q for %s record %s.%s", record.Content, content, recordType, name, zone)
+	return record.Content
+}
+
+// MatchesZone reports whether a domain name from an API response refers to the zone,
+// ignoring case and trailing dots
+func MatchesZone(dname, zone string) bool {
+	return strings.EqualFold(strings.TrimSuffix(dname, "."), strings.TrimSuffix(zone, "."))
+}
+
+// MatchesSubname reports whether a subname from an API response refers to the record name,
+// treating the empty subname the API may return for the apex as "@"
+func MatchesSubname(subname, name string) bool {
+	return normalizeSubname(subname) == normalizeSubname(name)
+}
+
+// normalizeSubname maps the apex spellings to "@" and ignores letter case
+func normalizeSubname(subname string) string {
+	if subname == "" {
+		return "@"
+	}
+	return strings.ToLower(subname)
+}
+
 // ValidateRecords validates that records list is not empty
 func (c *CommonOperations) ValidateRecords(records []interface{}) error {
 	if len(records) == 0 {
@@ -124,6 +239,110 @@ func (c *CommonOperations) OrderRecordsByConfiguration(foundRecords []string, co
 	return orderedRecords
 }
 
+// IsTimeoutError reports whether the error was caused by a request timeout
+func IsTimeoutError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// ReconcileAfterTimeout checks whether a write that failed with a timeout actually
+// succeeded server-side by re-reading the zone. It returns true when the record is present,
+// in which case the write must not be retried to avoid creating a duplicate.
+func (c *CommonOperations) ReconcileAfterTimeout(client CachedClientInterface, err error, zone, name, recordType, content string) bool {
+	expected := c.NormalizeDomain(content)
+	return c.ReconcileMatchingAfterTimeout(client, err, zone, name, recordType, content, func(rr DNSRecord) bool {
+		return c.NormalizeDomain(rr.Content) == expected
+	})
+}
+
+// ReconcileMatchingAfterTimeout is ReconcileAfterTimeout for record types whose identity is not
+// the content alone, such as SRV or CAA: a record of the type at the name counts as the written
+// one when matches accepts it. The description is used for logging only.
+func (c *CommonOperations) ReconcileMatchingAfterTimeout(client CachedClientInterface, err error, zone, name, recordType, description string, matches func(DNSRecord) bool) bool {
+	if !IsTimeoutError(err) {
+		return false
+	}
+
+	log.Printf("[DEBUG] Write of %s record %s.%s timed out, checking whether it was applied", recordType, name, zone)
+
+	client.InvalidateZoneCache(zone)
+	response, readErr := client.GetRecordsWithCache(zone)
+	if readErr != nil {
+		log.Printf("[DEBUG] Failed to re-read zone %s after timeout: %v", zone, readErr)
+		return false
+	}
+
+	var zoneResponse DNSZoneResponse
+	if err := json.Unmarshal(response, &zoneResponse); err != nil {
+		log.Printf("[DEBUG] Failed to parse zone %s after timeout: %v", zone, err)
+		return false
+	}
+
+	for _, domain := range zoneResponse.Answer.Domains {
+		for _, rr := range domain.Rrs {
+			if rr.Rectype == recordType && MatchesSubname(rr.Subname, name) && matches(rr) {
+				log.Printf("[DEBUG] %s record %s.%s -> %s is present after timeout, treating write as successful", recordType, name, zone, description)
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ReadAfterWriteRetries is the number of extra reads performed when a record written a moment
+// ago is not yet visible, and ReadAfterWriteDelay is the pause between them
+var (
+	ReadAfterWriteRetries = 2
+	ReadAfterWriteDelay   = 500 * time.Millisecond
+)
+
+// ReadAfterWrite runs a read right after a write. The API is eventually consistent and may briefly
+// omit a record that was just added, so when the read marks the resource as gone the zone is re-fetched
+// a few times before accepting that the record is absent. It must only be used following a write.
+func (c *CommonOperations) ReadAfterWrite(client CachedClientInterface, d *schema.ResourceData, zone string, read func() error) error {
+	id := d.Id()
+	for attempt := 0; ; attempt++ {
+		if err := read(); err != nil {
+			return err
+		}
+		if d.Id() != "" || attempt >= ReadAfterWriteRetries {
+			return nil
+		}
+
+		log.Printf("[DEBUG] Record %s not visible yet after write, re-reading (attempt %d of %d)", id, attempt+1, ReadAfterWriteRetries)
+		time.Sleep(ReadAfterWriteDelay)
+		d.SetId(id)
+		client.InvalidateZoneCache(zone)
+	}
+}
+
+// ConfirmAbsent re-fetches the zone bypassing the cache when a read found no records of the type
+// at zone/name, so a transient empty answer does not mark the resource as deleted. It returns the
+// records of the fresh read: none means the absence is confirmed. An error means the read was
+// inconclusive, and the resource must be kept in state rather than recreated over records that
+// may still exist.
+func (c *CommonOperations) ConfirmAbsent(client CachedClientInterface, zone, name, recordType string) ([]DNSRecord, error) {
+	log.Printf("[DEBUG] No %s records seen at %s.%s, re-reading the zone to confirm", recordType, name, zone)
+
+	client.InvalidateZoneCache(zone)
+	records, err := client.GetRecordsByType(zone, name, recordType)
+	if err != nil {
+		return nil, fmt.Errorf("could not confirm that %s records at %s.%s are gone: %w", recordType, name, zone, err)
+	}
+
+	if len(records) > 0 {
+		log.Printf("[WARN] %s records at %s.%s reappeared on a fresh read; the previous empty answer was transient", recordType, name, zone)
+	}
+	return records, nil
+}
+
 // InvalidateZoneCache invalidates the zone cache for a specific zone
 func (c *CommonOperations) InvalidateZoneCache(client interface{}, zone string) {
 	if cachedClient, ok := client.(interface {
@@ -142,6 +361,74 @@ func (c *CommonOperations) ClearZoneCache(client interface{}) {
 	}
 }
 
+// DomainListResponse represents the API response for the account's domain list
+type DomainListResponse struct {
+	Result string `json:"result"`
+	Answer struct {
+		Services []struct {
+			Dname    string `json:"dname"`
+			Servtype string `json:"servtype"`
+			State    string `json:"state"`
+		} `json:"services"`
+	} `json:"answer"`
+}
+
+// ParseDomainList extracts the zone names from a domain list response
+func ParseDomainList(response []byte) ([]string, error) {
+	var domainList DomainListResponse
+	if err := json.Unmarshal(response, &domainList); err != nil {
+		return nil, fmt.Errorf("failed to parse domain list response: %w", err)
+	}
+
+	var zones []string
+	for _, service := range domainList.Answer.Services {
+		if service.Dname != "" {
+			zones = append(zones, strings.ToLower(strings.TrimSuffix(service.Dname, ".")))
+		}
+	}
+	return zones, nil
+}
+
+// AccountZones returns the zones managed in the account
+func AccountZones(client interface{}) ([]string, error) {
+	domainClient, ok := client.(interface {
+		GetDomains() ([]byte, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("client does not support listing domains")
+	}
+
+	response, err := domainClient.GetDomains()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list account domains: %w", err)
+	}
+	return ParseDomainList(response)
+}
+
+// SplitFQDN splits an FQDN into the longest matching zone and the relative record name
+func SplitFQDN(fqdn string, zones []string) (zone, name string, err error) {
+	fqdn = strings.ToLower(strings.TrimSuffix(fqdn, "."))
+
+	for _, candidate := range zones {
+		candidate = strings.ToLower(strings.TrimSuffix(candidate, "."))
+		if candidate == "" || len(candidate) <= len(zone) {
+			continue
+		}
+		if fqdn == candidate || strings.HasSuffix(fqdn, "."+candidate) {
+			zone = candidate
+		}
+	}
+
+	if zone == "" {
+		return "", "", fmt.Errorf("no managed zone found for %s", fqdn)
+	}
+
+	if fqdn == zone {
+		return zone, "@", nil
+	}
+	return zone, strings.TrimSuffix(fqdn, "."+zone), nil
+}
+
 // APIErrorResponse represents an error response from the Reg.ru API
 type APIErrorResponse struct {
 	Answer struct {
@@ -151,66 +438,201 @@ type APIErrorResponse struct {
 			ErrorCode   string `json:"error_code"`
 			ErrorText   string `json:"error_text"`
 			ErrorParams struct {
-				ConflictingRecords []struct {
-					Data    string `json:"data"`
-					Rectype string `json:"rectype"`
-					Subname string `json:"subdomain"`
-				} `json:"conflicting_records"`
-				RecordToAdd struct {
-					Data    string `json:"data"`
-					Rectype string `json:"rectype"`
-					Subname string `json:"subdomain"`
-				} `json:"record_to_add"`
+				ConflictingRecords []APIErrorRecord `json:"conflicting_records"`
+				RecordToAdd        APIErrorRecord   `json:"record_to_add"`
 			} `json:"error_params"`
 		} `json:"domains"`
 	} `json:"answer"`
-	Result string `json:"result"`
+	Result    string `json:"result"`
+	ErrorCode string `json:"error_code"`
+	ErrorText string `json:"error_text"`
+}
+
+// APIErrorRecord is a record named in the parameters of an API error
+type APIErrorRecord struct {
+	Data    string `json:"data"`
+	Rectype string `json:"rectype"`
+	Subname string `json:"subdomain"`
+}
+
+// String renders the record as "subname type data"
+func (r APIErrorRecord) String() string {
+	subname := r.Subname
+	if subname == "" {
+		subname = "@"
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s %s %s", subname, r.Rectype, r.Data))
+}
+
+// conflictDetails describes the record being added and the existing records it conflicts with,
+// or returns an empty string when the error names no records
+func conflictDetails(recordToAdd APIErrorRecord, conflicting []APIErrorRecord) string {
+	var details []string
+	if recordToAdd != (APIErrorRecord{}) {
+		details = append(details, fmt.Sprintf("record to add: %s", recordToAdd))
+	}
+	if len(conflicting) > 0 {
+		records := make([]string, 0, len(conflicting))
+		for _, record := range conflicting {
+			records = append(records, record.String())
+		}
+		details = append(details, fmt.Sprintf("conflicting records: %s", strings.Join(records, ", ")))
+	}
+	return strings.Join(details, "; ")
 }
 
-// CheckAPIResponseForErrors checks if the API response contains errors
+// CheckAPIResponseForErrors checks if the API response contains errors. For conflicts, the
+// error names the record being added and the existing records it conflicts with.
 func CheckAPIResponseForErrors(response []byte) error {
+	return checkAPIResponse(response, func(string) bool { return false })
+}
+
+// recordNotFoundCodes are the API error codes reporting that a record to remove doesn't exist
+var recordNotFoundCodes = map[string]bool{
+	"RECORD_NOT_FOUND": true,
+	"RR_NOT_FOUND":     true,
+}
+
+// CheckRemoveResponse checks the result of a request removing a record. A record that no longer
+// exists, e.g. because it was removed outside Terraform, is already in the state Delete wants,
+// so the API reporting it as not found is not an error.
+func CheckRemoveResponse(response []byte, err error) error {
+	if err != nil {
+		var codedErr interface{ ErrorCode() string }
+		if errors.As(err, &codedErr) && recordNotFoundCodes[codedErr.ErrorCode()] {
+			log.Printf("[INFO] Record to remove no longer exists, treating it as removed: %v", err)
+			return nil
+		}
+		return err
+	}
+	return checkAPIResponse(response, func(code string) bool {
+		if recordNotFoundCodes[code] {
+			log.Printf("[INFO] Record to remove no longer exists (Error Code: %s), treating it as removed", code)
+			return true
+		}
+		return false
+	})
+}
+
+// checkAPIResponse checks if the API response contains errors other than those whose code is
+// accepted by ignore
+func checkAPIResponse(response []byte, ignore func(code string) bool) error {
 	var apiResponse APIErrorResponse
 	if err := json.Unmarshal(response, &apiResponse); err != nil {
 		// If we can't parse the response, assume it's not an error
 		return nil
 	}
 
-	// Check if the top-level result indicates an error
-	if apiResponse.Result == "error" {
-		var errorMessages []string
-
-		for _, domain := range apiResponse.Answer.Domains {
-			if domain.Result == "error" {
-				errorMsg := fmt.Sprintf("Domain %s: %s", domain.Dname, domain.ErrorText)
-				if domain.ErrorCode != "" {
-					errorMsg += fmt.Sprintf(" (Error Code: %s)", domain.ErrorCode)
-				}
-				errorMessages = append(errorMessages, errorMsg)
-			}
+	// A request rejected as a whole reports the error at the top level, possibly without an answer
+	if apiResponse.ErrorCode != "" && ignore(apiResponse.ErrorCode) {
+		return nil
+	}
+	if apiResponse.Result == "error" || apiResponse.ErrorCode != "" {
+		errorText := apiResponse.ErrorText
+		if errorText == "" {
+			errorText = "request rejected"
 		}
-
-		if len(errorMessages) > 0 {
-			return fmt.Errorf("API operation failed: %s", strings.Join(errorMessages, "; "))
+		if apiResponse.ErrorCode != "" {
+			return fmt.Errorf("API operation failed: %s (Error Code: %s)", errorText, apiResponse.ErrorCode)
 		}
+		return fmt.Errorf("API operation failed: %s", errorText)
 	}
 
-	// Check individual domain results
+	// Collect every domain entry that reports an error, either through its result
+	// or through an error code (some responses report success at the top level
+	// while a domain entry carries an error_code with an empty result)
+	var errorMessages []string
 	for _, domain := range apiResponse.Answer.Domains {
-		if domain.Result == "error" {
+		if domain.ErrorCode != "" && ignore(domain.ErrorCode) {
+			continue
+		}
+		if domain.Result == "error" || domain.ErrorCode != "" {
 			errorMsg := fmt.Sprintf("Domain %s: %s", domain.Dname, domain.ErrorText)
 			if domain.ErrorCode != "" {
 				errorMsg += fmt.Sprintf(" (Error Code: %s)", domain.ErrorCode)
 			}
-			return fmt.Errorf("API operation failed: %s", errorMsg)
+			if details := conflictDetails(domain.ErrorParams.RecordToAdd, domain.ErrorParams.ConflictingRecords); details != "" {
+				errorMsg += fmt.Sprintf(" [%s]", details)
+			}
+			errorMessages = append(errorMessages, errorMsg)
 		}
 	}
 
+	if len(errorMessages) > 0 {
+		return fmt.Errorf("API operation failed: %s", strings.Join(errorMessages, "; "))
+	}
+
 	return nil
 }
 
 // RecordsListDiffSuppressFunc provides a universal diff suppression function for record lists
 // It compares records as sets, ignoring order differences
 func RecordsListDiffSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
+	return suppressRecordsListDiff(k, d, nil)
+}
+
+// TXTRecordsDiffSuppressFunc compares TXT record lists as sets, treating long values written
+// as quoted chunks as equal to the single string they make up. With ignore_spf_case set, SPF
+// records that differ only in the case of their mechanisms (Include: vs include:) are equal too.
+func TXTRecordsDiffSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
+	ignoreSPFCase := false
+	if d != nil {
+		ignoreSPFCase, _ = d.Get("ignore_spf_case").(bool)
+	}
+	return suppressRecordsListDiff(k, d, func(value string) string {
+		if len(value) > MaxTXTStringLength {
+			value = JoinTXTChunks(value)
+		}
+		if ignoreSPFCase {
+			value = NormalizeSPF(value)
+		}
+		return value
+	})
+}
+
+// NormalizeSPF returns a comparable form of an SPF record, with repeated whitespace removed and
+// mechanisms, modifiers and domain names in lower case. Macro letters keep their case, since an
+// upper case letter asks for the expansion to be URL-escaped (RFC 7208, section 7.3). Values
+// that aren't SPF records are returned unchanged.
+func NormalizeSPF(value string) string {
+	unquoted := strings.Trim(strings.TrimSpace(value), "\"")
+	fields := strings.Fields(unquoted)
+	if len(fields) == 0 || !strings.EqualFold(fields[0], "v=spf1") {
+		return value
+	}
+	for i, field := range fields {
+		fields[i] = lowerSPFTerm(field)
+	}
+	return strings.Join(fields, " ")
+}
+
+// lowerSPFTerm lowers the case of an SPF term except for the letters of its macros (%{L})
+func lowerSPFTerm(term string) string {
+	lowered := []byte(term)
+	for i := 0; i < len(lowered); i++ {
+		switch {
+		case lowered[i] == '%' && i+1 < len(lowered) && lowered[i+1] == '{':
+			// Skip the brace and the macro letter after it
+			i += 2
+		case lowered[i] == '%':
+			// Skip the escaped character of %%, %_ and %-
+			i++
+		case lowered[i] >= 'A' && lowered[i] <= 'Z':
+			lowered[i] += 'a' - 'A'
+		}
+	}
+	return string(lowered)
+}
+
+// IsSPF reports whether a TXT value holds an SPF policy
+func IsSPF(value string) bool {
+	fields := strings.Fields(strings.Trim(strings.TrimSpace(value), "\""))
+	return len(fields) > 0 && strings.EqualFold(fields[0], "v=spf1")
+}
+
+// suppressRecordsListDiff compares the old and new records lists as multisets, applying
+// normalize to each record first when given
+func suppressRecordsListDiff(k string, d *schema.ResourceData, normalize func(string) string) bool {
 	// Safety check
 	if d == nil {
 		return false
@@ -226,6 +648,11 @@ func RecordsListDiffSuppressFunc(k, old, new string, d *schema.ResourceData) boo
 		return false
 	}
 
+	// Order is significant when the resource asks for it
+	if ordered, ok := d.Get("ordered").(bool); ok && ordered {
+		return false
+	}
+
 	// Use GetChange to get the actual old and new values
 	oldInterface, newInterface := d.GetChange("records")
 
@@ -246,6 +673,9 @@ func RecordsListDiffSuppressFunc(k, old, new string, d *schema.ResourceData) boo
 	for _, v := range oldRecords {
 		if v != nil {
 			if str, ok := v.(string); ok {
+				if normalize != nil {
+					str = normalize(str)
+				}
 				oldStrs = append(oldStrs, str)
 			}
 		}
@@ -255,6 +685,9 @@ func RecordsListDiffSuppressFunc(k, old, new string, d *schema.ResourceData) boo
 	for _, v := range newRecords {
 		if v != nil {
 			if str, ok := v.(string); ok {
+				if normalize != nil {
+					str = normalize(str)
+				}
 				newStrs = append(newStrs, str)
 			}
 		}
@@ -0,0 +1,82 @@
+package base
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckAPIResponseForErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		response string
+		want     string
+	}{
+		{
+			name:     "success",
+			response: `{"result":"success","answer":{"domains":[{"dname":"example.com","result":"success"}]}}`,
+		},
+		{
+			name:     "domain error",
+			response: `{"result":"success","answer":{"domains":[{"dname":"example.com","result":"error","error_code":"INVALID_IP","error_text":"Invalid IP"}]}}`,
+			want:     "INVALID_IP",
+		},
+		{
+			name:     "domain error code without result",
+			response: `{"result":"success","answer":{"domains":[{"dname":"example.com","result":"","error_code":"NO_SUCH_DOMAIN","error_text":"No such domain"}]}}`,
+			want:     "NO_SUCH_DOMAIN",
+		},
+		{
+			name: "conflicting records",
+			response: `{"result":"success","answer":{"domains":[{"dname":"example.com","result":"error","error_code":"CONFLICTS_WITH_EXISTING_RR","error_text":"Conflicts with existing record",
+				"error_params":{"record_to_add":{"subdomain":"www","rectype":"CNAME","data":"target.example.net."},
+				"conflicting_records":[{"subdomain":"www","rectype":"A","data":"192.0.2.1"},{"subdomain":"www","rectype":"A","data":"192.0.2.2"}]}}]}}`,
+			want: "[record to add: www CNAME target.example.net.; conflicting records: www A 192.0.2.1, www A 192.0.2.2]",
+		},
+		{
+			name:     "top-level error",
+			response: `{"result":"error","error_code":"ACCESS_DENIED","error_text":"Access denied"}`,
+			want:     "ACCESS_DENIED",
+		},
+		{
+			name:     "top-level error without code or answer",
+			response: `{"result":"error"}`,
+			want:     "request rejected",
+		},
+		{
+			name:     "top-level error code with success result",
+			response: `{"result":"success","error_code":"PASSWORD_AUTH_FAILED","error_text":"Username or password is incorrect"}`,
+			want:     "PASSWORD_AUTH_FAILED",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckAPIResponseForErrors([]byte(tc.response))
+			if tc.want == "" {
+				if err != nil {
+					t.Fatalf("CheckAPIResponseForErrors: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("CheckAPIResponseForErrors = %v, want an error with %s", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeSPF(t *testing.T) {
+	for _, tc := range []struct {
+		value string
+		want  string
+	}{
+		{"V=SPF1  Include:_SPF.Example.com ~ALL", "v=spf1 include:_spf.example.com ~all"},
+		{`"v=spf1 IP4:192.0.2.0/24 -all"`, "v=spf1 ip4:192.0.2.0/24 -all"},
+		// Upper case macro letters ask for URL-escaping, so they keep their case
+		{"v=spf1 Exists:%{L}.%{i}._SPF.example.com -all", "v=spf1 exists:%{L}.%{i}._spf.example.com -all"},
+		{"v=spf1 EXP=%{IR}.%_Explain.example.com", "v=spf1 exp=%{Ir}.%_explain.example.com"},
+		{"Site-Verification=ABC", "Site-Verification=ABC"},
+	} {
+		if got := NormalizeSPF(tc.value); got != tc.want {
+			t.Errorf("NormalizeSPF(%q) = %q, want %q", tc.value, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package base
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Record is a single DNS record value with the type-specific fields used by block-based resources
+type Record struct {
+	Priority int
+	Weight   int
+	Port     int
+	Flag     int
+	Tag      string
+	// Algorithm and FpType describe the key and hash of SSHFP records
+	Algorithm int
+	FpType    int
+	// Usage, Selector and MatchingType describe the certificate association of TLSA records
+	Usage        int
+	Selector     int
+	MatchingType int
+	// TTL is the TTL of an MX record in seconds, or 0 for the zone default
+	TTL   int
+	Value string // Server, target, CAA value, SSHFP fingerprint or TLSA certificate depending on the record type
+}
+
+// String returns a sortable string representation of the record
+func (r Record) String() string {
+	return fmt.Sprintf("%d_%d_%d_%d_%s_%d_%d_%d_%d_%d_%d_%s", r.Priority, r.Weight, r.Port, r.Flag, r.Tag,
+		r.Algorithm, r.FpType, r.Usage, r.Selector, r.MatchingType, r.TTL, r.Value)
+}
+
+// Format renders the record in zone-file notation for the given record type
+func (r Record) Format(recordType string) string {
+	switch recordType {
+	case "MX", "NS":
+		return fmt.Sprintf("%d %s", r.Priority, r.Value)
+	case "SRV":
+		return fmt.Sprintf("%d %d %d %s", r.Priority, r.Weight, r.Port, r.Value)
+	case "CAA":
+		return fmt.Sprintf("%d %s %q", r.Flag, r.Tag, r.Value)
+	case "SSHFP":
+		return fmt.Sprintf("%d %d %s", r.Algorithm, r.FpType, r.Value)
+	case "TLSA":
+		return fmt.Sprintf("%d %d %d %s", r.Usage, r.Selector, r.MatchingType, r.Value)
+	default:
+		return r.Value
+	}
+}
+
+// Less orders records by their numeric fields first, then by tag and value
+func (r Record) Less(other Record) bool {
+	if r.Priority != other.Priority {
+		return r.Priority < other.Priority
+	}
+	if r.Weight != other.Weight {
+		return r.Weight < other.Weight
+	}
+	if r.Port != other.Port {
+		return r.Port < other.Port
+	}
+	if r.Flag != other.Flag {
+		return r.Flag < other.Flag
+	}
+	if r.Tag != other.Tag {
+		return r.Tag < other.Tag
+	}
+	if r.Algorithm != other.Algorithm {
+		return r.Algorithm < other.Algorithm
+	}
+	if r.FpType != other.FpType {
+		return r.FpType < other.FpType
+	}
+	if r.Usage != other.Usage {
+		return r.Usage < other.Usage
+	}
+	if r.Selector != other.Selector {
+		return r.Selector < other.Selector
+	}
+	if r.MatchingType != other.MatchingType {
+		return r.MatchingType < other.MatchingType
+	}
+	if r.TTL != other.TTL {
+		return r.TTL < other.TTL
+	}
+	return r.Value < other.Value
+}
+
+// ParseSSHFPContent builds an SSHFP record from an API record, whose content holds the
+// fields in zone-file notation ("algorithm fp_type fingerprint"). Content in another
+// form is returned as the fingerprint with zero algorithm and type.
+func ParseSSHFPContent(record DNSRecord) Record {
+	parts := strings.Fields(record.Content)
+	if len(parts) != 3 {
+		return Record{Value: strings.TrimSpace(record.Content)}
+	}
+
+	algorithm, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return Record{Value: strings.TrimSpace(record.Content)}
+	}
+	fpType, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return Record{Value: strings.TrimSpace(record.Content)}
+	}
+	return Record{Algorithm: algorithm, FpType: fpType, Value: parts[2]}
+}
+
+// blockValuesField returns the name of the list field holding values within a record block,
+// or an empty string for types where every block holds a single value
+func blockValuesField(recordType string) string {
+	switch recordType {
+	case "MX", "NS":
+		return "servers"
+	case "SRV":
+		return "targets"
+	default:
+		return ""
+	}
+}
+
+// ParseTLSAContent builds a TLSA record from an API record. The content holds the fields in
+// zone-file notation ("usage selector matching_type certificate"), or only the certificate
+// when the API reports the numeric fields separately.
+func ParseTLSAContent(record DNSRecord) Record {
+	parsed := Record{
+		Usage:        record.Usage,
+		Selector:     record.Selector,
+		MatchingType: record.MatchingType,
+		Value:        strings.TrimSpace(record.Content),
+	}
+
+	parts := strings.Fields(record.Content)
+	if len(parts) != 4 {
+		return parsed
+	}
+	numbers := make([]int, 0, 3)
+	for _, part := range parts[:3] {
+		value, err := strconv.Atoi(part)
+		if err != nil {
+			return parsed
+		}
+		numbers = append(numbers, value)
+	}
+	return Record{Usage: numbers[0], Selector: numbers[1], MatchingType: numbers[2], Value: parts[3]}
+}
+
+// blockValueField returns the name of the field holding the value of a single-value record block
+func blockValueField(recordType string) string {
+	switch recordType {
+	case "SSHFP":
+		return "fingerprint"
+	case "TLSA":
+		return "certificate"
+	default:
+		return "value"
+	}
+}
+
+// RecordsFromSchema flattens record blocks of the given type into individual records.
+// Malformed blocks and values are skipped rather than causing a panic.
+func RecordsFromSchema(recordType string, blocks []interface{}) []Record {
+	var records []Record
+
+	for _, blockInterface := range blocks {
+		block, ok := blockInterface.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		template := Record{}
+		template.Priority, _ = block["priority"].(int)
+		template.Weight, _ = block["weight"].(int)
+		template.Port, _ = block["port"].(int)
+		template.Flag, _ = block["flag"].(int)
+		template.Tag, _ = block["tag"].(string)
+		template.Algorithm, _ = block["algorithm"].(int)
+		template.FpType, _ = block["fp_type"].(int)
+		template.Usage, _ = block["usage"].(int)
+		template.Selector, _ = block["selector"].(int)
+		template.MatchingType, _ = block["matching_type"].(int)
+		template.TTL, _ = block["ttl"].(int)
+
+		field := blockValuesField(recordType)
+		if field == "" {
+			value, ok := block[blockValueField(recordType)].(string)
+			if !ok {
+				continue
+			}
+			template.Value = value
+			records = append(records, template)
+			continue
+		}
+
+		values, ok := block[field].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, valueInterface := range values {
+			if value, ok := valueInterface.(string); ok {
+				record := template
+				record.Value = value
+				records = append(records, record)
+			}
+		}
+	}
+
+	return records
+}
+
+// RecordsToSchema converts records of the given type into sorted record blocks, grouping
+// values that share all other fields into one block for types with value lists. The blocks
+// have the shape ResourceData returns, so RecordsFromSchema reads them back.
+func RecordsToSchema(recordType string, records []Record) []interface{} {
+	sorted := make([]Record, len(records))
+	copy(sorted, records)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Less(sorted[j])
+	})
+
+	field := blockValuesField(recordType)
+	if field == "" {
+		blocks := make([]interface{}, 0, len(sorted))
+		for _, record := range sorted {
+			blocks = append(blocks, recordBlock(recordType, record))
+		}
+		return blocks
+	}
+
+	var blocks []interface{}
+	var current map[string]interface{}
+	var currentKey Record
+	for _, record := range sorted {
+		key := record
+		key.Value = ""
+		if current == nil || key != currentKey {
+			current = recordBlock(recordType, record)
+			current[field] = []interface{}{}
+			currentKey = key
+			blocks = append(blocks, current)
+		}
+		current[field] = append(current[field].([]interface{}), record.Value)
+	}
+
+	return blocks
+}
+
+// ConfiguredBlocks converts records read from the API into record blocks, keeping the configured
+// block layout when it describes exactly the records found. This lets a configuration give each
+// value its own block, e.g. one MX block per server with its own priority, without the grouping
+// by shared fields done by RecordsToSchema showing up as drift. Values are compared as found, so
+// callers should bring them into their configured spelling first.
+func ConfiguredBlocks(recordType string, found []Record, configured []interface{}) []interface{} {
+	if len(configured) > 0 && sameRecords(found, RecordsFromSchema(recordType, configured)) {
+		return configured
+	}
+	return RecordsToSchema(recordType, found)
+}
+
+// sameRecords reports whether two record lists hold the same records, ignoring order
+func sameRecords(a, b []Record) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	counts := make(map[Record]int, len(a))
+	for _, record := range a {
+		counts[record]++
+	}
+	for _, record := range b {
+		if counts[record] == 0 {
+			return false
+		}
+		counts[record]--
+	}
+	return true
+}
+
+// recordBlock builds the schema block for a record, without the value list for list-based types
+func recordBlock(recordType string, record Record) map[string]interface{} {
+	switch recordType {
+	case "MX":
+		return map[string]interface{}{"priority": record.Priority, "ttl": record.TTL}
+	case "NS":
+		return map[string]interface{}{"priority": record.Priority}
+	case "SRV":
+		return map[string]interface{}{"priority": record.Priority, "weight": record.Weight, "port": record.Port}
+	case "CAA":
+		return map[string]interface{}{"flag": record.Flag, "tag": record.Tag, "value": record.Value}
+	case "SSHFP":
+		return map[string]interface{}{"algorithm": record.Algorithm, "fp_type": record.FpType, "fingerprint": record.Value}
+	case "TLSA":
+		return map[string]interface{}{"usage": record.Usage, "selector": record.Selector, "matching_type": record.MatchingType, "certificate": record.Value}
+	default:
+		return map[string]interface{}{"value": record.Value}
+	}
+}
@@ -0,0 +1,108 @@
+package base
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRecordSchemaRoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		recordType string
+		records    []Record // sorted, as RecordsFromSchema returns them for the blocks
+		blocks     []interface{}
+	}{
+		{
+			recordType: "MX",
+			records: []Record{
+				{Priority: 10, Value: "mx1.example.com"},
+				{Priority: 10, Value: "mx2.example.com"},
+				{Priority: 20, TTL: 600, Value: "backup.example.net"},
+			},
+			blocks: []interface{}{
+				map[string]interface{}{"priority": 10, "ttl": 0, "servers": []interface{}{"mx1.example.com", "mx2.example.com"}},
+				map[string]interface{}{"priority": 20, "ttl": 600, "servers": []interface{}{"backup.example.net"}},
+			},
+		},
+		{
+			recordType: "NS",
+			records: []Record{
+				{Value: "ns1.example.net"},
+				{Value: "ns2.example.net"},
+			},
+			blocks: []interface{}{
+				map[string]interface{}{"priority": 0, "servers": []interface{}{"ns1.example.net", "ns2.example.net"}},
+			},
+		},
+		{
+			recordType: "SRV",
+			records: []Record{
+				{Priority: 10, Weight: 5, Port: 5060, Value: "sip1.example.com"},
+				{Priority: 10, Weight: 5, Port: 5061, Value: "sip2.example.com"},
+			},
+			blocks: []interface{}{
+				map[string]interface{}{"priority": 10, "weight": 5, "port": 5060, "targets": []interface{}{"sip1.example.com"}},
+				map[string]interface{}{"priority": 10, "weight": 5, "port": 5061, "targets": []interface{}{"sip2.example.com"}},
+			},
+		},
+		{
+			recordType: "CAA",
+			records: []Record{
+				{Tag: "iodef", Value: "mailto:security@example.com"},
+				{Flag: 128, Tag: "issue", Value: "letsencrypt.org"},
+			},
+			blocks: []interface{}{
+				map[string]interface{}{"flag": 0, "tag": "iodef", "value": "mailto:security@example.com"},
+				map[string]interface{}{"flag": 128, "tag": "issue", "value": "letsencrypt.org"},
+			},
+		},
+		{
+			recordType: "SSHFP",
+			records: []Record{
+				{Algorithm: 4, FpType: 2, Value: "abcdef"},
+			},
+			blocks: []interface{}{
+				map[string]interface{}{"algorithm": 4, "fp_type": 2, "fingerprint": "abcdef"},
+			},
+		},
+		{
+			recordType: "TLSA",
+			records: []Record{
+				{Usage: 3, Selector: 1, MatchingType: 1, Value: "abcdef"},
+			},
+			blocks: []interface{}{
+				map[string]interface{}{"usage": 3, "selector": 1, "matching_type": 1, "certificate": "abcdef"},
+			},
+		},
+	} {
+		t.Run(tc.recordType, func(t *testing.T) {
+			if got := RecordsFromSchema(tc.recordType, tc.blocks); !reflect.DeepEqual(got, tc.records) {
+				t.Errorf("RecordsFromSchema = %+v, want %+v", got, tc.records)
+			}
+
+			// Records in any order convert to the same blocks, which convert back to the records
+			reversed := make([]Record, 0, len(tc.records))
+			for i := len(tc.records) - 1; i >= 0; i-- {
+				reversed = append(reversed, tc.records[i])
+			}
+			blocks := RecordsToSchema(tc.recordType, reversed)
+			if !reflect.DeepEqual(blocks, tc.blocks) {
+				t.Errorf("RecordsToSchema = %v, want %v", blocks, tc.blocks)
+			}
+			if got := RecordsFromSchema(tc.recordType, blocks); !reflect.DeepEqual(got, tc.records) {
+				t.Errorf("records after a round trip = %+v, want %+v", got, tc.records)
+			}
+		})
+	}
+}
+
+func TestRecordsFromSchemaSkipsMalformedBlocks(t *testing.T) {
+	blocks := []interface{}{
+		"not a block",
+		map[string]interface{}{"priority": 10, "servers": "mx.example.com"},
+		map[string]interface{}{"priority": 20, "servers": []interface{}{42, "mx.example.com"}},
+	}
+	want := []Record{{Priority: 20, Value: "mx.example.com"}}
+	if got := RecordsFromSchema("MX", blocks); !reflect.DeepEqual(got, want) {
+		t.Errorf("RecordsFromSchema = %+v, want %+v", got, want)
+	}
+}
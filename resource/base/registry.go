@@ -0,0 +1,125 @@
+package base
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// RecordRegistry tracks which record types are managed at each zone/name within a single provider run
+type RecordRegistry struct {
+	// records maps each zone/name to the record type registered by each owner
+	records map[string]map[string]string
+	// owners maps each existing resource to the zone/name it registered
+	owners  map[string]string
+	written map[string]bool
+	mutex   sync.Mutex
+}
+
+// NewRecordRegistry creates a new record registry
+func NewRecordRegistry() *RecordRegistry {
+	return &RecordRegistry{
+		records: make(map[string]map[string]string),
+		owners:  make(map[string]string),
+		written: make(map[string]bool),
+	}
+}
+
+// Register records that a resource of the given type manages zone/name. The owner is the ID of an
+// existing resource, or empty for a resource being created. An existing resource registering again,
+// e.g. when it is replaced at another name, moves its registration rather than keeping both.
+// It returns an error when the registration would place a CNAME alongside another record type.
+func (r *RecordRegistry) Register(zone, name, recordType, owner string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := fmt.Sprintf("%s/%s", strings.ToLower(strings.TrimSuffix(zone, ".")), strings.ToLower(name))
+	if owner == "" {
+		// Resources being created can't be told apart, which only matters for different types
+		owner = recordType + " new"
+	} else {
+		owner = recordType + " " + owner
+		if previous, exists := r.owners[owner]; exists && previous != key {
+			delete(r.records[previous], owner)
+		}
+		r.owners[owner] = key
+	}
+
+	owners, exists := r.records[key]
+	if !exists {
+		owners = make(map[string]string)
+		r.records[key] = owners
+	}
+
+	seen := map[string]bool{recordType: true}
+	var others []string
+	for other, otherType := range owners {
+		if other != owner && !seen[otherType] {
+			seen[otherType] = true
+			others = append(others, otherType)
+		}
+	}
+	sort.Strings(others)
+
+	if len(others) > 0 && (recordType == "CNAME" || seen["CNAME"]) {
+		return fmt.Errorf("CNAME conflict at %s.%s: a CNAME record cannot coexist with other record types (%s also managed at this name)",
+			name, zone, strings.Join(append(others, recordType), ", "))
+	}
+
+	owners[owner] = recordType
+	return nil
+}
+
+// RegisterManagedRecord registers zone/name for the resource with the given ID, empty when it is
+// being created, with the client's record registry, if it has one
+func RegisterManagedRecord(client interface{}, zone, name, recordType, id string) error {
+	if registry := recordRegistry(client); registry != nil {
+		return registry.Register(zone, name, recordType, id)
+	}
+	return nil
+}
+
+// writtenKey identifies a record value independently of how its zone, name and content are spelled
+func writtenKey(zone, name, recordType, content string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", strings.ToLower(strings.TrimSuffix(zone, ".")), normalizeSubname(name), recordType, ComparableContent(content))
+}
+
+// MarkWritten records that a resource added the record value during this run
+func (r *RecordRegistry) MarkWritten(zone, name, recordType, content string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.written[writtenKey(zone, name, recordType, content)] = true
+}
+
+// Written reports whether a resource added the record value during this run
+func (r *RecordRegistry) Written(zone, name, recordType, content string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.written[writtenKey(zone, name, recordType, content)]
+}
+
+// recordRegistry returns the client's record registry, or nil when it has none
+func recordRegistry(client interface{}) *RecordRegistry {
+	if registryClient, ok := client.(interface {
+		RecordRegistry() *RecordRegistry
+	}); ok {
+		return registryClient.RecordRegistry()
+	}
+	return nil
+}
+
+// MarkRecordWritten notes in the client's record registry that the record value was added
+func MarkRecordWritten(client interface{}, zone, name, recordType, content string) {
+	if registry := recordRegistry(client); registry != nil {
+		registry.MarkWritten(zone, name, recordType, content)
+	}
+}
+
+// OwnedByReplacement reports whether another resource added the record value earlier in this run.
+// With create_before_destroy, a replacement resource is created before the resource it replaces is
+// deleted, so a value it wrote at the same zone/name must not be removed by the old resource.
+func OwnedByReplacement(client interface{}, zone, name, recordType, content string) bool {
+	registry := recordRegistry(client)
+	return registry != nil && registry.Written(zone, name, recordType, content)
+}
@@ -0,0 +1,45 @@
+package base
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ValidateTTL checks a TTL against the values the provider is configured to allow.
+// Any TTL is accepted when the client has no restriction configured.
+func ValidateTTL(client interface{}, ttl int) error {
+	ttlClient, ok := client.(interface {
+		AllowedTTLs() []int
+	})
+	if !ok {
+		return nil
+	}
+
+	allowed := ttlClient.AllowedTTLs()
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	for _, value := range allowed {
+		if value == ttl {
+			return nil
+		}
+	}
+
+	sorted := append([]int(nil), allowed...)
+	sort.Ints(sorted)
+	values := make([]string, len(sorted))
+	for i, value := range sorted {
+		values[i] = fmt.Sprintf("%d", value)
+	}
+	return fmt.Errorf("TTL %d is not allowed; valid values are: %s", ttl, strings.Join(values, ", "))
+}
+
+// TTLDiffSuppressFunc ignores the TTL reported by the API when the configuration doesn't set one,
+// so records using the server default don't produce a diff
+func TTLDiffSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
+	return new == "" || new == "0"
+}
@@ -0,0 +1,34 @@
+package base
+
+import (
+	"strings"
+	"testing"
+)
+
+// ttlClient stands in for a client configured with allowed_ttls
+type ttlClient []int
+
+func (c ttlClient) AllowedTTLs() []int { return c }
+
+func TestValidateTTL(t *testing.T) {
+	buckets := ttlClient{86400, 300, 3600, 600}
+
+	if err := ValidateTTL(buckets, 3600); err != nil {
+		t.Errorf("TTL 3600 rejected: %v", err)
+	}
+
+	err := ValidateTTL(buckets, 1800)
+	if err == nil {
+		t.Fatal("TTL 1800 accepted, want it rejected")
+	}
+	if want := "valid values are: 300, 600, 3600, 86400"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %q, want it to list the %s", err, want)
+	}
+
+	// Without a configured list any TTL is accepted
+	for _, client := range []interface{}{ttlClient(nil), struct{}{}} {
+		if err := ValidateTTL(client, 1800); err != nil {
+			t.Errorf("TTL 1800 rejected without restrictions: %v", err)
+		}
+	}
+}
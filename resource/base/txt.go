@@ -0,0 +1,66 @@
+package base
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxTXTStringLength is the maximum length in bytes of a single TXT character-string
+const MaxTXTStringLength = 255
+
+// ChunkTXTValue splits a TXT value into quoted strings of at most 255 bytes each, never
+// splitting inside a multi-byte UTF-8 character. Values that fit into one string are returned unchanged.
+func ChunkTXTValue(value string) string {
+	if len(value) <= MaxTXTStringLength {
+		return value
+	}
+
+	var chunks []string
+	for len(value) > 0 {
+		end := len(value)
+		if end > MaxTXTStringLength {
+			end = MaxTXTStringLength
+			for end > 0 && !utf8.RuneStart(value[end]) {
+				end--
+			}
+		}
+		chunks = append(chunks, "\""+value[:end]+"\"")
+		value = value[end:]
+	}
+
+	return strings.Join(chunks, " ")
+}
+
+// JoinTXTChunks reassembles a TXT value made of adjacent quoted strings into a single string.
+// Content that isn't made of quoted strings is returned unchanged.
+func JoinTXTChunks(content string) string {
+	trimmed := strings.TrimSpace(content)
+	if !strings.HasPrefix(trimmed, "\"") || !strings.HasSuffix(trimmed, "\"") {
+		return content
+	}
+
+	var builder strings.Builder
+	inString := false
+	for i := 0; i < len(trimmed); i++ {
+		ch := trimmed[i]
+		switch {
+		case ch == '\\' && inString && i+1 < len(trimmed):
+			i++
+			builder.WriteByte(trimmed[i])
+		case ch == '"':
+			inString = !inString
+		case inString:
+			builder.WriteByte(ch)
+		case ch == ' ' || ch == '\t':
+			// Whitespace between strings is not part of the value
+		default:
+			// Not a sequence of quoted strings
+			return content
+		}
+	}
+
+	if inString {
+		return content
+	}
+	return builder.String()
+}
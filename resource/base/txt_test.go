@@ -0,0 +1,52 @@
+package base
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestChunkTXTValue(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		value  string
+		chunks []string
+	}{
+		{
+			name:   "255 characters",
+			value:  strings.Repeat("a", 255),
+			chunks: []string{strings.Repeat("a", 255)},
+		},
+		{
+			name:   "256 characters",
+			value:  strings.Repeat("a", 256),
+			chunks: []string{strings.Repeat("a", 255), "a"},
+		},
+		{
+			// The 128th "é" spans bytes 254 and 255, so it starts the second string
+			name:   "multibyte character across the limit",
+			value:  strings.Repeat("é", 130),
+			chunks: []string{strings.Repeat("é", 127), strings.Repeat("é", 3)},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			chunked := ChunkTXTValue(tc.value)
+
+			want := tc.chunks[0]
+			if len(tc.chunks) > 1 {
+				want = "\"" + strings.Join(tc.chunks, "\" \"") + "\""
+			}
+			if chunked != want {
+				t.Errorf("ChunkTXTValue = %q, want %q", chunked, want)
+			}
+			for _, chunk := range tc.chunks {
+				if len(chunk) > MaxTXTStringLength || !utf8.ValidString(chunk) {
+					t.Errorf("chunk %q is not a valid string of at most %d bytes", chunk, MaxTXTStringLength)
+				}
+			}
+			if joined := JoinTXTChunks(chunked); joined != tc.value {
+				t.Errorf("JoinTXTChunks(ChunkTXTValue(value)) = %q, want the value back", joined)
+			}
+		})
+	}
+}
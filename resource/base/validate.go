@@ -0,0 +1,224 @@
+package base
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// CAATags lists the CAA property tags accepted by certificate authorities
+var CAATags = []string{"issue", "issuewild", "iodef", "issuemail", "issuevmc"}
+
+// ValidateRecordSpec checks a single record of the given type before it is written: address
+// families for A and AAAA, hostnames for CNAME, PTR, DNAME, MX, NS and SRV targets, numeric
+// ranges for priorities, weights, ports and CAA flags, CAA tags, SSHFP fingerprints, TLSA
+// certificate data and control characters in TXT values
+func ValidateRecordSpec(recordType string, record Record) error {
+	switch recordType {
+	case "A", "AAAA":
+		ip := net.ParseIP(record.Value)
+		switch {
+		case ip == nil && recordType == "A":
+			return fmt.Errorf("A record %q is not a valid IPv4 address", record.Value)
+		case ip == nil:
+			return fmt.Errorf("AAAA record %q is not a valid IPv6 address", record.Value)
+		case recordType == "A" && ip.To4() == nil:
+			return fmt.Errorf("%q is an IPv6 address; use regru_dns_aaaa_record for IPv6", record.Value)
+		case recordType == "AAAA" && ip.To4() != nil:
+			return fmt.Errorf("%q is an IPv4 address; use regru_dns_a_record for IPv4", record.Value)
+		}
+	case "TXT":
+		for i, r := range record.Value {
+			if unicode.IsControl(r) {
+				return fmt.Errorf("%q contains the control character %q at position %d; newlines and other control characters are not allowed", record.Value, r, i)
+			}
+		}
+	case "CNAME", "PTR", "DNAME":
+		return validateHostname(record.Value, false)
+	case "MX", "NS":
+		if err := validateRange("priority", record.Priority, 65535); err != nil {
+			return err
+		}
+		return validateHostname(record.Value, recordType == "MX")
+	case "SRV":
+		for _, field := range []struct {
+			name  string
+			value int
+		}{{"priority", record.Priority}, {"weight", record.Weight}, {"port", record.Port}} {
+			if err := validateRange(field.name, field.value, 65535); err != nil {
+				return err
+			}
+		}
+		return validateHostname(record.Value, true)
+	case "CAA":
+		if err := validateRange("flag", record.Flag, 255); err != nil {
+			return err
+		}
+		for _, tag := range CAATags {
+			if strings.EqualFold(record.Tag, tag) {
+				return nil
+			}
+		}
+		return fmt.Errorf("CAA tag %q is not supported, expected one of %s", record.Tag, strings.Join(CAATags, ", "))
+	case "SSHFP":
+		if err := validateRange("algorithm", record.Algorithm, 255); err != nil {
+			return err
+		}
+		if err := validateRange("fp_type", record.FpType, 255); err != nil {
+			return err
+		}
+		return validateHexData("SSHFP fingerprint", record.Value, "fp_type", record.FpType, sshfpFingerprintLengths)
+	case "TLSA":
+		for _, field := range []struct {
+			name  string
+			value int
+		}{{"usage", record.Usage}, {"selector", record.Selector}, {"matching_type", record.MatchingType}} {
+			if err := validateRange(field.name, field.value, 255); err != nil {
+				return err
+			}
+		}
+		return validateHexData("TLSA certificate", record.Value, "matching_type", record.MatchingType, tlsaDigestLengths)
+	}
+	return nil
+}
+
+// sshfpFingerprintLengths maps SSHFP fingerprint types to the hex length of their digests
+var sshfpFingerprintLengths = map[int]int{1: 40, 2: 64} // SHA-1, SHA-256
+
+// tlsaDigestLengths maps TLSA matching types to the hex length of their digests
+var tlsaDigestLengths = map[int]int{1: 64, 2: 128} // SHA-256, SHA-512
+
+// validateHexData checks that record data is hexadecimal and, for digest types with a
+// known length, that it has the length of the digest
+func validateHexData(field, value, typeField string, dataType int, lengths map[int]int) error {
+	if value == "" {
+		return fmt.Errorf("%s must not be empty", field)
+	}
+	for _, r := range value {
+		if !unicode.Is(unicode.ASCII_Hex_Digit, r) {
+			return fmt.Errorf("%s %q contains the non-hexadecimal character %q", field, value, r)
+		}
+	}
+	if length, known := lengths[dataType]; known && len(value) != length {
+		return fmt.Errorf("%s %q has %d hex digits, but %s %d requires %d", field, value, len(value), typeField, dataType, length)
+	}
+	return nil
+}
+
+// ValidateSPF checks the syntax of an SPF policy: the version prefix must appear only once and
+// the policy must end with an all mechanism, or hand evaluation over with a redirect modifier.
+// TXT values that aren't SPF policies are accepted.
+func ValidateSPF(value string) error {
+	if len(value) > MaxTXTStringLength {
+		value = JoinTXTChunks(value)
+	}
+	if !IsSPF(value) {
+		return nil
+	}
+	terms := strings.Fields(strings.Trim(strings.TrimSpace(value), "\""))
+
+	last, redirect := "", false
+	for _, term := range terms[1:] {
+		term = strings.ToLower(term)
+		if term == "v=spf1" {
+			return fmt.Errorf("SPF record %q contains the v=spf1 prefix more than once", value)
+		}
+		// Modifiers such as redirect= and exp= may follow the final mechanism
+		if i := strings.IndexAny(term, "=:/"); i >= 0 && term[i] == '=' {
+			redirect = redirect || strings.HasPrefix(term, "redirect=")
+			continue
+		}
+		if last == "all" {
+			return fmt.Errorf("SPF record %q has the mechanism %q after all, which is never evaluated", value, term)
+		}
+		last = strings.TrimLeft(term, "+-~?")
+	}
+
+	if last != "all" && !redirect {
+		return fmt.Errorf("SPF record %q does not end with an all mechanism such as ~all or -all", value)
+	}
+	return nil
+}
+
+// ValidateRecordSpecs checks every record of the given type, reporting all invalid records at once
+func ValidateRecordSpecs(recordType string, records []Record) error {
+	var errs []string
+	for _, record := range records {
+		if err := ValidateRecordSpec(recordType, record); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid %s records:\n%s", recordType, strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// ValidateResourceRecords checks the records configured on a resource, whichever of the
+// records, record, cname, ptrdname, target or value fields holds them
+func ValidateResourceRecords(recordType string, d *schema.ResourceData) error {
+	var records []Record
+	if values, ok := d.GetOk("records"); ok {
+		for _, value := range values.([]interface{}) {
+			if str, ok := value.(string); ok {
+				records = append(records, Record{Value: str})
+			}
+		}
+	} else if blocks, ok := d.GetOk("record"); ok {
+		records = RecordsFromSchema(recordType, blocks.([]interface{}))
+	} else if cname, ok := d.GetOk("cname"); ok {
+		records = append(records, Record{Value: cname.(string)})
+	} else if ptrdname, ok := d.GetOk("ptrdname"); ok {
+		records = append(records, Record{Value: ptrdname.(string)})
+	} else if target, ok := d.GetOk("target"); ok {
+		records = append(records, Record{Value: target.(string)})
+	} else if value, ok := d.GetOk("value"); ok {
+		records = append(records, Record{Value: value.(string)})
+	}
+
+	return ValidateRecordSpecs(recordType, records)
+}
+
+// validateRange checks that a numeric field lies between 0 and max
+func validateRange(field string, value, max int) error {
+	if value < 0 || value > max {
+		return fmt.Errorf("%s %d is out of range, expected 0 to %d", field, value, max)
+	}
+	return nil
+}
+
+// validateHostname checks that a value is a hostname rather than an IP address. The root
+// target "." is accepted where it denotes an unavailable service.
+func validateHostname(value string, allowRoot bool) error {
+	if allowRoot && value == RootTarget {
+		return nil
+	}
+
+	host := strings.TrimSuffix(value, ".")
+	if host == "" {
+		return fmt.Errorf("hostname must not be empty")
+	}
+	if net.ParseIP(host) != nil {
+		return fmt.Errorf("%q is an IP address, but the record must point to a hostname", value)
+	}
+	if len(host) > 253 {
+		return fmt.Errorf("hostname %q is longer than 253 characters", value)
+	}
+
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || len(label) > 63 {
+			return fmt.Errorf("hostname %q has an empty label or a label longer than 63 characters", value)
+		}
+		for _, r := range label {
+			// Letters beyond ASCII are allowed for internationalized names such as those under .рф
+			if !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '*') {
+				return fmt.Errorf("hostname %q contains the invalid character %q", value, r)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package base
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateRecordSpec(t *testing.T) {
+	sha256 := strings.Repeat("ab", 32)
+
+	for _, tc := range []struct {
+		name       string
+		recordType string
+		record     Record
+		wantErr    string // empty when the record is valid
+	}{
+		{"A", "A", Record{Value: "192.0.2.1"}, ""},
+		{"A not an address", "A", Record{Value: "192.0.2"}, "not a valid IPv4 address"},
+		{"A with IPv6", "A", Record{Value: "2001:db8::1"}, "use regru_dns_aaaa_record"},
+		{"AAAA", "AAAA", Record{Value: "2001:db8::1"}, ""},
+		{"AAAA not an address", "AAAA", Record{Value: "2001:db8::g"}, "not a valid IPv6 address"},
+		{"AAAA with IPv4", "AAAA", Record{Value: "192.0.2.1"}, "use regru_dns_a_record"},
+
+		{"TXT", "TXT", Record{Value: "v=spf1 -all"}, ""},
+		{"TXT with newline", "TXT", Record{Value: "v=spf1\n-all"}, "control character"},
+
+		{"CNAME", "CNAME", Record{Value: "www.example.com."}, ""},
+		{"CNAME with IP", "CNAME", Record{Value: "192.0.2.1"}, "must point to a hostname"},
+		{"CNAME root", "CNAME", Record{Value: "."}, "must not be empty"},
+		{"CNAME invalid character", "CNAME", Record{Value: "www example.com"}, "invalid character"},
+		{"CNAME empty label", "CNAME", Record{Value: "www..example.com"}, "empty label"},
+		{"CNAME long label", "CNAME", Record{Value: strings.Repeat("a", 64) + ".example.com"}, "longer than 63"},
+		{"PTR", "PTR", Record{Value: "host.example.com."}, ""},
+		{"PTR with IP", "PTR", Record{Value: "192.0.2.1"}, "must point to a hostname"},
+		{"DNAME internationalized", "DNAME", Record{Value: "пример.рф."}, ""},
+
+		{"MX", "MX", Record{Priority: 10, Value: "mx1.example.com"}, ""},
+		{"MX root", "MX", Record{Priority: 0, Value: "."}, ""},
+		{"MX priority out of range", "MX", Record{Priority: 65536, Value: "mx1.example.com"}, "priority 65536 is out of range"},
+		{"MX with IP", "MX", Record{Priority: 10, Value: "192.0.2.1"}, "must point to a hostname"},
+		{"NS", "NS", Record{Value: "ns1.example.net."}, ""},
+		{"NS root", "NS", Record{Value: "."}, "must not be empty"},
+
+		{"SRV", "SRV", Record{Priority: 10, Weight: 5, Port: 5060, Value: "sip.example.com."}, ""},
+		{"SRV root target", "SRV", Record{Value: "."}, ""},
+		{"SRV negative weight", "SRV", Record{Weight: -1, Value: "sip.example.com."}, "weight -1 is out of range"},
+		{"SRV port out of range", "SRV", Record{Port: 70000, Value: "sip.example.com."}, "port 70000 is out of range"},
+		{"SRV with IP", "SRV", Record{Port: 5060, Value: "192.0.2.1"}, "must point to a hostname"},
+
+		{"CAA", "CAA", Record{Tag: "issue", Value: "letsencrypt.org"}, ""},
+		{"CAA tag in upper case", "CAA", Record{Tag: "ISSUEWILD", Value: "letsencrypt.org"}, ""},
+		{"CAA unknown tag", "CAA", Record{Tag: "issuer", Value: "letsencrypt.org"}, `CAA tag "issuer" is not supported`},
+		{"CAA flag out of range", "CAA", Record{Flag: 256, Tag: "issue"}, "flag 256 is out of range"},
+
+		{"SSHFP", "SSHFP", Record{Algorithm: 4, FpType: 2, Value: sha256}, ""},
+		{"SSHFP unknown type", "SSHFP", Record{Algorithm: 4, FpType: 9, Value: "abcd"}, ""},
+		{"SSHFP not hex", "SSHFP", Record{Algorithm: 4, FpType: 2, Value: "xyz"}, "non-hexadecimal"},
+		{"SSHFP digest length", "SSHFP", Record{Algorithm: 4, FpType: 1, Value: sha256}, "requires 40"},
+		{"SSHFP algorithm out of range", "SSHFP", Record{Algorithm: 256, FpType: 2, Value: sha256}, "algorithm 256 is out of range"},
+		{"TLSA", "TLSA", Record{Usage: 3, Selector: 1, MatchingType: 1, Value: sha256}, ""},
+		{"TLSA empty", "TLSA", Record{Usage: 3, Selector: 1, MatchingType: 1}, "must not be empty"},
+		{"TLSA digest length", "TLSA", Record{Usage: 3, Selector: 1, MatchingType: 2, Value: sha256}, "requires 128"},
+		{"TLSA selector out of range", "TLSA", Record{Usage: 3, Selector: -1, MatchingType: 1, Value: sha256}, "selector -1 is out of range"},
+
+		{"unchecked type", "SPF", Record{Value: "anything"}, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateRecordSpec(tc.recordType, tc.record)
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Errorf("ValidateRecordSpec(%s, %+v) = %v, want no error", tc.recordType, tc.record, err)
+			case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+				t.Errorf("ValidateRecordSpec(%s, %+v) = %v, want an error containing %q", tc.recordType, tc.record, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateRecordSpecsReportsAllRecords(t *testing.T) {
+	err := ValidateRecordSpecs("A", []Record{
+		{Value: "192.0.2.1"},
+		{Value: "192.0.2.300"},
+		{Value: "2001:db8::1"},
+	})
+	if err == nil {
+		t.Fatal("ValidateRecordSpecs succeeded, want an error")
+	}
+	for _, want := range []string{"invalid A records", "192.0.2.300", "2001:db8::1"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), `"192.0.2.1"`) {
+		t.Errorf("error %q mentions the valid record", err)
+	}
+}
+
+func TestValidateSPF(t *testing.T) {
+	for _, tc := range []struct {
+		value   string
+		wantErr string // empty when the value is accepted
+	}{
+		{"v=spf1 include:_spf.example.com -all", ""},
+		{"V=SPF1 ip4:192.0.2.0/24 ~ALL", ""},
+		{`"v=spf1 mx ?all"`, ""},
+		{"v=spf1 include:_spf.example.com -all exp=explain.example.com", ""},
+		{"v=spf1 redirect=_spf.example.com", ""},
+		{"google-site-verification=abc123", ""},
+		{"v=spf1 include:_spf.example.com", "does not end with an all mechanism"},
+		{"v=spf1 -all v=spf1 +all", "more than once"},
+		{"v=spf1 -all include:_spf.example.com", "after all"},
+	} {
+		err := ValidateSPF(tc.value)
+		switch {
+		case tc.wantErr == "" && err != nil:
+			t.Errorf("ValidateSPF(%q) = %v, want no error", tc.value, err)
+		case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+			t.Errorf("ValidateSPF(%q) = %v, want an error containing %q", tc.value, err, tc.wantErr)
+		}
+	}
+}
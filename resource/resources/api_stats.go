@@ -0,0 +1,70 @@
+package resources
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// DataSourceAPIStats creates the debug data source reporting the API requests made so far in the run
+func DataSourceAPIStats() *schema.Resource {
+	return &schema.Resource{
+		Read: readAPIStats,
+		Schema: map[string]*schema.Schema{
+			"request_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of API requests made by the provider so far in this run",
+			},
+			"cache_hits": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of zone reads served from the zone cache so far in this run",
+			},
+			"cache_misses": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of zone reads not found in the zone cache so far in this run",
+			},
+			"cache_failure_hits": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of zone reads answered with a briefly cached read failure so far in this run",
+			},
+			"cache_hit_ratio": {
+				Type:        schema.TypeFloat,
+				Computed:    true,
+				Description: "The share of zone reads served with zone data from the zone cache, between 0 and 1",
+			},
+		},
+	}
+}
+
+// readAPIStats reads the request counter and the zone cache statistics of the client
+func readAPIStats(d *schema.ResourceData, meta interface{}) error {
+	counter, ok := meta.(interface {
+		RequestCount() int64
+	})
+	if !ok {
+		return fmt.Errorf("client does not count API requests")
+	}
+
+	// The count changes between reads, so the data source is never cached by ID
+	d.SetId(fmt.Sprintf("%d", time.Now().UnixNano()))
+	d.Set("request_count", int(counter.RequestCount()))
+
+	if cache, ok := meta.(interface {
+		ZoneCacheStats() (hits, misses, failureHits int64)
+	}); ok {
+		hits, misses, failureHits := cache.ZoneCacheStats()
+		d.Set("cache_hits", int(hits))
+		d.Set("cache_misses", int(misses))
+		d.Set("cache_failure_hits", int(failureHits))
+		if total := hits + misses + failureHits; total > 0 {
+			d.Set("cache_hit_ratio", float64(hits)/float64(total))
+		}
+	}
+
+	return nil
+}
@@ -1,8 +1,10 @@
 package resources
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net"
 	"sort"
 	"strconv"
 	"strings"
@@ -10,7 +12,9 @@ import (
 	"terraform-provider-regru/resource/base"
 	"terraform-provider-regru/resource/strategies"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 // GenericDiffSuppressFunc provides a unified diff suppression function for nested record blocks
@@ -29,6 +33,8 @@ func GenericDiffSuppressFunc(k, old, new string, d *schema.ResourceData, config
 		return handleCAARecordDiff(k, d)
 	case "nested_field":
 		return handleNestedFieldDiff(k, d, config.FieldName)
+	case "record_set":
+		return handleRecordSetDiff(k, d, config.FieldName)
 	default:
 		return false
 	}
@@ -36,8 +42,8 @@ func GenericDiffSuppressFunc(k, old, new string, d *schema.ResourceData, config
 
 // DiffSuppressConfig defines the configuration for diff suppression
 type DiffSuppressConfig struct {
-	FieldType string // "caa_record" or "nested_field"
-	FieldName string // Field name within record block (e.g., "servers", "targets")
+	FieldType string // "caa_record", "nested_field" or "record_set"
+	FieldName string // Field name within record block (e.g., "servers", "targets"), or the record type for "record_set"
 }
 
 // handleCAARecordDiff handles CAA record diff suppression
@@ -278,6 +284,57 @@ func handleNestedFieldDiff(k string, d *schema.ResourceData, fieldName string) b
 	return true
 }
 
+// handleRecordSetDiff compares the records described by the old and new record blocks as sets,
+// so blocks that are split or grouped differently (e.g. one priority across two blocks) don't produce a diff
+func handleRecordSetDiff(k string, d *schema.ResourceData, recordType string) bool {
+	if d == nil {
+		return false
+	}
+
+	oldRecordsInterface, newRecordsInterface := d.GetChange("record")
+	oldBlocks, oldOk := oldRecordsInterface.([]interface{})
+	newBlocks, newOk := newRecordsInterface.([]interface{})
+	if !oldOk || !newOk || len(oldBlocks) == 0 {
+		return false
+	}
+
+	oldRecords := base.RecordsFromSchema(recordType, oldBlocks)
+	newRecords := base.RecordsFromSchema(recordType, newBlocks)
+	if len(oldRecords) == 0 || len(oldRecords) != len(newRecords) {
+		return false
+	}
+
+	oldStrs := make([]string, len(oldRecords))
+	for i, record := range oldRecords {
+		oldStrs[i] = record.String()
+	}
+	newStrs := make([]string, len(newRecords))
+	for i, record := range newRecords {
+		newStrs[i] = record.String()
+	}
+
+	sort.Strings(oldStrs)
+	sort.Strings(newStrs)
+	for i, oldStr := range oldStrs {
+		if oldStr != newStrs[i] {
+			return false
+		}
+	}
+
+	log.Printf("[DEBUG] handleRecordSetDiff: Suppressing grouping-only diff for %s", k)
+	return true
+}
+
+// MXRecordsDiffSuppressFunc compares MX record blocks by the records they describe,
+// matching the one-block-per-priority shape produced by Read
+func MXRecordsDiffSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
+	config := DiffSuppressConfig{
+		FieldType: "record_set",
+		FieldName: "MX",
+	}
+	return GenericDiffSuppressFunc(k, old, new, d, config)
+}
+
 // MXServersDiffSuppressFunc compares MX server lists as sets, ignoring order differences
 func MXServersDiffSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
 	config := DiffSuppressConfig{
@@ -305,6 +362,24 @@ func CAARecordsDiffSuppressFunc(k, old, new string, d *schema.ResourceData) bool
 	return GenericDiffSuppressFunc(k, old, new, d, config)
 }
 
+// SSHFPRecordsDiffSuppressFunc compares SSHFP records as sets, ignoring order differences
+func SSHFPRecordsDiffSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
+	config := DiffSuppressConfig{
+		FieldType: "record_set",
+		FieldName: "SSHFP",
+	}
+	return GenericDiffSuppressFunc(k, old, new, d, config)
+}
+
+// TLSARecordsDiffSuppressFunc compares TLSA records as sets, ignoring order differences
+func TLSARecordsDiffSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
+	config := DiffSuppressConfig{
+		FieldType: "record_set",
+		FieldName: "TLSA",
+	}
+	return GenericDiffSuppressFunc(k, old, new, d, config)
+}
+
 // NSServersDiffSuppressFunc compares NS server lists as sets, ignoring order differences
 func NSServersDiffSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
 	config := DiffSuppressConfig{
@@ -314,6 +389,88 @@ func NSServersDiffSuppressFunc(k, old, new string, d *schema.ResourceData) bool
 	return GenericDiffSuppressFunc(k, old, new, d, config)
 }
 
+// CNAMEDiffSuppressFunc ignores differences in the trailing dot and letter case of a CNAME or DNAME target
+func CNAMEDiffSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
+	if old == "" || new == "" {
+		return false
+	}
+	return strings.EqualFold(strings.TrimSuffix(old, "."), strings.TrimSuffix(new, "."))
+}
+
+// ValidateTXTValue rejects TXT values containing newlines or other control characters,
+// which usually end up in a value by accident when it is pasted and produce broken records
+func ValidateTXTValue(v interface{}, k string) (warnings []string, errors []error) {
+	value, ok := v.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected %s to be a string", k))
+		return warnings, errors
+	}
+
+	if err := base.ValidateRecordSpec("TXT", base.Record{Value: value}); err != nil {
+		errors = append(errors, fmt.Errorf("%s: %w", k, err))
+	}
+	return warnings, errors
+}
+
+// ValidateIPv4Value rejects A record values that are not IPv4 addresses, so typos such as
+// 192.168.1.999 fail at plan time instead of with an API error on apply
+func ValidateIPv4Value(v interface{}, k string) (warnings []string, errors []error) {
+	value, ok := v.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected %s to be a string", k))
+		return warnings, errors
+	}
+
+	if err := base.ValidateRecordSpec("A", base.Record{Value: value}); err != nil {
+		errors = append(errors, fmt.Errorf("%s: %w", k, err))
+	}
+	return warnings, errors
+}
+
+// ValidateIPv6Value rejects AAAA record values that are not IPv6 addresses, including IPv4
+// addresses put in an AAAA resource by mistake
+func ValidateIPv6Value(v interface{}, k string) (warnings []string, errors []error) {
+	value, ok := v.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected %s to be a string", k))
+		return warnings, errors
+	}
+
+	if err := base.ValidateRecordSpec("AAAA", base.Record{Value: value}); err != nil {
+		errors = append(errors, fmt.Errorf("%s: %w", k, err))
+	}
+	return warnings, errors
+}
+
+// ValidateHostnameNotIP rejects IP addresses where DNS requires a hostname (MX and NS targets)
+func ValidateHostnameNotIP(v interface{}, k string) (warnings []string, errors []error) {
+	value, ok := v.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected %s to be a string", k))
+		return warnings, errors
+	}
+
+	if net.ParseIP(strings.TrimSuffix(value, ".")) != nil {
+		errors = append(errors, fmt.Errorf("%s: %q is an IP address, but MX and NS records must point to a hostname (e.g. mail.example.com)", k, value))
+	}
+	return warnings, errors
+}
+
+// ValidateSRVTarget rejects SRV targets that are not hostnames, such as IP addresses, at plan
+// time. The root target "." is accepted, as it marks a service as unavailable (RFC 2782).
+func ValidateSRVTarget(v interface{}, k string) (warnings []string, errors []error) {
+	value, ok := v.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected %s to be a string", k))
+		return warnings, errors
+	}
+
+	if err := base.ValidateRecordSpec("SRV", base.Record{Value: value}); err != nil {
+		errors = append(errors, fmt.Errorf("%s: %w", k, err))
+	}
+	return warnings, errors
+}
+
 // ResourceConfig defines the configuration for creating a DNS record resource
 type ResourceConfig struct {
 	RecordType      string
@@ -321,6 +478,11 @@ type ResourceConfig struct {
 	ExtraFields     map[string]*schema.Schema
 	StrategyFactory func() interface{} // Returns the strategy for this record type
 	UsesGenericCRUD bool               // Whether to use generic CRUD functions
+
+	// RecordsDiffSuppressFunc replaces the default set comparison of the records list
+	RecordsDiffSuppressFunc schema.SchemaDiffSuppressFunc
+	// RecordsValidateFunc validates each value of the records list
+	RecordsValidateFunc schema.SchemaValidateFunc
 }
 
 // CreateDNSRecordResource creates a Terraform resource for DNS records
@@ -339,17 +501,77 @@ func CreateDNSRecordResource(config ResourceConfig) *schema.Resource {
 			ForceNew:    true,
 			Description: "The name for this record (use @ for root domain)",
 		},
+		"credentials": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "Credentials of the Reg.ru account owning the zone, overriding the provider credentials for this resource",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"username": {
+						Type:        schema.TypeString,
+						Required:    true,
+						Description: "Reg.ru username",
+					},
+					"password": {
+						Type:        schema.TypeString,
+						Required:    true,
+						Sensitive:   true,
+						Description: "Reg.ru alternative password",
+					},
+				},
+			},
+		},
+		"ttl": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "The TTL of the records as reported by the API",
+		},
+		"created_at": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The creation time of the earliest record, when the API reports one",
+		},
+		"effective_records": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "The records the provider considers managed by this resource after normalization, in zone-file notation",
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
 	}
 
 	// Add records field for simple record types
 	if config.UsesGenericCRUD {
+		recordsDiffSuppressFunc := config.RecordsDiffSuppressFunc
+		if recordsDiffSuppressFunc == nil {
+			recordsDiffSuppressFunc = base.RecordsListDiffSuppressFunc
+		}
 		baseSchema["records"] = &schema.Schema{
 			Type:             schema.TypeList,
 			Required:         true,
-			MinItems:         1,
 			Description:      config.Description,
-			Elem:             &schema.Schema{Type: schema.TypeString},
-			DiffSuppressFunc: base.RecordsListDiffSuppressFunc,
+			Elem:             &schema.Schema{Type: schema.TypeString, ValidateFunc: config.RecordsValidateFunc},
+			DiffSuppressFunc: recordsDiffSuppressFunc,
+		}
+		baseSchema["ordered"] = &schema.Schema{
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Preserve the configured order of records. When enabled, reordering records produces a diff and the records are re-created in the new order",
+		}
+		baseSchema["ttl"] = &schema.Schema{
+			Type:             schema.TypeInt,
+			Optional:         true,
+			Computed:         true,
+			Description:      "The TTL of the records in seconds. Records get the zone default when unset; the TTL reported by the API is exposed either way",
+			ValidateFunc:     validation.IntAtLeast(1),
+			DiffSuppressFunc: base.TTLDiffSuppressFunc,
+		}
+		baseSchema["allow_empty"] = &schema.Schema{
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Allow an empty records list, which removes all managed records while keeping the resource so it can be repopulated later",
 		}
 	}
 
@@ -378,14 +600,290 @@ func CreateDNSRecordResource(config ResourceConfig) *schema.Resource {
 		importFunc = createSpecificImportFunc(config.RecordType, config.StrategyFactory)
 	}
 
+	readFunc = withEffectiveRecords(config.RecordType, readFunc)
+	createFunc = withEffectiveRecords(config.RecordType, createFunc)
+	updateFunc = withEffectiveRecords(config.RecordType, updateFunc)
+	createFunc = withZoneWriteGuard(createFunc)
+	updateFunc = withZoneWriteGuard(updateFunc)
+	deleteFunc = withZoneWriteGuard(deleteFunc)
+	createFunc = withCredentials(createFunc)
+	readFunc = withCredentials(readFunc)
+	updateFunc = withCredentials(updateFunc)
+	deleteFunc = withCredentials(deleteFunc)
+
 	return &schema.Resource{
-		Schema:   baseSchema,
-		Create:   createFunc,
-		Read:     readFunc,
-		Update:   updateFunc,
-		Delete:   deleteFunc,
-		Importer: &schema.ResourceImporter{State: importFunc},
+		Schema:        baseSchema,
+		CreateContext: withContext(createFunc),
+		ReadContext:   withOverlapWarning(config.RecordType, readFunc),
+		UpdateContext: withContext(updateFunc),
+		DeleteContext: withContext(deleteFunc),
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				return importFunc(d, base.ContextClient(meta, ctx))
+			},
+		},
+		CustomizeDiff: createCustomizeDiffFunc(config.RecordType, baseSchema["ttl"].Optional, config.UsesGenericCRUD),
+	}
+}
+
+// withContext adapts a CRUD function to one whose API requests are cancelled with the
+// operation's context, so an interrupted run stops waiting on the API
+func withContext(next func(d *schema.ResourceData, meta interface{}) error) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		return diag.FromErr(next(d, base.ContextClient(meta, ctx)))
+	}
+}
+
+// withCredentials wraps a CRUD function so it uses the resource's credentials override, if any
+func withCredentials(next func(d *schema.ResourceData, meta interface{}) error) func(d *schema.ResourceData, meta interface{}) error {
+	return func(d *schema.ResourceData, meta interface{}) error {
+		return next(d, credentialsClient(d, meta))
+	}
+}
+
+// credentialsClient returns the client using the credentials override of the resource data or
+// diff, or the client unchanged when the resource has none or they aren't known yet
+func credentialsClient(d interface{ Get(string) interface{} }, meta interface{}) interface{} {
+	blocks, ok := d.Get("credentials").([]interface{})
+	if !ok || len(blocks) == 0 {
+		return meta
+	}
+	credentials, ok := blocks[0].(map[string]interface{})
+	if !ok {
+		return meta
+	}
+	username, _ := credentials["username"].(string)
+	password, _ := credentials["password"].(string)
+	if username == "" {
+		return meta
+	}
+	return base.ScopedClient(meta, username, password)
+}
+
+// withEffectiveRecords wraps a read or write function and exposes the records it leaves in state
+// as effective_records, so the attribute is known right after create and update too
+func withEffectiveRecords(recordType string, next func(d *schema.ResourceData, meta interface{}) error) func(d *schema.ResourceData, meta interface{}) error {
+	return func(d *schema.ResourceData, meta interface{}) error {
+		if err := next(d, meta); err != nil {
+			return err
+		}
+		if d.Id() == "" {
+			return nil
+		}
+
+		var effective []string
+		if records := recordSet(d, recordType); records != nil {
+			effective = records
+		} else if cname, ok := d.GetOk("cname"); ok {
+			effective = append(effective, cname.(string))
+		} else if ptrdname, ok := d.GetOk("ptrdname"); ok {
+			effective = append(effective, ptrdname.(string))
+		} else if target, ok := d.GetOk("target"); ok {
+			effective = append(effective, target.(string))
+		} else if value, ok := d.GetOk("value"); ok {
+			effective = append(effective, value.(string))
+		}
+
+		if err := d.Set("effective_records", effective); err != nil {
+			return err
+		}
+
+		return setRecordMetadata(d, meta, recordType)
+	}
+}
+
+// setRecordMetadata sets the ttl and created_at attributes from the records at the resource's name
+func setRecordMetadata(d *schema.ResourceData, meta interface{}, recordType string) error {
+	c, ok := meta.(base.CachedClientInterface)
+	if !ok {
+		return nil
+	}
+
+	records, err := c.GetRecordsByType(d.Get("zone").(string), d.Get("name").(string), recordType)
+	if err != nil {
+		return err
+	}
+
+	ttl := 0
+	createdAt := ""
+	for _, record := range records {
+		if ttl == 0 && record.TTL > 0 {
+			ttl = record.TTL
+		}
+		if record.CreatedAt != "" && (createdAt == "" || record.CreatedAt < createdAt) {
+			createdAt = record.CreatedAt
+		}
+	}
+
+	if ttl > 0 {
+		if err := d.Set("ttl", ttl); err != nil {
+			return err
+		}
+	}
+	return d.Set("created_at", createdAt)
+}
+
+// withZoneWriteGuard wraps a write function with the check for concurrent zone modifications
+func withZoneWriteGuard(next func(d *schema.ResourceData, meta interface{}) error) func(d *schema.ResourceData, meta interface{}) error {
+	return func(d *schema.ResourceData, meta interface{}) error {
+		return base.GuardZoneWrite(meta, d.Get("zone").(string), func() error {
+			return next(d, meta)
+		})
+	}
+}
+
+// withOverlapWarning wraps a read function and warns when the zone holds more records
+// at the name than the resource manages, indicating another resource or external changes
+func withOverlapWarning(recordType string, next func(d *schema.ResourceData, meta interface{}) error) schema.ReadContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		meta = base.ContextClient(meta, ctx)
+
+		var managed []string
+		if d.Id() != "" {
+			managed = recordSet(d, recordType)
+		}
+
+		if err := next(d, meta); err != nil {
+			return diag.FromErr(err)
+		}
+
+		if len(managed) == 0 || d.Id() == "" {
+			return nil
+		}
+
+		managedSet := make(map[string]bool)
+		for _, record := range managed {
+			managedSet[record] = true
+		}
+
+		var extra []string
+		for _, record := range recordSet(d, recordType) {
+			if !managedSet[record] {
+				extra = append(extra, record)
+			}
+		}
+
+		if len(extra) == 0 {
+			return nil
+		}
+
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Unmanaged %s records found at %s.%s", recordType, d.Get("name").(string), d.Get("zone").(string)),
+			Detail: fmt.Sprintf("The zone contains %s records that this resource did not configure: %s. "+
+				"They may be managed by another resource or added outside Terraform. The refresh added them to this resource's state, "+
+				"so the plan shows them being removed; applying it deletes them from the zone unless they are added to the configuration.",
+				recordType, strings.Join(extra, ", ")),
+		}}
+	}
+}
+
+// recordSet returns the records of a resource managing a list of records or record blocks, in
+// zone-file notation, or nil for a resource managing a single value
+func recordSet(d *schema.ResourceData, recordType string) []string {
+	if records, ok := d.Get("records").([]interface{}); ok {
+		result := []string{}
+		for _, record := range records {
+			if str, ok := record.(string); ok {
+				result = append(result, str)
+			}
+		}
+		return result
 	}
+	if blocks, ok := d.Get("record").([]interface{}); ok {
+		records := base.RecordsFromSchema(recordType, blocks)
+		sort.Slice(records, func(i, j int) bool {
+			return records[i].Less(records[j])
+		})
+		result := []string{}
+		for _, record := range records {
+			result = append(result, record.Format(recordType))
+		}
+		return result
+	}
+	return nil
+}
+
+// createCustomizeDiffFunc creates the plan-time checks shared by all record types
+func createCustomizeDiffFunc(recordType string, hasTTL, usesRecordsList bool) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		// An empty records list is only accepted when the resource allows it
+		if usesRecordsList && d.NewValueKnown("records") {
+			if records, _ := d.Get("records").([]interface{}); len(records) == 0 && !d.Get("allow_empty").(bool) {
+				return fmt.Errorf("records must contain at least one %s record; set allow_empty = true to remove all records while keeping the resource", recordType)
+			}
+		}
+
+		// Reject TTLs the API would refuse before anything is applied
+		if hasTTL && d.NewValueKnown("ttl") {
+			if ttl, ok := d.GetOk("ttl"); ok {
+				if err := base.ValidateTTL(meta, ttl.(int)); err != nil {
+					return err
+				}
+			}
+		}
+		if recordType == "MX" && d.NewValueKnown("record") {
+			blocks, _ := d.Get("record").([]interface{})
+			for _, record := range base.RecordsFromSchema(recordType, blocks) {
+				if record.TTL > 0 {
+					if err := base.ValidateTTL(meta, record.TTL); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		// Zone and name may not be known until apply
+		if !d.NewValueKnown("zone") || !d.NewValueKnown("name") {
+			return nil
+		}
+
+		zone := d.Get("zone").(string)
+		name := d.Get("name").(string)
+		if zone == "" || name == "" {
+			return nil
+		}
+
+		// Checks against the zone read it with the credentials the resource will be applied with
+		meta = credentialsClient(d, meta)
+
+		if err := base.RegisterManagedRecord(meta, zone, name, recordType, d.Id()); err != nil {
+			return err
+		}
+		if d.Id() == "" && (recordType == "CNAME" || recordType == "DNAME") {
+			if err := checkAliasConflict(meta, recordType, zone, name); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// checkAliasConflict rejects a new CNAME record at a name already holding a DNAME record in the
+// zone, and the other way round, since a name can't hold both. Names managed in this run are
+// checked by the record registry; this catches records created outside Terraform.
+func checkAliasConflict(meta interface{}, recordType, zone, name string) error {
+	c, ok := meta.(base.CachedClientInterface)
+	if !ok {
+		return nil
+	}
+
+	other := "DNAME"
+	if recordType == "DNAME" {
+		other = "CNAME"
+	}
+	records, err := c.GetRecordsByType(zone, name, other)
+	if err != nil {
+		// The zone may not exist yet or be unreadable during plan; apply reports any real problem
+		log.Printf("[DEBUG] Could not check %s.%s for a %s record: %v", name, zone, other, err)
+		return nil
+	}
+	if len(records) > 0 {
+		return fmt.Errorf("%s conflict at %s.%s: the zone already has a %s record (%s) at this name, and a name can't hold both CNAME and DNAME records",
+			recordType, name, zone, other, records[0].Content)
+	}
+	return nil
 }
 
 // createGenericCRUDFunc creates a generic CRUD function for simple record types
@@ -507,6 +1005,8 @@ func ResourceDNSARecord() *schema.Resource {
 		Description:     "List of IPv4 addresses for this A record",
 		StrategyFactory: func() interface{} { return strategies.NewARecordStrategy() },
 		UsesGenericCRUD: true,
+
+		RecordsValidateFunc: ValidateIPv4Value,
 	})
 }
 
@@ -517,17 +1017,83 @@ func ResourceDNSAAAARecord() *schema.Resource {
 		Description:     "List of IPv6 addresses for this AAAA record",
 		StrategyFactory: func() interface{} { return strategies.NewAAAARecordStrategy() },
 		UsesGenericCRUD: true,
+
+		RecordsValidateFunc: ValidateIPv6Value,
 	})
 }
 
 // ResourceDNSTXTRecord creates the TXT record resource
 func ResourceDNSTXTRecord() *schema.Resource {
-	return CreateDNSRecordResource(ResourceConfig{
-		RecordType:      "TXT",
-		Description:     "List of text values for this TXT record",
+	resource := CreateDNSRecordResource(ResourceConfig{
+		RecordType:  "TXT",
+		Description: "List of text values for this TXT record",
+		ExtraFields: map[string]*schema.Schema{
+			"validate_spf": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Check the syntax of values starting with v=spf1 at plan time. Other values are not affected",
+			},
+			"ignore_spf_case": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Treat values starting with v=spf1 that differ only in the case of their mechanisms, modifiers and domain names as unchanged. Macro letters are still compared with case",
+			},
+		},
 		StrategyFactory: func() interface{} { return strategies.NewTXTRecordStrategy() },
 		UsesGenericCRUD: true,
+
+		RecordsDiffSuppressFunc: base.TXTRecordsDiffSuppressFunc,
+		RecordsValidateFunc:     ValidateTXTValue,
 	})
+
+	// A validate function can't see validate_spf, so the opt-in check runs with the plan
+	customizeDiff := resource.CustomizeDiff
+	resource.CustomizeDiff = func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		if d.Get("validate_spf").(bool) && d.NewValueKnown("records") {
+			if err := validateSPFRecords(d.Get("records").([]interface{})); err != nil {
+				return err
+			}
+		}
+		return customizeDiff(ctx, d, meta)
+	}
+
+	return resource
+}
+
+// ValidateSPFValue checks the syntax of a TXT value holding an SPF policy. Other TXT values are accepted.
+func ValidateSPFValue(v interface{}, k string) (warnings []string, errors []error) {
+	value, ok := v.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected %s to be a string", k))
+		return warnings, errors
+	}
+
+	if err := base.ValidateSPF(value); err != nil {
+		errors = append(errors, fmt.Errorf("%s: %w", k, err))
+	}
+	return warnings, errors
+}
+
+// validateSPFRecords checks every SPF policy in a TXT records list, and that there is at most
+// one, since receivers treat a name with several SPF policies as a permanent error
+func validateSPFRecords(records []interface{}) error {
+	spfCount := 0
+	for i, record := range records {
+		value, _ := record.(string)
+		if _, errs := ValidateSPFValue(value, fmt.Sprintf("records.%d", i)); len(errs) > 0 {
+			return errs[0]
+		}
+		if base.IsSPF(value) {
+			spfCount++
+		}
+	}
+
+	if spfCount > 1 {
+		return fmt.Errorf("records contain %d values starting with v=spf1, but only one SPF policy may exist at a name", spfCount)
+	}
+	return nil
 }
 
 // ResourceDNSNSRecord creates the NS record resource
@@ -552,7 +1118,7 @@ func ResourceDNSNSRecord() *schema.Resource {
 							Required:         true,
 							MinItems:         1,
 							Description:      "List of name server hostnames for this NS record set",
-							Elem:             &schema.Schema{Type: schema.TypeString},
+							Elem:             &schema.Schema{Type: schema.TypeString, ValidateFunc: ValidateHostnameNotIP},
 							DiffSuppressFunc: NSServersDiffSuppressFunc,
 						},
 					},
@@ -570,9 +1136,10 @@ func ResourceDNSCNAMERecord() *schema.Resource {
 		RecordType: "CNAME",
 		ExtraFields: map[string]*schema.Schema{
 			"cname": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The canonical name (target domain) for this CNAME record",
+				Type:             schema.TypeString,
+				Required:         true,
+				Description:      "The canonical name (target domain) for this CNAME record",
+				DiffSuppressFunc: CNAMEDiffSuppressFunc,
 			},
 		},
 		StrategyFactory: func() interface{} { return strategies.NewCNAMERecordStrategy() },
@@ -580,16 +1147,52 @@ func ResourceDNSCNAMERecord() *schema.Resource {
 	})
 }
 
+// ResourceDNSPTRRecord creates the PTR record resource
+func ResourceDNSPTRRecord() *schema.Resource {
+	return CreateDNSRecordResource(ResourceConfig{
+		RecordType: "PTR",
+		ExtraFields: map[string]*schema.Schema{
+			"ptrdname": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "The domain name this PTR record points to",
+				ValidateFunc: ValidateHostnameNotIP,
+			},
+		},
+		StrategyFactory: func() interface{} { return strategies.NewPTRRecordStrategy() },
+		UsesGenericCRUD: false,
+	})
+}
+
+// ResourceDNSDNAMERecord creates the DNAME record resource
+func ResourceDNSDNAMERecord() *schema.Resource {
+	return CreateDNSRecordResource(ResourceConfig{
+		RecordType: "DNAME",
+		ExtraFields: map[string]*schema.Schema{
+			"target": {
+				Type:             schema.TypeString,
+				Required:         true,
+				Description:      "The domain the subtree below this name is redirected to",
+				ValidateFunc:     ValidateHostnameNotIP,
+				DiffSuppressFunc: CNAMEDiffSuppressFunc,
+			},
+		},
+		StrategyFactory: func() interface{} { return strategies.NewDNAMERecordStrategy() },
+		UsesGenericCRUD: false,
+	})
+}
+
 // ResourceDNSMXRecord creates the MX record resource
 func ResourceDNSMXRecord() *schema.Resource {
 	return CreateDNSRecordResource(ResourceConfig{
 		RecordType: "MX",
 		ExtraFields: map[string]*schema.Schema{
 			"record": {
-				Type:        schema.TypeList,
-				Required:    true,
-				MinItems:    1,
-				Description: "List of MX record sets with priority and servers",
+				Type:             schema.TypeList,
+				Required:         true,
+				MinItems:         1,
+				Description:      "List of MX record sets with priority and servers",
+				DiffSuppressFunc: MXRecordsDiffSuppressFunc,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"priority": {
@@ -602,9 +1205,15 @@ func ResourceDNSMXRecord() *schema.Resource {
 							Required:         true,
 							MinItems:         1,
 							Description:      "List of mail server hostnames for this MX record set",
-							Elem:             &schema.Schema{Type: schema.TypeString},
+							Elem:             &schema.Schema{Type: schema.TypeString, ValidateFunc: ValidateHostnameNotIP},
 							DiffSuppressFunc: MXServersDiffSuppressFunc,
 						},
+						"ttl": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Description:  "The TTL of the records in this set in seconds. The records get the zone default when unset",
+							ValidateFunc: validation.IntAtLeast(1),
+						},
 					},
 				},
 			},
@@ -627,26 +1236,29 @@ func ResourceDNSSRVRecord() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"priority": {
-							Type:        schema.TypeInt,
-							Required:    true,
-							Description: "The priority for this SRV record set (lower number = higher priority)",
+							Type:         schema.TypeInt,
+							Required:     true,
+							Description:  "The priority for this SRV record set (lower number = higher priority)",
+							ValidateFunc: validation.IntBetween(0, 65535),
 						},
 						"weight": {
-							Type:        schema.TypeInt,
-							Required:    true,
-							Description: "The weight for this SRV record set (used for load balancing within the same priority)",
+							Type:         schema.TypeInt,
+							Required:     true,
+							Description:  "The weight for this SRV record set (used for load balancing within the same priority)",
+							ValidateFunc: validation.IntBetween(0, 65535),
 						},
 						"port": {
-							Type:        schema.TypeInt,
-							Required:    true,
-							Description: "The port number for this SRV record set",
+							Type:         schema.TypeInt,
+							Required:     true,
+							Description:  "The port number for this SRV record set",
+							ValidateFunc: validation.IntBetween(0, 65535),
 						},
 						"targets": {
 							Type:             schema.TypeList,
 							Required:         true,
 							MinItems:         1,
 							Description:      "List of target hostnames for this SRV record set",
-							Elem:             &schema.Schema{Type: schema.TypeString},
+							Elem:             &schema.Schema{Type: schema.TypeString, ValidateFunc: ValidateSRVTarget},
 							DiffSuppressFunc: SRVTargetsDiffSuppressFunc,
 						},
 					},
@@ -658,6 +1270,45 @@ func ResourceDNSSRVRecord() *schema.Resource {
 	})
 }
 
+// ResourceDNSSSHFPRecord creates the SSHFP record resource
+func ResourceDNSSSHFPRecord() *schema.Resource {
+	return CreateDNSRecordResource(ResourceConfig{
+		RecordType: "SSHFP",
+		ExtraFields: map[string]*schema.Schema{
+			"record": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "List of SSHFP records with algorithm, fingerprint type, and fingerprint",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"algorithm": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							Description:  "The SSH key algorithm (1 = RSA, 2 = DSA, 3 = ECDSA, 4 = Ed25519, 6 = Ed448)",
+							ValidateFunc: validation.IntBetween(0, 255),
+						},
+						"fp_type": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							Description:  "The fingerprint type (1 = SHA-1, 2 = SHA-256)",
+							ValidateFunc: validation.IntBetween(0, 255),
+						},
+						"fingerprint": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The hexadecimal fingerprint of the host key",
+						},
+					},
+				},
+				DiffSuppressFunc: SSHFPRecordsDiffSuppressFunc,
+			},
+		},
+		StrategyFactory: func() interface{} { return strategies.NewSSHFPRecordStrategy() },
+		UsesGenericCRUD: false,
+	})
+}
+
 // ResourceDNSCAARecord creates the CAA record resource
 func ResourceDNSCAARecord() *schema.Resource {
 	return CreateDNSRecordResource(ResourceConfig{
@@ -689,8 +1340,126 @@ func ResourceDNSCAARecord() *schema.Resource {
 				},
 				DiffSuppressFunc: CAARecordsDiffSuppressFunc,
 			},
+			"managed_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Manage only the configured CAA tuples, leaving other CAA records at the name untouched",
+			},
 		},
 		StrategyFactory: func() interface{} { return strategies.NewCAARecordStrategy() },
 		UsesGenericCRUD: false,
 	})
 }
+
+// ResourceDNSTLSARecord creates the TLSA record resource
+func ResourceDNSTLSARecord() *schema.Resource {
+	return CreateDNSRecordResource(ResourceConfig{
+		RecordType: "TLSA",
+		ExtraFields: map[string]*schema.Schema{
+			"record": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "List of TLSA records with usage, selector, matching type, and certificate data",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"usage": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							Description:  "Certificate usage (0 = PKIX-TA, 1 = PKIX-EE, 2 = DANE-TA, 3 = DANE-EE)",
+							ValidateFunc: validation.IntBetween(0, 255),
+						},
+						"selector": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							Description:  "Selector (0 = full certificate, 1 = SubjectPublicKeyInfo)",
+							ValidateFunc: validation.IntBetween(0, 255),
+						},
+						"matching_type": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							Description:  "Matching type (0 = exact match, 1 = SHA-256, 2 = SHA-512)",
+							ValidateFunc: validation.IntBetween(0, 255),
+						},
+						"certificate": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The certificate association data in hexadecimal",
+						},
+					},
+				},
+				DiffSuppressFunc: TLSARecordsDiffSuppressFunc,
+			},
+		},
+		StrategyFactory: func() interface{} { return strategies.NewTLSARecordStrategy() },
+		UsesGenericCRUD: false,
+	})
+}
+
+// ResourceDNSDKIMRecord creates the DKIM record resource, a TXT record published under <selector>._domainkey
+func ResourceDNSDKIMRecord() *schema.Resource {
+	resource := CreateDNSRecordResource(ResourceConfig{
+		RecordType: "TXT",
+		ExtraFields: map[string]*schema.Schema{
+			"selector": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The DKIM selector; the record is published as <selector>._domainkey",
+			},
+			"value": {
+				Type:             schema.TypeString,
+				Required:         true,
+				Description:      "The DKIM key record (e.g. v=DKIM1; k=rsa; p=...). Long keys are split into 255-byte strings automatically",
+				ValidateFunc:     validateDKIMValue,
+				DiffSuppressFunc: DKIMValueDiffSuppressFunc,
+			},
+		},
+		StrategyFactory: func() interface{} { return strategies.NewDKIMRecordStrategy() },
+		UsesGenericCRUD: false,
+	})
+
+	// The record name is derived from the selector
+	resource.Schema["name"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The name of the TXT record (<selector>._domainkey)",
+	}
+
+	customizeDiff := resource.CustomizeDiff
+	resource.CustomizeDiff = func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		if d.NewValueKnown("selector") {
+			name := strategies.DKIMRecordName(d.Get("selector").(string))
+			if d.Get("name").(string) != name {
+				if err := d.SetNew("name", name); err != nil {
+					return err
+				}
+			}
+		}
+		return customizeDiff(ctx, d, meta)
+	}
+
+	return resource
+}
+
+// DKIMValueDiffSuppressFunc ignores differences in how a DKIM key is split into quoted strings
+func DKIMValueDiffSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
+	return strategies.NormalizeDKIMValue(old) == strategies.NormalizeDKIMValue(new)
+}
+
+// validateDKIMValue checks that the value is a DKIM key record
+func validateDKIMValue(v interface{}, k string) (warnings []string, errors []error) {
+	value, ok := v.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("%q must be a string", k))
+		return warnings, errors
+	}
+
+	if !strings.HasPrefix(strategies.NormalizeDKIMValue(value), strategies.DKIMKeyPrefix) {
+		errors = append(errors, fmt.Errorf("%q must start with %q, got %q", k, strategies.DKIMKeyPrefix, value))
+	}
+
+	_, textErrors := ValidateTXTValue(value, k)
+	return warnings, append(errors, textErrors...)
+}
@@ -0,0 +1,550 @@
+package resources_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"terraform-provider-regru/internal/fakeclient"
+	"terraform-provider-regru/resource/base"
+	"terraform-provider-regru/resource/resources"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// accountsClient is a fake client standing for the provider account, switching to the fake
+// client of another account when given its username as credentials
+type accountsClient struct {
+	*fakeclient.Client
+	accounts map[string]*fakeclient.Client
+}
+
+func (a *accountsClient) WithCredentials(username, password string) base.CachedClientInterface {
+	return a.accounts[username]
+}
+
+// planCreate plans the creation of a resource with the given configuration
+func planCreate(t *testing.T, r *schema.Resource, raw map[string]interface{}, meta interface{}) error {
+	t.Helper()
+	_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(raw), meta)
+	return err
+}
+
+func TestPlanChecksUseResourceCredentials(t *testing.T) {
+	other := fakeclient.New("example.com")
+	other.SetRecords("example.com", []base.DNSRecord{{Subname: "docs", Rectype: "DNAME", Content: "example.net."}})
+	meta := &accountsClient{Client: fakeclient.New("example.com"), accounts: map[string]*fakeclient.Client{"other": other}}
+
+	config := map[string]interface{}{"zone": "example.com", "name": "docs", "cname": "docs.example.net"}
+	if err := planCreate(t, resources.ResourceDNSCNAMERecord(), config, meta); err != nil {
+		t.Fatalf("plan with the provider credentials: %v", err)
+	}
+
+	// The zone of the other account holds a DNAME record at the name
+	config["credentials"] = []interface{}{map[string]interface{}{"username": "other", "password": "secret"}}
+	err := planCreate(t, resources.ResourceDNSCNAMERecord(), config, meta)
+	if err == nil || !strings.Contains(err.Error(), "CNAME conflict") {
+		t.Fatalf("plan with the resource credentials: err = %v, want a CNAME conflict", err)
+	}
+}
+
+// effectiveRecords returns the effective_records attribute of the resource data
+func effectiveRecords(d *schema.ResourceData) []string {
+	result := []string{}
+	for _, record := range d.Get("effective_records").([]interface{}) {
+		result = append(result, record.(string))
+	}
+	return result
+}
+
+func TestEffectiveRecordsSetOnWrite(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	r := resources.ResourceDNSMXRecord()
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"zone": "example.com",
+		"name": "@",
+		"record": []interface{}{
+			map[string]interface{}{"priority": 20, "servers": []interface{}{"mx2.example.com"}},
+			map[string]interface{}{"priority": 10, "servers": []interface{}{"mx1.example.com"}},
+		},
+	})
+	if diags := r.CreateContext(context.Background(), d, fake); diags.HasError() {
+		t.Fatalf("Create: %v", diags)
+	}
+	want := []string{"10 mx1.example.com", "20 mx2.example.com"}
+	if got := effectiveRecords(d); !reflect.DeepEqual(got, want) {
+		t.Errorf("effective_records after create = %q, want %q", got, want)
+	}
+
+	state := d.State()
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"zone": "example.com",
+		"name": "@",
+		"record": []interface{}{
+			map[string]interface{}{"priority": 10, "servers": []interface{}{"mx1.example.com"}},
+		},
+	})
+	diff, err := schema.InternalMap(r.Schema).Diff(context.Background(), state, config, nil, nil, true)
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	d, err = schema.InternalMap(r.Schema).Data(state, diff)
+	if err != nil {
+		t.Fatalf("data: %v", err)
+	}
+	if diags := r.UpdateContext(context.Background(), d, fake); diags.HasError() {
+		t.Fatalf("Update: %v", diags)
+	}
+	want = []string{"10 mx1.example.com"}
+	if got := effectiveRecords(d); !reflect.DeepEqual(got, want) {
+		t.Errorf("effective_records after update = %q, want %q", got, want)
+	}
+}
+
+func TestReadSetsTTLFromAPI(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	fake.SetRecords("example.com", []base.DNSRecord{
+		{Subname: "www", Rectype: "A", Content: "192.0.2.1", TTL: 3600},
+		{Subname: "www", Rectype: "A", Content: "192.0.2.2", TTL: 3600},
+		{Subname: "mail", Rectype: "A", Content: "192.0.2.3", TTL: 600},
+	})
+	r := resources.ResourceDNSARecord()
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"zone":    "example.com",
+		"name":    "www",
+		"records": []interface{}{"192.0.2.1", "192.0.2.2"},
+	})
+	d.SetId("example.com/www")
+	if diags := r.ReadContext(context.Background(), d, fake); diags.HasError() {
+		t.Fatalf("Read: %v", diags)
+	}
+	if ttl := d.Get("ttl").(int); ttl != 3600 {
+		t.Errorf("ttl = %d, want the 3600 reported by the API", ttl)
+	}
+}
+
+func TestReadSetsCreatedAt(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		records []base.DNSRecord
+		want    string
+	}{
+		{
+			name: "earliest reported time",
+			records: []base.DNSRecord{
+				{Subname: "www", Rectype: "A", Content: "192.0.2.1", CreatedAt: "2024-05-02T08:00:00Z"},
+				{Subname: "www", Rectype: "A", Content: "192.0.2.2", CreatedAt: "2024-05-01T12:00:00Z"},
+			},
+			want: "2024-05-01T12:00:00Z",
+		},
+		{
+			name:    "not reported",
+			records: []base.DNSRecord{{Subname: "www", Rectype: "A", Content: "192.0.2.1"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := fakeclient.New("example.com")
+			fake.SetRecords("example.com", tc.records)
+			r := resources.ResourceDNSARecord()
+
+			d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+				"zone": "example.com",
+				"name": "www",
+			})
+			d.SetId("example.com/www")
+			if diags := r.ReadContext(context.Background(), d, fake); diags.HasError() {
+				t.Fatalf("Read: %v", diags)
+			}
+			if createdAt := d.Get("created_at").(string); createdAt != tc.want {
+				t.Errorf("created_at = %q, want %q", createdAt, tc.want)
+			}
+		})
+	}
+}
+
+func TestOverlapWarningForRecordBlocks(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	r := resources.ResourceDNSMXRecord()
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"zone": "example.com",
+		"name": "@",
+		"record": []interface{}{
+			map[string]interface{}{"priority": 10, "servers": []interface{}{"mx1.example.com"}},
+		},
+	})
+	if diags := r.CreateContext(context.Background(), d, fake); diags.HasError() {
+		t.Fatalf("Create: %v", diags)
+	}
+	if diags := r.ReadContext(context.Background(), d, fake); len(diags) != 0 {
+		t.Fatalf("Read without extra records: %v", diags)
+	}
+
+	fake.SetRecords("example.com", append(fake.Records("example.com"), base.DNSRecord{Subname: "@", Rectype: "MX", Content: "rogue.example.net.", Prio: 5}))
+	diags := r.ReadContext(context.Background(), d, fake)
+	if len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Fatalf("Read with an extra record: diagnostics = %v, want one warning", diags)
+	}
+	if !strings.Contains(diags[0].Detail, "5 rogue.example.net") {
+		t.Errorf("warning %q does not name the extra record", diags[0].Detail)
+	}
+}
+
+func TestRegistryFollowsReplacedResources(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	a := resources.ResourceDNSARecord()
+	cname := resources.ResourceDNSCNAMERecord()
+
+	existing := schema.TestResourceDataRaw(t, a.Schema, map[string]interface{}{
+		"zone":    "example.com",
+		"name":    "www",
+		"records": []interface{}{"192.0.2.1"},
+	})
+	existing.SetId("example.com/www")
+	state := existing.State()
+
+	planA := func(name string) {
+		t.Helper()
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"zone":    "example.com",
+			"name":    name,
+			"records": []interface{}{"192.0.2.1"},
+		})
+		if _, err := a.Diff(context.Background(), state, config, fake); err != nil {
+			t.Fatalf("plan A record at %s: %v", name, err)
+		}
+	}
+	cnameConfig := map[string]interface{}{"zone": "example.com", "name": "www", "cname": "target.example.net"}
+
+	planA("www")
+	if err := planCreate(t, cname, cnameConfig, fake); err == nil || !strings.Contains(err.Error(), "CNAME conflict") {
+		t.Fatalf("plan CNAME next to the A record: err = %v, want a CNAME conflict", err)
+	}
+
+	// Once the A record is replaced at another name, the CNAME record may take its place
+	planA("web")
+	planA("web")
+	if err := planCreate(t, cname, cnameConfig, fake); err != nil {
+		t.Fatalf("plan CNAME after the A record moved: %v", err)
+	}
+}
+
+// plannedResource is a resource and the configuration it is planned with
+type plannedResource struct {
+	resource *schema.Resource
+	config   map[string]interface{}
+}
+
+func TestPlanRejectsCNAMEConflicts(t *testing.T) {
+	a := plannedResource{resources.ResourceDNSARecord(), map[string]interface{}{"zone": "example.com", "name": "www", "records": []interface{}{"192.0.2.1"}}}
+	cname := plannedResource{resources.ResourceDNSCNAMERecord(), map[string]interface{}{"zone": "example.com", "name": "www", "cname": "target.example.net"}}
+	dname := plannedResource{resources.ResourceDNSDNAMERecord(), map[string]interface{}{"zone": "example.com", "name": "www", "target": "example.net"}}
+	txtElsewhere := plannedResource{resources.ResourceDNSTXTRecord(), map[string]interface{}{"zone": "example.com", "name": "other", "records": []interface{}{"v=spf1 -all"}}}
+
+	for _, tc := range []struct {
+		name     string
+		plans    []plannedResource
+		existing []base.DNSRecord
+		conflict string
+	}{
+		{name: "A then CNAME", plans: []plannedResource{a, cname}, conflict: "CNAME conflict"},
+		{name: "CNAME then A", plans: []plannedResource{cname, a}, conflict: "CNAME conflict"},
+		{name: "CNAME then DNAME", plans: []plannedResource{cname, dname}, conflict: "CNAME conflict"},
+		{name: "different names", plans: []plannedResource{cname, txtElsewhere}},
+		{
+			name:     "DNAME created outside Terraform",
+			plans:    []plannedResource{cname},
+			existing: []base.DNSRecord{{Subname: "www", Rectype: "DNAME", Content: "example.net."}},
+			conflict: "CNAME conflict",
+		},
+		{
+			name:     "CNAME created outside Terraform",
+			plans:    []plannedResource{dname},
+			existing: []base.DNSRecord{{Subname: "www", Rectype: "CNAME", Content: "target.example.net."}},
+			conflict: "DNAME conflict",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := fakeclient.New("example.com")
+			fake.SetRecords("example.com", tc.existing)
+
+			var err error
+			for _, plan := range tc.plans {
+				if err = planCreate(t, plan.resource, plan.config, fake); err != nil {
+					break
+				}
+			}
+			switch {
+			case tc.conflict == "" && err != nil:
+				t.Errorf("plan: %v", err)
+			case tc.conflict != "" && (err == nil || !strings.Contains(err.Error(), tc.conflict)):
+				t.Errorf("plan: err = %v, want a %s", err, tc.conflict)
+			}
+		})
+	}
+}
+
+func TestPlanEmptyRecordsRequiresAllowEmpty(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	r := resources.ResourceDNSARecord()
+
+	err := planCreate(t, r, map[string]interface{}{"zone": "example.com", "name": "www", "records": []interface{}{}}, fake)
+	if err == nil || !strings.Contains(err.Error(), "allow_empty") {
+		t.Errorf("plan of empty records: err = %v, want one pointing to allow_empty", err)
+	}
+	if err := planCreate(t, r, map[string]interface{}{"zone": "example.com", "name": "www", "records": []interface{}{}, "allow_empty": true}, fake); err != nil {
+		t.Errorf("plan of empty records with allow_empty: %v", err)
+	}
+}
+
+func TestServersRejectIPAddresses(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		server  string
+		wantErr bool
+	}{
+		{name: "hostname", server: "mx1.example.com"},
+		{name: "hostname with trailing dot", server: "mx1.example.com."},
+		{name: "IPv4", server: "192.0.2.1", wantErr: true},
+		{name: "IPv4 with trailing dot", server: "192.0.2.1.", wantErr: true},
+		{name: "IPv6", server: "2001:db8::1", wantErr: true},
+	} {
+		for _, r := range []struct {
+			recordType string
+			resource   *schema.Resource
+		}{
+			{"MX", resources.ResourceDNSMXRecord()},
+			{"NS", resources.ResourceDNSNSRecord()},
+		} {
+			t.Run(r.recordType+" "+tc.name, func(t *testing.T) {
+				diags := r.resource.Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+					"zone": "example.com",
+					"name": "@",
+					"record": []interface{}{
+						map[string]interface{}{"priority": 10, "servers": []interface{}{tc.server}},
+					},
+				}))
+				if !tc.wantErr {
+					if diags.HasError() {
+						t.Errorf("server %q rejected: %v", tc.server, diags)
+					}
+					return
+				}
+				if !diags.HasError() {
+					t.Fatalf("server %q accepted, want an error", tc.server)
+				}
+				if detail := diags[0].Summary + diags[0].Detail; !strings.Contains(detail, "must point to a hostname") {
+					t.Errorf("error %q does not explain a hostname is required", detail)
+				}
+			})
+		}
+	}
+}
+
+func TestAddressRecordsRejectInvalidAddresses(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		resource *schema.Resource
+		value    string
+		wantErr  string // empty when the value is valid
+	}{
+		{"A", resources.ResourceDNSARecord(), "192.0.2.1", ""},
+		{"A out of range", resources.ResourceDNSARecord(), "192.168.1.999", "not a valid IPv4 address"},
+		{"A with IPv6", resources.ResourceDNSARecord(), "2001:db8::1", "use regru_dns_aaaa_record"},
+		{"AAAA", resources.ResourceDNSAAAARecord(), "2001:db8::1", ""},
+		{"AAAA malformed", resources.ResourceDNSAAAARecord(), "2001:db8:::1", "not a valid IPv6 address"},
+		{"AAAA with IPv4", resources.ResourceDNSAAAARecord(), "192.0.2.1", "use regru_dns_a_record"},
+		{"AAAA with IPv4-mapped address", resources.ResourceDNSAAAARecord(), "::ffff:192.0.2.1", "use regru_dns_a_record"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			diags := tc.resource.Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+				"zone":    "example.com",
+				"name":    "www",
+				"records": []interface{}{tc.value},
+			}))
+			if tc.wantErr == "" {
+				if diags.HasError() {
+					t.Errorf("value %q rejected: %v", tc.value, diags)
+				}
+				return
+			}
+			if !diags.HasError() {
+				t.Fatalf("value %q accepted, want an error", tc.value)
+			}
+			if detail := diags[0].Summary + diags[0].Detail; !strings.Contains(detail, tc.wantErr) {
+				t.Errorf("error %q does not contain %q", detail, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestSRVRecordValidation(t *testing.T) {
+	for _, tc := range []struct {
+		name                   string
+		priority, weight, port int
+		target                 string
+		wantErr                string // empty when the record set is valid
+	}{
+		{"valid", 10, 5, 5060, "sip.example.com.", ""},
+		{"bounds", 0, 65535, 65535, "sip.example.com", ""},
+		{"service unavailable", 0, 0, 0, ".", ""},
+		{"negative priority", -1, 5, 5060, "sip.example.com.", "priority"},
+		{"weight out of range", 10, 65536, 5060, "sip.example.com.", "weight"},
+		{"port out of range", 10, 5, 70000, "sip.example.com.", "port"},
+		{"target is an IP", 10, 5, 5060, "192.0.2.1", "must point to a hostname"},
+		{"target with invalid character", 10, 5, 5060, "sip example.com", "invalid character"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			diags := resources.ResourceDNSSRVRecord().Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+				"zone": "example.com",
+				"name": "_sip._udp",
+				"record": []interface{}{map[string]interface{}{
+					"priority": tc.priority,
+					"weight":   tc.weight,
+					"port":     tc.port,
+					"targets":  []interface{}{tc.target},
+				}},
+			}))
+			if tc.wantErr == "" {
+				if diags.HasError() {
+					t.Errorf("record set rejected: %v", diags)
+				}
+				return
+			}
+			if !diags.HasError() {
+				t.Fatal("record set accepted, want an error")
+			}
+			if detail := diags[0].Summary + diags[0].Detail; !strings.Contains(detail, tc.wantErr) {
+				t.Errorf("error %q does not contain %q", detail, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestTXTRejectsControlCharacters(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{name: "clean", value: "v=spf1 include:_spf.example.com -all"},
+		{name: "embedded newline", value: "v=spf1 -all\nv=spf1 +all", wantErr: true},
+		{name: "tab", value: "key=\tvalue", wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			diags := resources.ResourceDNSTXTRecord().Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+				"zone":    "example.com",
+				"name":    "@",
+				"records": []interface{}{tc.value},
+			}))
+			if !tc.wantErr {
+				if diags.HasError() {
+					t.Errorf("value %q rejected: %v", tc.value, diags)
+				}
+				return
+			}
+			if !diags.HasError() {
+				t.Fatalf("value %q accepted, want an error", tc.value)
+			}
+			if detail := diags[0].Summary + diags[0].Detail; !strings.Contains(detail, "control characters") {
+				t.Errorf("error %q does not mention control characters", detail)
+			}
+		})
+	}
+}
+
+func TestPlanValidatesSPFWhenEnabled(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	r := resources.ResourceDNSTXTRecord()
+
+	for _, tc := range []struct {
+		name        string
+		records     []interface{}
+		validateSPF bool
+		wantErr     string
+	}{
+		{name: "missing all", records: []interface{}{"v=spf1 mx"}, validateSPF: true, wantErr: "all mechanism"},
+		{name: "missing all unchecked", records: []interface{}{"v=spf1 mx"}},
+		{name: "two policies", records: []interface{}{"v=spf1 mx -all", "v=spf1 a -all"}, validateSPF: true, wantErr: "only one SPF policy"},
+		{name: "other values", records: []interface{}{"v=spf1 mx -all", "verification=abc"}, validateSPF: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := planCreate(t, r, map[string]interface{}{
+				"zone":         "example.com",
+				"name":         "@",
+				"records":      tc.records,
+				"validate_spf": tc.validateSPF,
+			}, fake)
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Errorf("plan: %v", err)
+			case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+				t.Errorf("plan: err = %v, want one containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestTXTDiffIgnoresSPFMechanismCase(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		ignoreSPFCase bool
+		records       []interface{}
+		wantChanges   bool
+	}{
+		{"mixed-case mechanisms", true, []interface{}{"V=SPF1 Include:%{d}._SPF.Example.com  ~ALL", "site-verification=abc"}, false},
+		{"mixed-case mechanisms by default", false, []interface{}{"V=SPF1 Include:%{d}._SPF.Example.com  ~ALL", "site-verification=abc"}, true},
+		{"non-SPF value", true, []interface{}{"v=spf1 include:%{d}._spf.example.com ~all", "site-verification=ABC"}, true},
+		{"macro letter", true, []interface{}{"v=spf1 include:%{D}._spf.example.com ~all", "site-verification=abc"}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := fakeclient.New("example.com")
+			r := resources.ResourceDNSTXTRecord()
+
+			d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+				"zone":            "example.com",
+				"name":            "@",
+				"records":         []interface{}{"v=spf1 include:%{d}._spf.example.com ~all", "site-verification=abc"},
+				"ignore_spf_case": tc.ignoreSPFCase,
+			})
+			if diags := r.CreateContext(context.Background(), d, fake); diags.HasError() {
+				t.Fatalf("Create: %v", diags)
+			}
+
+			config := terraform.NewResourceConfigRaw(map[string]interface{}{
+				"zone":            "example.com",
+				"name":            "@",
+				"records":         tc.records,
+				"ignore_spf_case": tc.ignoreSPFCase,
+			})
+			diff, err := schema.InternalMap(r.Schema).Diff(context.Background(), d.State(), config, nil, nil, true)
+			if err != nil {
+				t.Fatalf("diff: %v", err)
+			}
+			if changes := !diff.Empty(); changes != tc.wantChanges {
+				t.Errorf("plan shows changes = %v, want %v: %v", changes, tc.wantChanges, diff)
+			}
+		})
+	}
+}
+
+func TestCNAMEDiffSuppressFunc(t *testing.T) {
+	for _, tc := range []struct {
+		old, new string
+		suppress bool
+	}{
+		{"target.example.net", "target.example.net.", true},
+		{"target.example.net.", "Target.Example.net", true},
+		{"target.example.net", "other.example.net", false},
+		{"", "target.example.net", false},
+	} {
+		if got := resources.CNAMEDiffSuppressFunc("cname", tc.old, tc.new, nil); got != tc.suppress {
+			t.Errorf("CNAMEDiffSuppressFunc(%q, %q) = %t, want %t", tc.old, tc.new, got, tc.suppress)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package resources
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"terraform-provider-regru/resource/base"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// DataSourceRecord creates the data source reading the records of a type at a name, without managing them
+func DataSourceRecord() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: withContext(readRecord),
+		Schema: map[string]*schema.Schema{
+			"zone": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The DNS zone (domain) to read from",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The record name (use @ for root domain)",
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "The record type",
+				ValidateFunc: validation.StringInSlice([]string{"A", "AAAA", "CNAME", "PTR", "DNAME", "MX", "NS", "TXT", "SRV", "CAA", "SSHFP", "TLSA"}, false),
+			},
+			"allow_missing": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Return an empty list instead of failing when no record of the type exists at the name",
+			},
+			"records": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The matching records in zone-file notation, sorted",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// readRecord reads the records from the cached zone. Unless allow_missing is set,
+// a missing record or zone is an error.
+func readRecord(d *schema.ResourceData, meta interface{}) error {
+	c, ok := meta.(base.CachedClientInterface)
+	if !ok {
+		return fmt.Errorf("invalid client type for record lookup")
+	}
+
+	zone := d.Get("zone").(string)
+	name := d.Get("name").(string)
+	recordType := d.Get("type").(string)
+	allowMissing := d.Get("allow_missing").(bool)
+
+	records, err := c.GetRecordsByType(zone, name, recordType)
+	if err != nil && !(allowMissing && errors.Is(err, base.ErrZoneNotFound)) {
+		return fmt.Errorf("failed to read %s records for %s.%s: %w", recordType, name, zone, err)
+	}
+	if len(records) == 0 && !allowMissing {
+		return fmt.Errorf("no %s record found for %s.%s; set allow_missing = true to accept an empty result", recordType, name, zone)
+	}
+
+	// Keep the list stable across reads regardless of the order the API returns records in
+	sortRecordValues(records)
+	values := formatRecordValues(recordType, records)
+
+	d.SetId(strings.Join([]string{zone, name, recordType}, "/"))
+	if err := d.Set("records", values); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// sortRecordValues orders records of one type by their numeric fields, then by content
+func sortRecordValues(records []base.DNSRecord) {
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].Prio != records[j].Prio {
+			return records[i].Prio < records[j].Prio
+		}
+		if records[i].Weight != records[j].Weight {
+			return records[i].Weight < records[j].Weight
+		}
+		if records[i].Port != records[j].Port {
+			return records[i].Port < records[j].Port
+		}
+		return records[i].Content < records[j].Content
+	})
+}
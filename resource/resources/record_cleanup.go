@@ -0,0 +1,198 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"terraform-provider-regru/resource/base"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// ResourceDNSRecordCleanup creates the resource that removes all records of a type whose name matches a pattern.
+// The matching records are resolved at plan time, so the plan lists exactly what will be removed.
+func ResourceDNSRecordCleanup() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: withContext(withZoneWriteGuard(createRecordCleanup)),
+		ReadContext:   withContext(readRecordCleanup),
+		DeleteContext: withContext(deleteRecordCleanup),
+		Schema: map[string]*schema.Schema{
+			"zone": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The DNS zone (domain) to clean up",
+			},
+			"record_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The type of records to remove",
+				ValidateFunc: validation.StringInSlice([]string{"A", "AAAA", "CNAME", "PTR", "DNAME", "MX", "NS", "TXT", "SRV", "CAA", "SSHFP", "TLSA"}, false),
+			},
+			"name_pattern": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Regular expression matched against the whole record name (e.g. _acme-challenge.*)",
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"matched_records": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The records removed by this resource",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name":     {Type: schema.TypeString, Computed: true},
+						"content":  {Type: schema.TypeString, Computed: true},
+						"priority": {Type: schema.TypeInt, Computed: true},
+						"weight":   {Type: schema.TypeInt, Computed: true},
+						"port":     {Type: schema.TypeInt, Computed: true},
+						"flag":     {Type: schema.TypeInt, Computed: true},
+						"tag":      {Type: schema.TypeString, Computed: true},
+					},
+				},
+			},
+		},
+		CustomizeDiff: planRecordCleanup,
+	}
+}
+
+// planRecordCleanup resolves the records matching the pattern so the plan shows what will be removed
+func planRecordCleanup(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	// The matches are fixed once the cleanup has run
+	if d.Id() != "" {
+		return nil
+	}
+	if !d.NewValueKnown("zone") || !d.NewValueKnown("record_type") || !d.NewValueKnown("name_pattern") {
+		return nil
+	}
+
+	c, ok := meta.(base.CachedClientInterface)
+	if !ok {
+		return nil
+	}
+
+	matches, err := findCleanupRecords(c, d.Get("zone").(string), d.Get("record_type").(string), d.Get("name_pattern").(string))
+	if err != nil {
+		return err
+	}
+
+	return d.SetNew("matched_records", matches)
+}
+
+// findCleanupRecords lists the records of the type in the zone whose name fully matches the pattern
+func findCleanupRecords(c base.CachedClientInterface, zone, recordType, pattern string) ([]interface{}, error) {
+	matcher, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid name pattern %q: %w", pattern, err)
+	}
+
+	response, err := c.GetRecordsWithCache(zone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get zone records: %w", err)
+	}
+
+	var zoneResponse base.DNSZoneResponse
+	if err := json.Unmarshal(response, &zoneResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse DNS records response: %w", err)
+	}
+	if err := zoneResponse.CheckZone(zone); err != nil {
+		return nil, err
+	}
+
+	matches := []interface{}{}
+	for _, domain := range zoneResponse.Answer.Domains {
+		if !base.MatchesZone(domain.Dname, zone) {
+			continue
+		}
+		for _, rr := range domain.Rrs {
+			name := rr.Subname
+			if name == "" {
+				name = "@"
+			}
+			if rr.Rectype != recordType || !matcher.MatchString(name) {
+				continue
+			}
+			matches = append(matches, map[string]interface{}{
+				"name":     name,
+				"content":  rr.Content,
+				"priority": rr.Prio,
+				"weight":   rr.Weight,
+				"port":     rr.Port,
+				"flag":     rr.Flag,
+				"tag":      rr.Tag,
+			})
+		}
+	}
+
+	return matches, nil
+}
+
+// createRecordCleanup removes the records resolved during planning. Like the record resources'
+// writes, it runs under the check for concurrent zone modifications.
+func createRecordCleanup(d *schema.ResourceData, meta interface{}) error {
+	c, ok := meta.(base.CachedClientInterface)
+	if !ok {
+		return fmt.Errorf("invalid client type for record cleanup")
+	}
+
+	zone := d.Get("zone").(string)
+	recordType := d.Get("record_type").(string)
+	pattern := d.Get("name_pattern").(string)
+
+	for _, item := range d.Get("matched_records").([]interface{}) {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		name := record["name"].(string)
+		content := record["content"].(string)
+		priority := record["priority"].(int)
+		log.Printf("[DEBUG] Cleanup removing %s record: %s.%s -> %s", recordType, name, zone, content)
+
+		var response []byte
+		var err error
+		switch recordType {
+		case "SRV":
+			weight := record["weight"].(int)
+			port := record["port"].(int)
+			response, err = c.RemoveSRVRecord(zone, name, content, &priority, &weight, &port)
+		case "CAA":
+			flag := record["flag"].(int)
+			tag := record["tag"].(string)
+			response, err = c.RemoveCAARecord(zone, name, content, &flag, &tag)
+		case "SSHFP":
+			sshfp := base.ParseSSHFPContent(base.DNSRecord{Content: content})
+			response, err = c.RemoveSSHFPRecord(zone, name, sshfp.Value, &sshfp.Algorithm, &sshfp.FpType)
+		case "TLSA":
+			tlsa := base.ParseTLSAContent(base.DNSRecord{Content: content})
+			response, err = c.RemoveTLSARecord(zone, name, tlsa.Value, &tlsa.Usage, &tlsa.Selector, &tlsa.MatchingType)
+		default:
+			response, err = c.RemoveRecord(zone, name, recordType, content, &priority)
+		}
+		// A record removed since the plan is already gone
+		if err := base.CheckRemoveResponse(response, err); err != nil {
+			return fmt.Errorf("failed to remove %s record %s -> %s: %w", recordType, name, content, err)
+		}
+	}
+
+	c.InvalidateZoneCache(zone)
+	d.SetId(fmt.Sprintf("%s/%s/%s", zone, recordType, pattern))
+	return nil
+}
+
+// readRecordCleanup keeps the recorded result; the cleanup is a one-off action
+func readRecordCleanup(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
+
+// deleteRecordCleanup only forgets the cleanup; removed records are not restored
+func deleteRecordCleanup(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,77 @@
+package resources_test
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"terraform-provider-regru/internal/fakeclient"
+	"terraform-provider-regru/resource/base"
+	"terraform-provider-regru/resource/resources"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestRecordCleanupRemovesOnlyMatches(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	fake.SetRecords("example.com", []base.DNSRecord{
+		{Subname: "_acme-challenge", Rectype: "TXT", Content: "token-apex"},
+		{Subname: "_acme-challenge.www", Rectype: "TXT", Content: "token-www"},
+		{Subname: "_acme-challenge", Rectype: "CNAME", Content: "acme.example.net."},
+		{Subname: "www", Rectype: "TXT", Content: "v=spf1 -all"},
+		{Subname: "www._acme-challenge", Rectype: "TXT", Content: "unrelated"},
+	})
+	r := resources.ResourceDNSRecordCleanup()
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"zone":         "example.com",
+		"record_type":  "TXT",
+		"name_pattern": "_acme-challenge.*",
+	})
+
+	diff, err := r.Diff(context.Background(), nil, config, fake)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	if _, diags := r.Apply(context.Background(), nil, diff, fake); diags.HasError() {
+		t.Fatalf("apply: %v", diags)
+	}
+
+	var remaining []string
+	for _, rr := range fake.Records("example.com") {
+		remaining = append(remaining, rr.Rectype+" "+rr.Subname)
+	}
+	sort.Strings(remaining)
+	want := []string{"CNAME _acme-challenge", "TXT www", "TXT www._acme-challenge"}
+	if !reflect.DeepEqual(remaining, want) {
+		t.Errorf("remaining records = %q, want %q", remaining, want)
+	}
+}
+
+// goneClient is a fake client whose records were all removed by someone else after the plan
+type goneClient struct {
+	*fakeclient.Client
+}
+
+func (goneClient) RemoveRecord(domainName, subdomain, recordType, content string, priority *int) ([]byte, error) {
+	return []byte(`{"result":"success","answer":{"domains":[{"dname":"example.com","result":"error","error_code":"RR_NOT_FOUND","error_text":"Record not found"}]}}`), nil
+}
+
+func TestRecordCleanupToleratesRecordsAlreadyRemoved(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	fake.SetRecords("example.com", []base.DNSRecord{{Subname: "_acme-challenge", Rectype: "TXT", Content: "token"}})
+	r := resources.ResourceDNSRecordCleanup()
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"zone":         "example.com",
+		"record_type":  "TXT",
+		"name_pattern": "_acme-challenge",
+	})
+
+	diff, err := r.Diff(context.Background(), nil, config, fake)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	if _, diags := r.Apply(context.Background(), nil, diff, goneClient{fake}); diags.HasError() {
+		t.Fatalf("apply: %v", diags)
+	}
+}
@@ -0,0 +1,97 @@
+package resources
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"terraform-provider-regru/resource/base"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// DataSourceRecordExists creates the data source reporting whether a record exists, without managing it
+func DataSourceRecordExists() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: withContext(readRecordExists),
+		Schema: map[string]*schema.Schema{
+			"zone": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The DNS zone (domain) to look in",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The record name (use @ for root domain)",
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "The record type",
+				ValidateFunc: validation.StringInSlice([]string{"A", "AAAA", "CNAME", "PTR", "DNAME", "MX", "NS", "TXT", "SRV", "CAA", "SSHFP", "TLSA"}, false),
+			},
+			"exists": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether at least one record of the type exists at the name",
+			},
+			"values": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The matching records in zone-file notation",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// readRecordExists looks the records up in the cached zone. A missing record or zone is not an error.
+func readRecordExists(d *schema.ResourceData, meta interface{}) error {
+	c, ok := meta.(base.CachedClientInterface)
+	if !ok {
+		return fmt.Errorf("invalid client type for record lookup")
+	}
+
+	zone := d.Get("zone").(string)
+	name := d.Get("name").(string)
+	recordType := d.Get("type").(string)
+
+	records, err := c.GetRecordsByType(zone, name, recordType)
+	if err != nil && !errors.Is(err, base.ErrZoneNotFound) {
+		return fmt.Errorf("failed to look up %s records for %s.%s: %w", recordType, name, zone, err)
+	}
+
+	values := formatRecordValues(recordType, records)
+
+	d.SetId(strings.Join([]string{zone, name, recordType}, "/"))
+	d.Set("exists", len(values) > 0)
+	d.Set("values", values)
+
+	return nil
+}
+
+// formatRecordValues renders records read from the API in zone-file notation
+func formatRecordValues(recordType string, records []base.DNSRecord) []string {
+	values := make([]string, 0, len(records))
+	for _, record := range records {
+		// SSHFP and TLSA content already holds all fields in zone-file notation
+		switch recordType {
+		case "SSHFP":
+			values = append(values, base.ParseSSHFPContent(record).Format(recordType))
+			continue
+		case "TLSA":
+			values = append(values, base.ParseTLSAContent(record).Format(recordType))
+			continue
+		}
+		values = append(values, base.Record{
+			Priority: record.Prio,
+			Weight:   record.Weight,
+			Port:     record.Port,
+			Flag:     record.Flag,
+			Tag:      record.Tag,
+			Value:    record.Content,
+		}.Format(recordType))
+	}
+	return values
+}
@@ -0,0 +1,57 @@
+package resources_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"terraform-provider-regru/internal/fakeclient"
+	"terraform-provider-regru/resource/base"
+	"terraform-provider-regru/resource/resources"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestRecordExists(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	fake.SetRecords("example.com", []base.DNSRecord{
+		{Subname: "@", Rectype: "MX", Content: "mx1.example.com.", Prio: 10},
+		{Subname: "@", Rectype: "MX", Content: "mx2.example.com.", Prio: 20},
+		{Subname: "www", Rectype: "A", Content: "192.0.2.1"},
+	})
+	r := resources.DataSourceRecordExists()
+
+	for _, tc := range []struct {
+		name             string
+		zone, recordName string
+		recordType       string
+		exists           bool
+		values           []string
+	}{
+		{"present", "example.com", "@", "MX", true, []string{"10 mx1.example.com.", "20 mx2.example.com."}},
+		{"other type at the name", "example.com", "www", "AAAA", false, []string{}},
+		{"absent name", "example.com", "ftp", "A", false, []string{}},
+		{"absent zone", "missing.example", "www", "A", false, []string{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+				"zone": tc.zone,
+				"name": tc.recordName,
+				"type": tc.recordType,
+			})
+			if diags := r.ReadContext(context.Background(), d, fake); diags.HasError() {
+				t.Fatalf("Read: %v", diags)
+			}
+			if exists := d.Get("exists").(bool); exists != tc.exists {
+				t.Errorf("exists = %t, want %t", exists, tc.exists)
+			}
+			values := []string{}
+			for _, value := range d.Get("values").([]interface{}) {
+				values = append(values, value.(string))
+			}
+			if !reflect.DeepEqual(values, tc.values) {
+				t.Errorf("values = %q, want %q", values, tc.values)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package resources_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"terraform-provider-regru/internal/fakeclient"
+	"terraform-provider-regru/resource/base"
+	"terraform-provider-regru/resource/resources"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestRecordDataSource(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	fake.SetRecords("example.com", []base.DNSRecord{
+		{Subname: "@", Rectype: "MX", Content: "mx2.example.com.", Prio: 20},
+		{Subname: "@", Rectype: "MX", Content: "mx1.example.com.", Prio: 10},
+		{Subname: "www", Rectype: "A", Content: "192.0.2.2"},
+		{Subname: "www", Rectype: "A", Content: "192.0.2.1"},
+	})
+	r := resources.DataSourceRecord()
+
+	for _, tc := range []struct {
+		name                   string
+		zone, recordName, kind string
+		allowMissing           bool
+		values                 []string
+		wantErr                string
+	}{
+		{name: "A", zone: "example.com", recordName: "www", kind: "A", values: []string{"192.0.2.1", "192.0.2.2"}},
+		{name: "MX", zone: "example.com", recordName: "@", kind: "MX", values: []string{"10 mx1.example.com.", "20 mx2.example.com."}},
+		{name: "missing record", zone: "example.com", recordName: "www", kind: "AAAA", wantErr: "allow_missing"},
+		{name: "missing record allowed", zone: "example.com", recordName: "www", kind: "AAAA", allowMissing: true, values: []string{}},
+		{name: "missing zone", zone: "missing.example", recordName: "www", kind: "A", wantErr: "zone not found"},
+		{name: "missing zone allowed", zone: "missing.example", recordName: "www", kind: "A", allowMissing: true, values: []string{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+				"zone":          tc.zone,
+				"name":          tc.recordName,
+				"type":          tc.kind,
+				"allow_missing": tc.allowMissing,
+			})
+			diags := r.ReadContext(context.Background(), d, fake)
+			if tc.wantErr != "" {
+				if !diags.HasError() || !strings.Contains(diags[0].Summary, tc.wantErr) {
+					t.Errorf("Read = %v, want an error mentioning %s", diags, tc.wantErr)
+				}
+				return
+			}
+			if diags.HasError() {
+				t.Fatalf("Read: %v", diags)
+			}
+			values := []string{}
+			for _, value := range d.Get("records").([]interface{}) {
+				values = append(values, value.(string))
+			}
+			if !reflect.DeepEqual(values, tc.values) {
+				t.Errorf("records = %q, want %q", values, tc.values)
+			}
+		})
+	}
+}
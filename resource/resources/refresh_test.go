@@ -0,0 +1,190 @@
+package resources_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"terraform-provider-regru/internal/fakeclient"
+	"terraform-provider-regru/resource/resources"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// TestReadAfterCreateHasNoDrift refreshes every record type right after creating it and checks
+// neither the state nor the plan changes, with values spelled the way the API doesn't store them
+func TestReadAfterCreateHasNoDrift(t *testing.T) {
+	cases := []struct {
+		name     string
+		resource *schema.Resource
+		config   map[string]interface{}
+		kept     map[string]string
+	}{
+		{
+			name:     "A",
+			resource: resources.ResourceDNSARecord(),
+			config:   map[string]interface{}{"name": "host", "records": []interface{}{"192.0.2.2", "192.0.2.1"}},
+		},
+		{
+			name:     "AAAA",
+			resource: resources.ResourceDNSAAAARecord(),
+			config:   map[string]interface{}{"name": "host", "records": []interface{}{"2001:db8::2", "2001:db8::1"}},
+		},
+		{
+			name:     "TXT",
+			resource: resources.ResourceDNSTXTRecord(),
+			config:   map[string]interface{}{"name": "host", "records": []interface{}{"v=spf1 -all", strings.Repeat("k", 300)}},
+		},
+		{
+			name:     "CNAME",
+			resource: resources.ResourceDNSCNAMERecord(),
+			config:   map[string]interface{}{"name": "host", "cname": "Target.Example.net."},
+			kept:     map[string]string{"cname": "Target.Example.net."},
+		},
+		{
+			name:     "DNAME",
+			resource: resources.ResourceDNSDNAMERecord(),
+			config:   map[string]interface{}{"name": "host", "target": "Example.net"},
+			kept:     map[string]string{"target": "Example.net"},
+		},
+		{
+			name:     "PTR",
+			resource: resources.ResourceDNSPTRRecord(),
+			config:   map[string]interface{}{"name": "host", "ptrdname": "host.example.com."},
+		},
+		{
+			name:     "NS",
+			resource: resources.ResourceDNSNSRecord(),
+			config: map[string]interface{}{"name": "host", "record": []interface{}{
+				map[string]interface{}{"priority": 0, "servers": []interface{}{"NS2.example.net.", "ns1.example.net"}},
+			}},
+			kept: map[string]string{"record.0.servers.0": "NS2.example.net.", "record.0.servers.1": "ns1.example.net"},
+		},
+		{
+			name:     "MX",
+			resource: resources.ResourceDNSMXRecord(),
+			config: map[string]interface{}{"name": "host", "record": []interface{}{
+				map[string]interface{}{"priority": 20, "servers": []interface{}{"Backup.example.net."}},
+				map[string]interface{}{"priority": 10, "servers": []interface{}{"mx2.example.com", "mx1.example.com"}},
+			}},
+			kept: map[string]string{"record.0.servers.0": "Backup.example.net."},
+		},
+		{
+			name:     "SRV",
+			resource: resources.ResourceDNSSRVRecord(),
+			config: map[string]interface{}{"name": "_sip._tcp", "record": []interface{}{
+				map[string]interface{}{"priority": 10, "weight": 5, "port": 5060, "targets": []interface{}{"SIP2.example.com.", "sip1.example.com"}},
+			}},
+			kept: map[string]string{"record.0.targets.0": "SIP2.example.com."},
+		},
+		{
+			name:     "SSHFP",
+			resource: resources.ResourceDNSSSHFPRecord(),
+			config: map[string]interface{}{"name": "host", "record": []interface{}{
+				map[string]interface{}{"algorithm": 4, "fp_type": 2, "fingerprint": strings.Repeat("AB", 32)},
+			}},
+		},
+		{
+			name:     "CAA",
+			resource: resources.ResourceDNSCAARecord(),
+			config: map[string]interface{}{"name": "@", "record": []interface{}{
+				map[string]interface{}{"flag": 0, "tag": "issue", "value": "LetsEncrypt.org"},
+				map[string]interface{}{"flag": 0, "tag": "iodef", "value": "mailto:security@example.com"},
+			}},
+			kept: map[string]string{"record.1.value": "LetsEncrypt.org"},
+		},
+		{
+			name:     "TLSA",
+			resource: resources.ResourceDNSTLSARecord(),
+			config: map[string]interface{}{"name": "_443._tcp", "record": []interface{}{
+				map[string]interface{}{"usage": 3, "selector": 1, "matching_type": 1, "certificate": strings.Repeat("CD", 32)},
+			}},
+		},
+		{
+			name:     "DKIM",
+			resource: resources.ResourceDNSDKIMRecord(),
+			config:   map[string]interface{}{"selector": "mail", "value": "v=DKIM1; k=rsa; p=" + strings.Repeat("A", 400)},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := fakeclient.New("example.com")
+			r := tc.resource
+			raw := map[string]interface{}{"zone": "example.com"}
+			for key, value := range tc.config {
+				raw[key] = value
+			}
+
+			d := schema.TestResourceDataRaw(t, r.Schema, raw)
+			if diags := r.CreateContext(context.Background(), d, fake); diags.HasError() {
+				t.Fatalf("Create: %v", diags)
+			}
+			created := d.State().Attributes
+			for key, want := range tc.kept {
+				if created[key] != want {
+					t.Errorf("%s = %q, want the configured spelling %q", key, created[key], want)
+				}
+			}
+
+			if diags := r.ReadContext(context.Background(), d, fake); diags.HasError() {
+				t.Fatalf("Read: %v", diags)
+			}
+			if d.Id() == "" {
+				t.Fatal("Read removed the resource from state")
+			}
+			if refreshed := d.State().Attributes; !reflect.DeepEqual(refreshed, created) {
+				t.Errorf("state changed on refresh:\n got  %q\n want %q", refreshed, created)
+			}
+
+			diff, err := schema.InternalMap(r.Schema).Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(raw), nil, nil, true)
+			if err != nil {
+				t.Fatalf("diff: %v", err)
+			}
+			if !diff.Empty() {
+				t.Errorf("plan after refresh shows changes: %v", diff)
+			}
+		})
+	}
+}
+
+// TestMXPrioritySplitAcrossBlocksHasNoDiff applies a config listing priority 10 in two record
+// blocks and checks the plan stays clean against the one block per priority Read reports
+// when it has no configured grouping to follow, as after an import
+func TestMXPrioritySplitAcrossBlocksHasNoDiff(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	r := resources.ResourceDNSMXRecord()
+	raw := map[string]interface{}{
+		"zone": "example.com",
+		"name": "@",
+		"record": []interface{}{
+			map[string]interface{}{"priority": 10, "servers": []interface{}{"mx1.example.com"}},
+			map[string]interface{}{"priority": 20, "servers": []interface{}{"backup.example.net"}},
+			map[string]interface{}{"priority": 10, "servers": []interface{}{"mx2.example.com"}},
+		},
+	}
+
+	d := schema.TestResourceDataRaw(t, r.Schema, raw)
+	if diags := r.CreateContext(context.Background(), d, fake); diags.HasError() {
+		t.Fatalf("Create: %v", diags)
+	}
+
+	read := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"zone": "example.com", "name": "@"})
+	read.SetId(d.Id())
+	if diags := r.ReadContext(context.Background(), read, fake); diags.HasError() {
+		t.Fatalf("Read: %v", diags)
+	}
+	if blocks := read.Get("record").([]interface{}); len(blocks) != 2 {
+		t.Fatalf("record blocks after read = %v, want one per priority", blocks)
+	}
+
+	diff, err := schema.InternalMap(r.Schema).Diff(context.Background(), read.State(), terraform.NewResourceConfigRaw(raw), nil, nil, true)
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	if !diff.Empty() {
+		t.Errorf("plan against split priority blocks shows changes: %v", diff)
+	}
+}
@@ -0,0 +1,175 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"terraform-provider-regru/resource/base"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// zoneRecordTypes lists the record types exposed by the zone resource, each in a block named
+// after the record resource managing it
+var zoneRecordTypes = []string{"A", "AAAA", "CNAME", "PTR", "DNAME", "MX", "NS", "TXT", "SRV", "CAA", "SSHFP", "TLSA"}
+
+// zoneRecordBlock returns the name of the block holding the records of a type
+func zoneRecordBlock(recordType string) string {
+	return strings.ToLower(recordType) + "_record"
+}
+
+// ResourceDNSZone creates the resource taking a snapshot of all records in a zone, grouped by
+// type and name, together with the import IDs of the record resources that would manage them.
+// It doesn't manage any records: removing it leaves the zone untouched.
+func ResourceDNSZone() *schema.Resource {
+	resourceSchema := map[string]*schema.Schema{
+		"zone": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The DNS zone (domain) to read",
+		},
+	}
+	for _, recordType := range zoneRecordTypes {
+		resourceSchema[zoneRecordBlock(recordType)] = &schema.Schema{
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: fmt.Sprintf("The %s records in the zone, one block per name", recordType),
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "The record name relative to the zone (@ for the root domain)",
+					},
+					"records": {
+						Type:        schema.TypeList,
+						Computed:    true,
+						Description: "The records at the name in zone-file notation, sorted",
+						Elem:        &schema.Schema{Type: schema.TypeString},
+					},
+					"import_id": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: fmt.Sprintf("The ID to import the records into regru_dns_%s with", zoneRecordBlock(recordType)),
+					},
+				},
+			},
+		}
+	}
+
+	return &schema.Resource{
+		CreateContext: withContext(createZone),
+		ReadContext:   withContext(readZone),
+		DeleteContext: withContext(deleteZone),
+		Importer: &schema.ResourceImporter{
+			StateContext: importZone,
+		},
+		Schema: resourceSchema,
+	}
+}
+
+// createZone takes the first snapshot of the zone
+func createZone(d *schema.ResourceData, meta interface{}) error {
+	d.SetId(d.Get("zone").(string))
+	return readZone(d, meta)
+}
+
+// readZone groups the records of the cached zone by type and name. A zone that is no longer
+// in the account is removed from state.
+func readZone(d *schema.ResourceData, meta interface{}) error {
+	c, ok := meta.(base.CachedClientInterface)
+	if !ok {
+		return fmt.Errorf("invalid client type for zone")
+	}
+
+	zone := d.Get("zone").(string)
+
+	response, err := c.GetRecordsWithCache(zone)
+	if err != nil {
+		return fmt.Errorf("failed to get records of zone %s: %w", zone, err)
+	}
+
+	var zoneResponse base.DNSZoneResponse
+	if err := json.Unmarshal(response, &zoneResponse); err != nil {
+		return fmt.Errorf("failed to parse DNS records response: %w", err)
+	}
+	if err := zoneResponse.CheckZone(zone); err != nil {
+		if errors.Is(err, base.ErrZoneNotFound) {
+			log.Printf("[WARN] Zone %s is no longer in the account, removing it from state", zone)
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	// Group the records by type, then by name
+	grouped := make(map[string]map[string][]base.DNSRecord)
+	for _, domain := range zoneResponse.Answer.Domains {
+		if !base.MatchesZone(domain.Dname, zone) {
+			continue
+		}
+		for _, rr := range domain.Rrs {
+			if grouped[rr.Rectype] == nil {
+				grouped[rr.Rectype] = make(map[string][]base.DNSRecord)
+			}
+			grouped[rr.Rectype][rr.Subname] = append(grouped[rr.Rectype][rr.Subname], rr)
+		}
+	}
+
+	for _, recordType := range zoneRecordTypes {
+		byName := grouped[recordType]
+		delete(grouped, recordType)
+
+		names := make([]string, 0, len(byName))
+		for name := range byName {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		blocks := make([]interface{}, 0, len(names))
+		for _, name := range names {
+			records := byName[name]
+			sortRecordValues(records)
+			blocks = append(blocks, map[string]interface{}{
+				"name":      name,
+				"records":   formatRecordValues(recordType, records),
+				"import_id": zone + "/" + name,
+			})
+		}
+		if err := d.Set(zoneRecordBlock(recordType), blocks); err != nil {
+			return err
+		}
+	}
+
+	for recordType := range grouped {
+		log.Printf("[DEBUG] Zone %s has %s records, which no record resource manages; skipping them", zone, recordType)
+	}
+
+	return nil
+}
+
+// deleteZone removes the snapshot from state. The records in the zone are left in place.
+func deleteZone(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Removing zone %s from state; its records are not changed", d.Id())
+	d.SetId("")
+	return nil
+}
+
+// importZone imports a zone by its name, e.g. example.com
+func importZone(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	zone := strings.TrimSuffix(strings.TrimSpace(d.Id()), ".")
+	if zone == "" || strings.Contains(zone, "/") {
+		return nil, fmt.Errorf("invalid import ID %q, expected the zone name, e.g. example.com", d.Id())
+	}
+
+	d.SetId(zone)
+	if err := d.Set("zone", zone); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,53 @@
+package resources
+
+import (
+	"fmt"
+	"terraform-provider-regru/resource/base"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// DataSourceZoneForFQDN creates the data source resolving an FQDN to a managed zone and relative name
+func DataSourceZoneForFQDN() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: withContext(readZoneForFQDN),
+		Schema: map[string]*schema.Schema{
+			"fqdn": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The fully qualified domain name to resolve",
+			},
+			"zone": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The longest managed zone in the account containing the FQDN",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The record name relative to the zone (@ for the zone apex)",
+			},
+		},
+	}
+}
+
+// readZoneForFQDN splits the FQDN against the domains in the account
+func readZoneForFQDN(d *schema.ResourceData, meta interface{}) error {
+	fqdn := d.Get("fqdn").(string)
+
+	zones, err := base.AccountZones(meta)
+	if err != nil {
+		return fmt.Errorf("failed to resolve zone for %s: %w", fqdn, err)
+	}
+
+	zone, name, err := base.SplitFQDN(fqdn, zones)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", zone, name))
+	d.Set("zone", zone)
+	d.Set("name", name)
+
+	return nil
+}
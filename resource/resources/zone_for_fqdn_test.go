@@ -0,0 +1,53 @@
+package resources_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"terraform-provider-regru/internal/fakeclient"
+	"terraform-provider-regru/resource/resources"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestZoneForFQDN(t *testing.T) {
+	fake := fakeclient.New("example.com", "sub.example.com", "example.co.uk")
+	r := resources.DataSourceZoneForFQDN()
+
+	for _, tc := range []struct {
+		fqdn string
+		zone string
+		name string
+	}{
+		{fqdn: "example.com", zone: "example.com", name: "@"},
+		{fqdn: "example.com.", zone: "example.com", name: "@"},
+		{fqdn: "sub.example.com", zone: "sub.example.com", name: "@"},
+		{fqdn: "www.example.com", zone: "example.com", name: "www"},
+		{fqdn: "a.b.c.example.com", zone: "example.com", name: "a.b.c"},
+		{fqdn: "host.sub.example.com", zone: "sub.example.com", name: "host"},
+		{fqdn: "_sip._tcp.deep.sub.example.com", zone: "sub.example.com", name: "_sip._tcp.deep"},
+		{fqdn: "www.example.co.uk", zone: "example.co.uk", name: "www"},
+	} {
+		t.Run(tc.fqdn, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"fqdn": tc.fqdn})
+			if diags := r.ReadContext(context.Background(), d, fake); diags.HasError() {
+				t.Fatalf("Read: %v", diags)
+			}
+			if zone, name := d.Get("zone"), d.Get("name"); zone != tc.zone || name != tc.name {
+				t.Errorf("zone, name = %v, %v, want %s, %s", zone, name, tc.zone, tc.name)
+			}
+		})
+	}
+
+	t.Run("unmanaged zone", func(t *testing.T) {
+		d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"fqdn": "www.example.org"})
+		diags := r.ReadContext(context.Background(), d, fake)
+		if !diags.HasError() {
+			t.Fatalf("Read resolved www.example.org to %v/%v, want an error", d.Get("zone"), d.Get("name"))
+		}
+		if !strings.Contains(diags[0].Summary, "www.example.org") {
+			t.Errorf("error %q does not name the FQDN", diags[0].Summary)
+		}
+	})
+}
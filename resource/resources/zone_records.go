@@ -0,0 +1,136 @@
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"terraform-provider-regru/resource/base"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// DataSourceZoneRecords creates the data source listing all records in a zone
+func DataSourceZoneRecords() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: withContext(readZoneRecords),
+		Schema: map[string]*schema.Schema{
+			"zone": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The DNS zone (domain) to list the records of",
+			},
+			"records": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The records in the zone, sorted by name, type and content",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"subname": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The record name relative to the zone (@ for the root domain)",
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The record type",
+						},
+						"content": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The record content as returned by the API",
+						},
+						"prio": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The priority of MX, NS and SRV records",
+						},
+						"weight": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The weight of SRV records",
+						},
+						"port": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The port of SRV records",
+						},
+						"flag": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The flag of CAA records",
+						},
+						"tag": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The tag of CAA records",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// readZoneRecords lists the records of the cached zone. Unlike the record lookup, a zone that
+// isn't in the account is an error, since an empty list would be indistinguishable from an empty zone.
+func readZoneRecords(d *schema.ResourceData, meta interface{}) error {
+	c, ok := meta.(base.CachedClientInterface)
+	if !ok {
+		return fmt.Errorf("invalid client type for zone records")
+	}
+
+	zone := d.Get("zone").(string)
+
+	response, err := c.GetRecordsWithCache(zone)
+	if err != nil {
+		return fmt.Errorf("failed to get records of zone %s: %w", zone, err)
+	}
+
+	var zoneResponse base.DNSZoneResponse
+	if err := json.Unmarshal(response, &zoneResponse); err != nil {
+		return fmt.Errorf("failed to parse DNS records response: %w", err)
+	}
+	if err := zoneResponse.CheckZone(zone); err != nil {
+		return err
+	}
+
+	var found []base.DNSRecord
+	for _, domain := range zoneResponse.Answer.Domains {
+		if base.MatchesZone(domain.Dname, zone) {
+			found = append(found, domain.Rrs...)
+		}
+	}
+
+	// Keep the list stable across reads regardless of the order the API returns records in
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].Subname != found[j].Subname {
+			return found[i].Subname < found[j].Subname
+		}
+		if found[i].Rectype != found[j].Rectype {
+			return found[i].Rectype < found[j].Rectype
+		}
+		return found[i].Content < found[j].Content
+	})
+
+	records := make([]interface{}, 0, len(found))
+	for _, rr := range found {
+		records = append(records, map[string]interface{}{
+			"subname": rr.Subname,
+			"type":    rr.Rectype,
+			"content": rr.Content,
+			"prio":    rr.Prio,
+			"weight":  rr.Weight,
+			"port":    rr.Port,
+			"flag":    rr.Flag,
+			"tag":     rr.Tag,
+		})
+	}
+
+	d.SetId(zone)
+	if err := d.Set("records", records); err != nil {
+		return err
+	}
+
+	return nil
+}
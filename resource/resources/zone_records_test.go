@@ -0,0 +1,60 @@
+package resources_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"terraform-provider-regru/internal/fakeclient"
+	"terraform-provider-regru/resource/base"
+	"terraform-provider-regru/resource/resources"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestZoneRecords(t *testing.T) {
+	fake := fakeclient.New("example.com", "example.net")
+	fake.SetRecords("example.com", []base.DNSRecord{
+		{Subname: "www", Rectype: "A", Content: "192.0.2.2"},
+		{Subname: "_sip._tcp", Rectype: "SRV", Content: "sip.example.com.", Prio: 10, Weight: 5, Port: 5060},
+		{Subname: "@", Rectype: "MX", Content: "mx.example.com.", Prio: 10},
+		{Subname: "@", Rectype: "CAA", Content: "letsencrypt.org", Flag: 128, Tag: "issue"},
+		{Subname: "www", Rectype: "A", Content: "192.0.2.1"},
+	})
+	fake.SetRecords("example.net", []base.DNSRecord{
+		{Subname: "www", Rectype: "A", Content: "198.51.100.1"},
+	})
+	r := resources.DataSourceZoneRecords()
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"zone": "example.com"})
+	if diags := r.ReadContext(context.Background(), d, fake); diags.HasError() {
+		t.Fatalf("Read: %v", diags)
+	}
+
+	var records []string
+	for _, record := range d.Get("records").([]interface{}) {
+		rr := record.(map[string]interface{})
+		records = append(records, fmt.Sprintf("%s %s %s prio=%d weight=%d port=%d flag=%d tag=%s",
+			rr["subname"], rr["type"], rr["content"], rr["prio"], rr["weight"], rr["port"], rr["flag"], rr["tag"]))
+	}
+	// Records are sorted by name, type and content, and those of other zones are left out
+	want := []string{
+		"@ CAA letsencrypt.org prio=0 weight=0 port=0 flag=128 tag=issue",
+		"@ MX mx.example.com. prio=10 weight=0 port=0 flag=0 tag=",
+		"_sip._tcp SRV sip.example.com. prio=10 weight=5 port=5060 flag=0 tag=",
+		"www A 192.0.2.1 prio=0 weight=0 port=0 flag=0 tag=",
+		"www A 192.0.2.2 prio=0 weight=0 port=0 flag=0 tag=",
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("records = %q, want %q", records, want)
+	}
+}
+
+func TestZoneRecordsMissingZone(t *testing.T) {
+	r := resources.DataSourceZoneRecords()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"zone": "missing.example"})
+	if diags := r.ReadContext(context.Background(), d, fakeclient.New("example.com")); !diags.HasError() {
+		t.Error("Read of a zone missing from the account succeeded")
+	}
+}
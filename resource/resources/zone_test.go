@@ -0,0 +1,86 @@
+package resources_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"terraform-provider-regru/internal/fakeclient"
+	"terraform-provider-regru/resource/base"
+	"terraform-provider-regru/resource/resources"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestZoneImport(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	fake.SetRecords("example.com", []base.DNSRecord{
+		{Subname: "www", Rectype: "A", Content: "192.0.2.2"},
+		{Subname: "@", Rectype: "A", Content: "192.0.2.1"},
+		{Subname: "www", Rectype: "A", Content: "192.0.2.1"},
+		{Subname: "@", Rectype: "MX", Content: "mx.example.com.", Prio: 10},
+		{Subname: "@", Rectype: "TXT", Content: "v=spf1 mx -all"},
+	})
+	r := resources.ResourceDNSZone()
+
+	d := r.Data(nil)
+	d.SetId("example.com.")
+	imported, err := r.Importer.StateContext(context.Background(), d, fake)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if len(imported) != 1 || imported[0].Id() != "example.com" {
+		t.Fatalf("imported = %v, want the zone example.com", imported)
+	}
+	d = imported[0]
+	if diags := r.ReadContext(context.Background(), d, fake); diags.HasError() {
+		t.Fatalf("Read: %v", diags)
+	}
+
+	// Every record type found gets one block per name, with the ID to import it with
+	for _, tc := range []struct {
+		block  string
+		blocks []map[string]interface{}
+	}{
+		{"a_record", []map[string]interface{}{
+			{"name": "@", "records": []interface{}{"192.0.2.1"}, "import_id": "example.com/@"},
+			{"name": "www", "records": []interface{}{"192.0.2.1", "192.0.2.2"}, "import_id": "example.com/www"},
+		}},
+		{"mx_record", []map[string]interface{}{
+			{"name": "@", "records": []interface{}{"10 mx.example.com."}, "import_id": "example.com/@"},
+		}},
+		{"txt_record", []map[string]interface{}{
+			{"name": "@", "records": []interface{}{"v=spf1 mx -all"}, "import_id": "example.com/@"},
+		}},
+		{"cname_record", []map[string]interface{}{}},
+	} {
+		blocks := []map[string]interface{}{}
+		for _, block := range d.Get(tc.block).([]interface{}) {
+			blocks = append(blocks, block.(map[string]interface{}))
+		}
+		if !reflect.DeepEqual(blocks, tc.blocks) {
+			t.Errorf("%s = %v, want %v", tc.block, blocks, tc.blocks)
+		}
+	}
+}
+
+func TestZoneImportRejectsRecordIDs(t *testing.T) {
+	r := resources.ResourceDNSZone()
+	d := r.Data(nil)
+	d.SetId("example.com/www")
+	if _, err := r.Importer.StateContext(context.Background(), d, fakeclient.New("example.com")); err == nil {
+		t.Error("Import of a record ID succeeded, want an error")
+	}
+}
+
+func TestZoneRemovedFromAccount(t *testing.T) {
+	r := resources.ResourceDNSZone()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"zone": "gone.example"})
+	d.SetId("gone.example")
+	if diags := r.ReadContext(context.Background(), d, fakeclient.New("example.com")); diags.HasError() {
+		t.Fatalf("Read: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("ID = %q after reading a zone missing from the account, want it cleared", d.Id())
+	}
+}
@@ -0,0 +1,69 @@
+package resources
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"terraform-provider-regru/resource/base"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// DataSourceZones creates the data source listing the zones managed in the account
+func DataSourceZones() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: withContext(readZones),
+		Schema: map[string]*schema.Schema{
+			"prefetch_records": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Fetch the records of all listed zones concurrently to warm the cache for record resources",
+			},
+			"zones": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The zones managed in the account",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// readZones lists the account's zones, optionally prefetching their records
+func readZones(d *schema.ResourceData, meta interface{}) error {
+	zones, err := base.AccountZones(meta)
+	if err != nil {
+		return err
+	}
+	sort.Strings(zones)
+
+	if d.Get("prefetch_records").(bool) {
+		if c, ok := meta.(base.CachedClientInterface); ok {
+			prefetchZones(c, zones)
+		}
+	}
+
+	d.SetId(fmt.Sprintf("%d:%s", len(zones), strings.Join(zones, ",")))
+	d.Set("zones", zones)
+
+	return nil
+}
+
+// prefetchZones reads the records of all zones concurrently so that they are served from the cache
+// afterwards. Failures are only logged; the records are fetched again when a resource reads them.
+func prefetchZones(c base.CachedClientInterface, zones []string) {
+	var wg sync.WaitGroup
+	for _, zone := range zones {
+		wg.Add(1)
+		go func(zone string) {
+			defer wg.Done()
+			if _, err := c.GetRecordsWithCache(zone); err != nil {
+				log.Printf("[DEBUG] Failed to prefetch records for zone %s: %v", zone, err)
+			}
+		}(zone)
+	}
+	wg.Wait()
+}
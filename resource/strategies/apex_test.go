@@ -0,0 +1,78 @@
+package strategies_test
+
+import (
+	"testing"
+
+	"terraform-provider-regru/internal/fakeclient"
+	"terraform-provider-regru/resource/base"
+	"terraform-provider-regru/resource/resources"
+	"terraform-provider-regru/resource/strategies"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// TestApexRecordsWithEmptySubname reads apex records the API reports with an empty subname
+// into resources configured with "@"
+func TestApexRecordsWithEmptySubname(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		strategy base.RecordTypeStrategy
+		resource *schema.Resource
+		record   base.DNSRecord
+		values   func(d *schema.ResourceData) []string
+		want     []string
+	}{
+		{
+			name:     "A",
+			strategy: strategies.NewARecordStrategy(),
+			resource: resources.ResourceDNSARecord(),
+			record:   base.DNSRecord{Rectype: "A", Content: "192.0.2.1"},
+			values:   func(d *schema.ResourceData) []string { return stringList(d.Get("records")) },
+			want:     []string{"192.0.2.1"},
+		},
+		{
+			name:     "MX",
+			strategy: strategies.NewMXRecordStrategy(),
+			resource: resources.ResourceDNSMXRecord(),
+			record:   base.DNSRecord{Rectype: "MX", Content: "mx.example.com.", Prio: 10},
+			values:   func(d *schema.ResourceData) []string { return stringList(d.Get("record.0.servers")) },
+			want:     []string{"mx.example.com"},
+		},
+		{
+			name:     "NS",
+			strategy: strategies.NewNSRecordStrategy(),
+			resource: resources.ResourceDNSNSRecord(),
+			record:   base.DNSRecord{Rectype: "NS", Content: "ns1.example.net."},
+			values:   func(d *schema.ResourceData) []string { return stringList(d.Get("record.0.servers")) },
+			want:     []string{"ns1.example.net"},
+		},
+		{
+			name:     "CAA",
+			strategy: strategies.NewCAARecordStrategy(),
+			resource: resources.ResourceDNSCAARecord(),
+			record:   base.DNSRecord{Rectype: "CAA", Content: "letsencrypt.org", Tag: "issue"},
+			values:   func(d *schema.ResourceData) []string { return []string{d.Get("record.0.value").(string)} },
+			want:     []string{"letsencrypt.org"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := fakeclient.New("example.com")
+			fake.SetRecords("example.com", []base.DNSRecord{tc.record})
+
+			d := importData(tc.resource, "example.com/@")
+			if err := tc.strategy.Import(fake, d); err != nil {
+				t.Fatalf("Import: %v", err)
+			}
+			if err := tc.strategy.Read(fake, d); err != nil {
+				t.Fatalf("Read: %v", err)
+			}
+			if d.Id() != "example.com/@" {
+				t.Fatalf("ID = %q, want example.com/@", d.Id())
+			}
+			if name := d.Get("name"); name != "@" {
+				t.Errorf("name = %v, want @", name)
+			}
+			expectStrings(t, "records", tc.values(d), tc.want)
+		})
+	}
+}
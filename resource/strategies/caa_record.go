@@ -36,30 +36,29 @@ func (caa CAARecord) String() string {
 
 // parseCAARecords converts the record from schema to CAARecord structs
 func (s *CAARecordStrategy) parseCAARecords(d *schema.ResourceData) ([]CAARecord, error) {
-	recordList := d.Get("record").([]interface{})
+	recordList, _ := d.Get("record").([]interface{})
+	return s.caaRecordsFromBlocks(recordList), nil
+}
 
+// caaRecordsFromBlocks converts record blocks to CAARecord structs
+func (s *CAARecordStrategy) caaRecordsFromBlocks(blocks []interface{}) []CAARecord {
 	var caaRecords []CAARecord
-	for _, recordInterface := range recordList {
-		recordMap := recordInterface.(map[string]interface{})
-
-		flag := recordMap["flag"].(int)
-		tag := recordMap["tag"].(string)
-		value := recordMap["value"].(string)
-
-		caaRecord := CAARecord{
-			Flag:  flag,
-			Tag:   tag,
-			Value: value,
-		}
-
-		caaRecords = append(caaRecords, caaRecord)
+	for _, record := range base.RecordsFromSchema("CAA", blocks) {
+		caaRecords = append(caaRecords, CAARecord{
+			Flag:  record.Flag,
+			Tag:   record.Tag,
+			Value: record.Value,
+		})
 	}
-
-	return caaRecords, nil
+	return caaRecords
 }
 
 // Create creates CAA records
 func (s *CAARecordStrategy) Create(meta interface{}, d *schema.ResourceData) error {
+	if err := base.ValidateResourceRecords("CAA", d); err != nil {
+		return err
+	}
+
 	c := meta.(base.CachedClientInterface)
 	zone := s.GetZone(d)
 	name := s.GetName(d)
@@ -90,19 +89,18 @@ func (s *CAARecordStrategy) Create(meta interface{}, d *schema.ResourceData) err
 
 		response, err := c.AddCAARecord(zone, name, caaRecord.Value, &caaRecord.Flag, &caaRecord.Tag)
 		if err != nil {
-			return fmt.Errorf("failed to create CAA record %s: %w", caaRecord.Value, err)
-		}
-
-		// Check API response for errors
-		if err := base.CheckAPIResponseForErrors(response); err != nil {
+			if !s.reconcileCAAAdd(c, err, zone, name, caaRecord) {
+				return fmt.Errorf("failed to create CAA record %s: %w", caaRecord.Value, err)
+			}
+		} else if err := base.CheckAPIResponseForErrors(response); err != nil {
 			return fmt.Errorf("failed to create CAA record %s: %w", caaRecord.Value, err)
 		}
 	}
 
 	// Set resource ID
-	d.SetId(fmt.Sprintf("%s/%s/%s", zone, name, "CAA"))
+	s.SetResourceID(d, zone, name, "CAA")
 
-	return s.Read(meta, d)
+	return s.ReadAfterWrite(c, d, zone, func() error { return s.Read(meta, d) })
 }
 
 // Read reads CAA records from the API
@@ -126,9 +124,29 @@ func (s *CAARecordStrategy) Read(meta interface{}, d *schema.ResourceData) error
 	if err := json.Unmarshal(response, &zoneResponse); err != nil {
 		return fmt.Errorf("failed to parse DNS records response: %w", err)
 	}
+	if err := zoneResponse.CheckZone(zone); err != nil {
+		return err
+	}
 
 	log.Printf("[DEBUG] Parsed response - domains: %d", len(zoneResponse.Answer.Domains))
 
+	// Keep the configured spelling of values so refresh doesn't report drift
+	var configuredValues []interface{}
+	configured, _ := s.parseCAARecords(d)
+	for _, record := range configured {
+		configuredValues = append(configuredValues, record.Value)
+	}
+
+	// In managed-only mode, tuples this resource doesn't manage are left out of state, so
+	// updates never remove them. Without managed tuples, as on import, all tuples are read.
+	var managed map[string]bool
+	if managedOnly, _ := d.Get("managed_only").(bool); managedOnly && len(configured) > 0 {
+		managed = make(map[string]bool, len(configured))
+		for _, record := range configured {
+			managed[caaTupleKey(record)] = true
+		}
+	}
+
 	// Parse response and find CAA records
 	var foundCAARecords []CAARecord
 	for _, domain := range zoneResponse.Answer.Domains {
@@ -137,44 +155,12 @@ func (s *CAARecordStrategy) Read(meta interface{}, d *schema.ResourceData) error
 			log.Printf("[DEBUG] Record: type=%s, subname=%s, content=%s, flag=%d, tag=%s",
 				record.Rectype, record.Subname, record.Content, record.Flag, record.Tag)
 
-			if record.Rectype == "CAA" && record.Subname == name {
-				var flag int
-				var tag string
-				var value string
-
-				// The API might provide flag and tag in separate fields or combined in content
-				if record.Flag != 0 || record.Tag != "" {
-					// Use separate fields if available
-					flag = record.Flag
-					tag = record.Tag
-					value = record.Content
-				} else {
-					// Parse CAA content format: "flag tag \"value\""
-					parts := strings.Fields(record.Content)
-					if len(parts) >= 3 {
-						// Parse flag
-						if flagVal, err := strconv.Atoi(parts[0]); err == nil {
-							flag = flagVal
-						}
-
-						// Parse tag
-						tag = parts[1]
-
-						// Parse value (remove quotes if present)
-						value = strings.Join(parts[2:], " ")
-						if strings.HasPrefix(value, "\"") && strings.HasSuffix(value, "\"") {
-							value = strings.Trim(value, "\"")
-						}
-					} else {
-						// Fallback: use content as value
-						value = record.Content
-					}
-				}
-
-				caaRecord := CAARecord{
-					Flag:  flag,
-					Tag:   tag,
-					Value: s.NormalizeDomain(value),
+			if record.Rectype == "CAA" && base.MatchesSubname(record.Subname, name) {
+				caaRecord := parseCAAContent(record)
+				caaRecord.Value = s.ConfiguredForm(caaRecord.Value, configuredValues)
+				if managed != nil && !managed[caaTupleKey(caaRecord)] {
+					log.Printf("[DEBUG] Ignoring unmanaged CAA record %s at %s.%s", caaRecord.String(), name, zone)
+					continue
 				}
 
 				foundCAARecords = append(foundCAARecords, caaRecord)
@@ -196,27 +182,76 @@ func (s *CAARecordStrategy) Read(meta interface{}, d *schema.ResourceData) error
 
 	log.Printf("[DEBUG] Sorted CAA records: %v", foundCAARecords)
 
-	// Set the data
+	// Set the data; IDs of the older zone/name/CAA form are migrated to zone/name
+	s.SetResourceID(d, zone, name, "CAA")
 	d.Set("zone", zone)
 	d.Set("name", name)
 
-	// Convert to interface slice for Terraform record schema
-	recordInterface := make([]interface{}, len(foundCAARecords))
-	for i, caaRecord := range foundCAARecords {
-		recordInterface[i] = map[string]interface{}{
-			"flag":  caaRecord.Flag,
-			"tag":   caaRecord.Tag,
-			"value": caaRecord.Value,
-		}
+	// Convert to record blocks for Terraform record schema
+	var records []base.Record
+	for _, caaRecord := range foundCAARecords {
+		records = append(records, base.Record{
+			Flag:  caaRecord.Flag,
+			Tag:   caaRecord.Tag,
+			Value: caaRecord.Value,
+		})
 	}
+	recordInterface := base.RecordsToSchema("CAA", records)
 	d.Set("record", recordInterface)
 
 	log.Printf("[DEBUG] Successfully read %d CAA records", len(foundCAARecords))
 	return nil
 }
 
+// parseCAAContent builds a CAA record from an API record. The API might provide flag and tag
+// in separate fields or combined in the content as "flag tag \"value\"".
+func parseCAAContent(record base.DNSRecord) CAARecord {
+	if record.Flag != 0 || record.Tag != "" {
+		// Use separate fields if available
+		return CAARecord{Flag: record.Flag, Tag: record.Tag, Value: record.Content}
+	}
+
+	parts := strings.Fields(record.Content)
+	if len(parts) < 3 {
+		// Fallback: use content as value
+		return CAARecord{Value: record.Content}
+	}
+
+	var caaRecord CAARecord
+	if flagVal, err := strconv.Atoi(parts[0]); err == nil {
+		caaRecord.Flag = flagVal
+	}
+	caaRecord.Tag = parts[1]
+
+	// Parse value (remove quotes if present)
+	value := strings.Join(parts[2:], " ")
+	if strings.HasPrefix(value, "\"") && strings.HasSuffix(value, "\"") {
+		value = strings.Trim(value, "\"")
+	}
+	caaRecord.Value = value
+	return caaRecord
+}
+
+// reconcileCAAAdd reports whether a CAA add that timed out was applied anyway, matching the
+// record by flag and tag as well as by value
+func (s *CAARecordStrategy) reconcileCAAAdd(c base.CachedClientInterface, err error, zone, name string, record CAARecord) bool {
+	key := caaTupleKey(record)
+	return s.ReconcileMatchingAfterTimeout(c, err, zone, name, "CAA", record.String(), func(rr base.DNSRecord) bool {
+		return caaTupleKey(parseCAAContent(rr)) == key
+	})
+}
+
+// caaTupleKey identifies a CAA tuple independently of tag case and value spelling
+func caaTupleKey(record CAARecord) string {
+	return fmt.Sprintf("%d|%s|%s", record.Flag, strings.ToLower(record.Tag), base.ComparableContent(record.Value))
+}
+
 // Update updates CAA records
 func (s *CAARecordStrategy) Update(meta interface{}, d *schema.ResourceData) error {
+	if err := base.ValidateResourceRecords("CAA", d); err != nil {
+		return err
+	}
+
 	c := meta.(base.CachedClientInterface)
 	zone := s.GetZone(d)
 	name := s.GetName(d)
@@ -293,10 +328,10 @@ func (s *CAARecordStrategy) Update(meta interface{}, d *schema.ResourceData) err
 				record.Flag, record.Tag, record.Value)
 			response, err := c.AddCAARecord(zone, name, record.Value, &record.Flag, &record.Tag)
 			if err != nil {
-				return fmt.Errorf("failed to add CAA record %s: %w", record.Value, err)
-			}
-
-			if err := base.CheckAPIResponseForErrors(response); err != nil {
+				if !s.reconcileCAAAdd(c, err, zone, name, record) {
+					return fmt.Errorf("failed to add CAA record %s: %w", record.Value, err)
+				}
+			} else if err := base.CheckAPIResponseForErrors(response); err != nil {
 				return fmt.Errorf("failed to add CAA record %s: %w", record.Value, err)
 			}
 		}
@@ -305,32 +340,14 @@ func (s *CAARecordStrategy) Update(meta interface{}, d *schema.ResourceData) err
 		c.InvalidateZoneCache(zone)
 	}
 
-	return s.Read(meta, d)
+	return s.ReadAfterWrite(c, d, zone, func() error { return s.Read(meta, d) })
 }
 
 // getOldCAARecords reconstructs old CAA records from the change data
 func (s *CAARecordStrategy) getOldCAARecords(d *schema.ResourceData) ([]CAARecord, error) {
 	old, _ := d.GetChange("record")
-	oldRecordList := old.([]interface{})
-
-	var caaRecords []CAARecord
-	for _, recordInterface := range oldRecordList {
-		recordMap := recordInterface.(map[string]interface{})
-
-		flag := recordMap["flag"].(int)
-		tag := recordMap["tag"].(string)
-		value := recordMap["value"].(string)
-
-		caaRecord := CAARecord{
-			Flag:  flag,
-			Tag:   tag,
-			Value: value,
-		}
-
-		caaRecords = append(caaRecords, caaRecord)
-	}
-
-	return caaRecords, nil
+	oldRecordList, _ := old.([]interface{})
+	return s.caaRecordsFromBlocks(oldRecordList), nil
 }
 
 // Delete deletes CAA records
@@ -351,11 +368,7 @@ func (s *CAARecordStrategy) Delete(meta interface{}, d *schema.ResourceData) err
 		log.Printf("[DEBUG] Removing CAA record: %s -> %d %s %s", name,
 			caaRecord.Flag, caaRecord.Tag, caaRecord.Value)
 		response, err := c.RemoveCAARecord(zone, name, caaRecord.Value, &caaRecord.Flag, &caaRecord.Tag)
-		if err != nil {
-			return fmt.Errorf("failed to delete CAA record %s: %w", caaRecord.Value, err)
-		}
-
-		if err := base.CheckAPIResponseForErrors(response); err != nil {
+		if err := base.CheckRemoveResponse(response, err); err != nil {
 			return fmt.Errorf("failed to delete CAA record %s: %w", caaRecord.Value, err)
 		}
 	}
@@ -369,7 +382,7 @@ func (s *CAARecordStrategy) Delete(meta interface{}, d *schema.ResourceData) err
 // Import imports an existing CAA record
 func (s *CAARecordStrategy) Import(meta interface{}, d *schema.ResourceData) error {
 	// Parse the import ID using the common format
-	zone, name, err := s.ParseResourceID(d.Id())
+	zone, name, err := s.ParseImportID(meta, d.Id())
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,197 @@
+package strategies_test
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"terraform-provider-regru/internal/fakeclient"
+	"terraform-provider-regru/resource/base"
+	"terraform-provider-regru/resource/resources"
+	"terraform-provider-regru/resource/strategies"
+)
+
+// caaContents returns the CAA records the fake client holds at a name as "flag tag value", sorted
+func caaContents(f *fakeclient.Client, zone, name string) []string {
+	contents := []string{}
+	for _, rr := range f.Records(zone) {
+		if rr.Rectype == "CAA" && rr.Subname == name {
+			contents = append(contents, fmt.Sprintf("%d %s %s", rr.Flag, rr.Tag, rr.Content))
+		}
+	}
+	sort.Strings(contents)
+	return contents
+}
+
+// caaBlock returns a CAA record block of the resource configuration
+func caaBlock(flag int, tag, value string) map[string]interface{} {
+	return map[string]interface{}{"flag": flag, "tag": tag, "value": value}
+}
+
+func TestCAARecordLifecycle(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	strategy := strategies.NewCAARecordStrategy()
+	resource := resources.ResourceDNSCAARecord()
+
+	d := newData(t, resource, map[string]interface{}{
+		"zone": "example.com",
+		"name": "@",
+		"record": []interface{}{
+			caaBlock(0, "issue", "letsencrypt.org"),
+			caaBlock(0, "iodef", "mailto:security@example.com"),
+		},
+	})
+	if err := strategy.Create(fake, d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	expectStrings(t, "zone after create", caaContents(fake, "example.com", "@"), []string{
+		"0 iodef mailto:security@example.com",
+		"0 issue letsencrypt.org",
+	})
+
+	// Changing the flag of a tuple replaces it, the other tuple is left alone
+	fake.Calls = nil
+	d = changedData(t, resource, d.State(), map[string]interface{}{
+		"zone": "example.com",
+		"name": "@",
+		"record": []interface{}{
+			caaBlock(128, "issue", "letsencrypt.org"),
+			caaBlock(0, "iodef", "mailto:security@example.com"),
+		},
+	})
+	if err := strategy.Update(fake, d); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	expectStrings(t, "update calls", fake.Calls, []string{
+		"remove CAA @ letsencrypt.org",
+		"add CAA @ letsencrypt.org",
+	})
+	expectStrings(t, "zone after update", caaContents(fake, "example.com", "@"), []string{
+		"0 iodef mailto:security@example.com",
+		"128 issue letsencrypt.org",
+	})
+
+	if err := strategy.Delete(fake, d); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	expectStrings(t, "zone after delete", caaContents(fake, "example.com", "@"), []string{})
+}
+
+func TestCAARecordManagedOnly(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	fake.SetRecords("example.com", []base.DNSRecord{
+		{Subname: "@", Rectype: "CAA", Flag: 0, Tag: "issue", Content: "pki.goog"},
+	})
+	strategy := strategies.NewCAARecordStrategy()
+	resource := resources.ResourceDNSCAARecord()
+
+	d := newData(t, resource, map[string]interface{}{
+		"zone":         "example.com",
+		"name":         "@",
+		"managed_only": true,
+		"record":       []interface{}{caaBlock(0, "issue", "letsencrypt.org")},
+	})
+	if err := strategy.Create(fake, d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// The tuple managed elsewhere stays out of state and survives the delete
+	records := d.Get("record").([]interface{})
+	if len(records) != 1 || records[0].(map[string]interface{})["value"] != "letsencrypt.org" {
+		t.Errorf("record = %v, want only the managed tuple", records)
+	}
+
+	// An iodef tuple added by hand survives an update replacing the managed tuples
+	fake.SetRecords("example.com", append(fake.Records("example.com"), base.DNSRecord{Subname: "@", Rectype: "CAA", Flag: 0, Tag: "iodef", Content: "mailto:security@example.com"}))
+	d = changedData(t, resource, d.State(), map[string]interface{}{
+		"zone":         "example.com",
+		"name":         "@",
+		"managed_only": true,
+		"record":       []interface{}{caaBlock(0, "issuewild", "letsencrypt.org")},
+	})
+	if err := strategy.Update(fake, d); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	expectStrings(t, "zone after update", caaContents(fake, "example.com", "@"), []string{
+		"0 iodef mailto:security@example.com",
+		"0 issue pki.goog",
+		"0 issuewild letsencrypt.org",
+	})
+
+	if err := strategy.Delete(fake, d); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	expectStrings(t, "zone after delete", caaContents(fake, "example.com", "@"), []string{
+		"0 iodef mailto:security@example.com",
+		"0 issue pki.goog",
+	})
+}
+
+func TestCAARecordID(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	strategy := strategies.NewCAARecordStrategy()
+	resource := resources.ResourceDNSCAARecord()
+
+	// The ID set on create survives a refresh and can be imported
+	d := newData(t, resource, map[string]interface{}{
+		"zone":   "example.com",
+		"name":   "@",
+		"record": []interface{}{caaBlock(0, "issue", "letsencrypt.org")},
+	})
+	if err := strategy.Create(fake, d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if d.Id() != "example.com/@" {
+		t.Fatalf("ID = %q, want example.com/@", d.Id())
+	}
+	if err := strategy.Read(fake, d); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if d.Id() != "example.com/@" {
+		t.Errorf("ID after refresh = %q, want example.com/@", d.Id())
+	}
+
+	// IDs with the record type, as set by earlier versions, are still accepted
+	for _, id := range []string{d.Id(), "example.com/@/CAA"} {
+		imported := importData(resource, id)
+		if err := strategy.Import(fake, imported); err != nil {
+			t.Fatalf("Import %s: %v", id, err)
+		}
+		if zone, name := imported.Get("zone"), imported.Get("name"); zone != "example.com" || name != "@" {
+			t.Errorf("import %s: zone, name = %v, %v, want example.com, @", id, zone, name)
+		}
+		records := imported.Get("record").([]interface{})
+		if len(records) != 1 || records[0].(map[string]interface{})["value"] != "letsencrypt.org" {
+			t.Errorf("import %s: record = %v, want the created tuple", id, records)
+		}
+	}
+}
+
+func TestCAARecordAddAfterTimeout(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	strategy := strategies.NewCAARecordStrategy()
+	resource := resources.ResourceDNSCAARecord()
+	config := map[string]interface{}{
+		"zone":   "example.com",
+		"name":   "@",
+		"record": []interface{}{caaBlock(0, "issue", "letsencrypt.org")},
+	}
+
+	// The record turns out to be present, so the add is neither failed nor repeated
+	d := newData(t, resource, config)
+	if err := strategy.Create(timeoutClient{Client: fake}, d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	expectStrings(t, "zone after create", caaContents(fake, "example.com", "@"), []string{"0 issue letsencrypt.org"})
+
+	// A lost add is reported even though the value is present with another flag or tag
+	fake = fakeclient.New("example.com")
+	fake.SetRecords("example.com", []base.DNSRecord{
+		{Subname: "@", Rectype: "CAA", Content: "letsencrypt.org", Tag: "issuewild"},
+		{Subname: "@", Rectype: "CAA", Content: "letsencrypt.org", Flag: 128, Tag: "issue"},
+	})
+	d = newData(t, resource, config)
+	if err := strategy.Create(timeoutClient{Client: fake, lost: true}, d); err == nil {
+		t.Fatal("Create succeeded although the record was never added")
+	}
+}
@@ -1,8 +1,8 @@
 package strategies
 
 import (
-	"encoding/json"
 	"fmt"
+	"strings"
 	"terraform-provider-regru/resource/base"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -45,6 +45,10 @@ func (s *CNAMERecordStrategy) ValidateRecords(records []interface{}) error {
 
 // Create creates CNAME records
 func (s *CNAMERecordStrategy) Create(client interface{}, d *schema.ResourceData) error {
+	if err := base.ValidateResourceRecords("CNAME", d); err != nil {
+		return err
+	}
+
 	// Type assert to get the cached client using shared interface
 	c, ok := client.(base.CachedClientInterface)
 	if !ok {
@@ -58,14 +62,14 @@ func (s *CNAMERecordStrategy) Create(client interface{}, d *schema.ResourceData)
 	s.LogResourceOperation("Creating", "CNAME", zone, name)
 
 	// For CNAME records, we need to add trailing dots for domain names
-	apiRecord := s.AddTrailingDot(cname)
+	apiRecord := s.AddTrailingDot(c, cname)
 	response, err := c.AddRecord("CNAME", zone, name, apiRecord, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create CNAME record: %w", err)
-	}
-
-	// Check API response for errors
-	if err := base.CheckAPIResponseForErrors(response); err != nil {
+		if !s.ReconcileAfterTimeout(c, err, zone, name, "CNAME", apiRecord) {
+			return fmt.Errorf("failed to create CNAME record: %w", err)
+		}
+	} else if err := base.CheckAPIResponseForErrors(response); err != nil {
+		// Check API response for errors
 		return fmt.Errorf("failed to create CNAME record: %w", err)
 	}
 
@@ -87,29 +91,15 @@ func (s *CNAMERecordStrategy) Read(client interface{}, d *schema.ResourceData) e
 
 	s.LogResourceOperation("Reading", "CNAME", zone, name)
 
-	response, err := c.GetRecordsWithCache(zone)
+	records, err := c.GetRecordsByType(zone, name, "CNAME")
 	if err != nil {
 		return fmt.Errorf("failed to get zone records: %w", err)
 	}
 
-	var zoneResponse base.DNSZoneResponse
-	if err := json.Unmarshal(response, &zoneResponse); err != nil {
-		return fmt.Errorf("failed to parse DNS records response: %w", err)
-	}
-
-	// Find CNAME records for this subdomain
+	// Find the CNAME record for this subdomain
 	var foundCNAME string
-	for _, domain := range zoneResponse.Answer.Domains {
-		if domain.Dname == zone {
-			for _, rr := range domain.Rrs {
-				if rr.Subname == name && rr.Rectype == "CNAME" {
-					// Remove trailing dot from content for consistency
-					foundCNAME = s.NormalizeDomain(rr.Content)
-					break
-				}
-			}
-			break
-		}
+	if len(records) > 0 {
+		foundCNAME = s.ConfiguredForm(records[0].Content, s.GetRecords(d))
 	}
 
 	if foundCNAME == "" {
@@ -128,6 +118,10 @@ func (s *CNAMERecordStrategy) Read(client interface{}, d *schema.ResourceData) e
 
 // Update updates CNAME records
 func (s *CNAMERecordStrategy) Update(client interface{}, d *schema.ResourceData) error {
+	if err := base.ValidateResourceRecords("CNAME", d); err != nil {
+		return err
+	}
+
 	// Type assert to get the cached client using shared interface
 	c, ok := client.(base.CachedClientInterface)
 	if !ok {
@@ -144,10 +138,16 @@ func (s *CNAMERecordStrategy) Update(client interface{}, d *schema.ResourceData)
 	oldCNAMEStr := oldCNAME.(string)
 	newCNAMEStr := newCNAME.(string)
 
+	// A change of the trailing dot or letter case only leaves the record as it is; replacing
+	// it would briefly remove it and lose it entirely if the add failed
+	if oldCNAMEStr != "" && strings.EqualFold(s.NormalizeDomain(oldCNAMEStr), s.NormalizeDomain(newCNAMEStr)) {
+		return nil
+	}
+
 	// Delete the old record first (required due to DNS CNAME constraints)
 	if oldCNAMEStr != "" {
-		apiOldRecord := s.AddTrailingDot(oldCNAMEStr)
-		response, err := c.RemoveRecord(zone, name, "CNAME", apiOldRecord, nil)
+		apiOldRecord := s.AddTrailingDot(c, oldCNAMEStr)
+		response, err := c.RemoveRecord(zone, name, "CNAME", s.StoredContent(c, zone, name, "CNAME", apiOldRecord), nil)
 		if err != nil {
 			return fmt.Errorf("failed to delete old CNAME record: %w", err)
 		}
@@ -160,14 +160,14 @@ func (s *CNAMERecordStrategy) Update(client interface{}, d *schema.ResourceData)
 
 	// Add the new record
 	if newCNAMEStr != "" {
-		apiNewRecord := s.AddTrailingDot(newCNAMEStr)
+		apiNewRecord := s.AddTrailingDot(c, newCNAMEStr)
 		response, err := c.AddRecord("CNAME", zone, name, apiNewRecord, nil)
 		if err != nil {
-			return fmt.Errorf("failed to create new CNAME record: %w", err)
-		}
-
-		// Check API response for errors
-		if err := base.CheckAPIResponseForErrors(response); err != nil {
+			if !s.ReconcileAfterTimeout(c, err, zone, name, "CNAME", apiNewRecord) {
+				return fmt.Errorf("failed to create new CNAME record: %w", err)
+			}
+		} else if err := base.CheckAPIResponseForErrors(response); err != nil {
+			// Check API response for errors
 			return fmt.Errorf("failed to create new CNAME record: %w", err)
 		}
 	}
@@ -200,14 +200,9 @@ func (s *CNAMERecordStrategy) Delete(client interface{}, d *schema.ResourceData)
 
 	if cname != "" {
 		// For CNAME records, we need to add trailing dots for domain names
-		apiRecord := s.AddTrailingDot(cname)
-		response, err := c.RemoveRecord(zone, name, "CNAME", apiRecord, nil)
-		if err != nil {
-			return fmt.Errorf("failed to delete CNAME record: %w", err)
-		}
-
-		// Check API response for errors
-		if err := base.CheckAPIResponseForErrors(response); err != nil {
+		apiRecord := s.AddTrailingDot(c, cname)
+		response, err := c.RemoveRecord(zone, name, "CNAME", s.StoredContent(c, zone, name, "CNAME", apiRecord), nil)
+		if err := base.CheckRemoveResponse(response, err); err != nil {
 			return fmt.Errorf("failed to delete CNAME record: %w", err)
 		}
 	}
@@ -220,7 +215,7 @@ func (s *CNAMERecordStrategy) Delete(client interface{}, d *schema.ResourceData)
 // Import imports an existing CNAME record
 func (s *CNAMERecordStrategy) Import(client interface{}, d *schema.ResourceData) error {
 	// Parse the import ID using the common format
-	zone, name, err := s.ParseResourceID(d.Id())
+	zone, name, err := s.ParseImportID(client, d.Id())
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,86 @@
+package strategies_test
+
+import (
+	"testing"
+
+	"terraform-provider-regru/internal/fakeclient"
+	"terraform-provider-regru/resource/resources"
+	"terraform-provider-regru/resource/strategies"
+)
+
+// noTrailingDotClient is a fake client configured with disable_trailing_dot
+type noTrailingDotClient struct {
+	*fakeclient.Client
+}
+
+func (noTrailingDotClient) TrailingDotDisabled() bool {
+	return true
+}
+
+func TestCNAMERecordTrailingDot(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		client func(*fakeclient.Client) interface{}
+		want   string
+	}{
+		{"added by default", func(f *fakeclient.Client) interface{} { return f }, "target.example.net."},
+		{"disabled per client", func(f *fakeclient.Client) interface{} { return noTrailingDotClient{f} }, "target.example.net"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := fakeclient.New("example.com")
+			d := newData(t, resources.ResourceDNSCNAMERecord(), map[string]interface{}{
+				"zone":  "example.com",
+				"name":  "www",
+				"cname": "target.example.net",
+			})
+			if err := strategies.NewCNAMERecordStrategy().Create(tc.client(fake), d); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			expectStrings(t, "zone after create", zoneContents(fake, "example.com", "www", "CNAME"), []string{tc.want})
+		})
+	}
+}
+
+func TestCNAMERecordUpdateSkipsTrailingDotChange(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	strategy := strategies.NewCNAMERecordStrategy()
+	resource := resources.ResourceDNSCNAMERecord()
+
+	d := newData(t, resource, map[string]interface{}{
+		"zone":  "example.com",
+		"name":  "www",
+		"cname": "target.example.net",
+	})
+	if err := strategy.Create(fake, d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// Only the spelling of the target changes, so the record is left in place
+	fake.Calls = nil
+	d = changedData(t, resource, d.State(), map[string]interface{}{
+		"zone":  "example.com",
+		"name":  "www",
+		"cname": "Target.example.net.",
+	})
+	if err := strategy.Update(fake, d); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if len(fake.Calls) != 0 {
+		t.Errorf("update calls = %q, want none", fake.Calls)
+	}
+	expectStrings(t, "zone after update", zoneContents(fake, "example.com", "www", "CNAME"), []string{"target.example.net."})
+
+	// A different target replaces the record
+	d = changedData(t, resource, d.State(), map[string]interface{}{
+		"zone":  "example.com",
+		"name":  "www",
+		"cname": "other.example.net",
+	})
+	if err := strategy.Update(fake, d); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	expectStrings(t, "update calls", fake.Calls, []string{
+		"remove CNAME www target.example.net.",
+		"add CNAME www other.example.net.",
+	})
+}
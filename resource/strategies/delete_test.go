@@ -0,0 +1,149 @@
+package strategies_test
+
+import (
+	"testing"
+
+	"terraform-provider-regru/internal/fakeclient"
+	"terraform-provider-regru/resource/base"
+	"terraform-provider-regru/resource/resources"
+	"terraform-provider-regru/resource/strategies"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// recordNotFound is the API response to removing a record that no longer exists
+var recordNotFound = []byte(`{"result":"success","answer":{"domains":[{"dname":"example.com","result":"error","error_code":"RR_NOT_FOUND","error_text":"Record not found"}]}}`)
+
+// goneClient is a fake client whose records were removed by someone else after they were read
+type goneClient struct {
+	*fakeclient.Client
+}
+
+func (goneClient) RemoveRecord(domainName, subdomain, recordType, content string, priority *int) ([]byte, error) {
+	return recordNotFound, nil
+}
+
+func (goneClient) RemoveSRVRecord(domainName, subdomain, target string, priority, weight, port *int) ([]byte, error) {
+	return recordNotFound, nil
+}
+
+func (goneClient) RemoveCAARecord(domainName, subdomain, value string, flag *int, tag *string) ([]byte, error) {
+	return recordNotFound, nil
+}
+
+func TestDeleteToleratesRecordsAlreadyRemoved(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		strategy interface {
+			Create(meta interface{}, d *schema.ResourceData) error
+			Delete(meta interface{}, d *schema.ResourceData) error
+		}
+		resource *schema.Resource
+		config   map[string]interface{}
+	}{
+		{
+			name:     "A",
+			strategy: strategies.NewARecordStrategy(),
+			resource: resources.ResourceDNSARecord(),
+			config:   map[string]interface{}{"zone": "example.com", "name": "www", "records": []interface{}{"192.0.2.1"}},
+		},
+		{
+			name:     "MX",
+			strategy: strategies.NewMXRecordStrategy(),
+			resource: resources.ResourceDNSMXRecord(),
+			config: map[string]interface{}{"zone": "example.com", "name": "@", "record": []interface{}{
+				map[string]interface{}{"priority": 10, "servers": []interface{}{"mx1.example.com"}},
+			}},
+		},
+		{
+			name:     "SRV",
+			strategy: strategies.NewSRVRecordStrategy(),
+			resource: resources.ResourceDNSSRVRecord(),
+			config: map[string]interface{}{"zone": "example.com", "name": "_sip._tcp", "record": []interface{}{
+				map[string]interface{}{"priority": 10, "weight": 5, "port": 5060, "targets": []interface{}{"sip.example.com"}},
+			}},
+		},
+		{
+			name:     "CAA",
+			strategy: strategies.NewCAARecordStrategy(),
+			resource: resources.ResourceDNSCAARecord(),
+			config:   map[string]interface{}{"zone": "example.com", "name": "@", "record": []interface{}{caaBlock(0, "issue", "letsencrypt.org")}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := fakeclient.New("example.com")
+			d := newData(t, tc.resource, tc.config)
+			if err := tc.strategy.Create(fake, d); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+
+			fake.NewRun()
+			if err := tc.strategy.Delete(goneClient{fake}, d); err != nil {
+				t.Errorf("Delete: %v", err)
+			}
+		})
+	}
+}
+
+// exactRemoveClient is a fake client whose removals only match the content exactly as stored,
+// like an API that doesn't normalize the content of removal requests
+type exactRemoveClient struct {
+	*fakeclient.Client
+}
+
+func (c exactRemoveClient) RemoveRecord(domainName, subdomain, recordType, content string, priority *int) ([]byte, error) {
+	for _, rr := range c.Records(domainName) {
+		if rr.Rectype == recordType && rr.Content == content {
+			return c.Client.RemoveRecord(domainName, subdomain, recordType, content, priority)
+		}
+	}
+	return recordNotFound, nil
+}
+
+func TestRemovalUsesStoredContent(t *testing.T) {
+	t.Run("TXT delete", func(t *testing.T) {
+		fake := fakeclient.New("example.com")
+		fake.SetRecords("example.com", []base.DNSRecord{{Subname: "@", Rectype: "TXT", Content: `"v=spf1 -all"`}})
+		strategy := strategies.NewTXTRecordStrategy()
+
+		d := newData(t, resources.ResourceDNSTXTRecord(), map[string]interface{}{
+			"zone":    "example.com",
+			"name":    "@",
+			"records": []interface{}{"v=spf1 -all"},
+		})
+		d.SetId("example.com/@")
+		fake.NewRun()
+		if err := strategy.Delete(exactRemoveClient{fake}, d); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		expectStrings(t, "delete calls", fake.Calls, []string{`remove TXT @ "v=spf1 -all"`})
+		expectStrings(t, "zone after delete", zoneContents(fake, "example.com", "@", "TXT"), []string{})
+	})
+
+	t.Run("CNAME update", func(t *testing.T) {
+		fake := fakeclient.New("example.com")
+		fake.SetRecords("example.com", []base.DNSRecord{{Subname: "www", Rectype: "CNAME", Content: "Old.Example.NET."}})
+		strategy := strategies.NewCNAMERecordStrategy()
+		resource := resources.ResourceDNSCNAMERecord()
+
+		d := newData(t, resource, map[string]interface{}{
+			"zone":  "example.com",
+			"name":  "www",
+			"cname": "old.example.net",
+		})
+		d.SetId("example.com/www")
+		if err := strategy.Read(fake, d); err != nil {
+			t.Fatalf("Read: %v", err)
+		}
+		d = changedData(t, resource, d.State(), map[string]interface{}{
+			"zone":  "example.com",
+			"name":  "www",
+			"cname": "new.example.net",
+		})
+		fake.NewRun()
+		if err := strategy.Update(exactRemoveClient{fake}, d); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		expectStrings(t, "zone after update", zoneContents(fake, "example.com", "www", "CNAME"), []string{"new.example.net."})
+	})
+}
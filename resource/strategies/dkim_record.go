@@ -0,0 +1,242 @@
+package strategies
+
+import (
+	"fmt"
+	"strings"
+	"terraform-provider-regru/resource/base"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// DKIMKeyPrefix is the version tag every DKIM key record must start with
+const DKIMKeyPrefix = "v=DKIM1"
+
+// DKIMRecordStrategy implements the strategy for DKIM keys published as TXT records
+type DKIMRecordStrategy struct {
+	base.BaseStrategy
+}
+
+// NewDKIMRecordStrategy creates a new DKIM record strategy
+func NewDKIMRecordStrategy() *DKIMRecordStrategy {
+	return &DKIMRecordStrategy{}
+}
+
+// DKIMRecordName returns the record name a DKIM selector is published under
+func DKIMRecordName(selector string) string {
+	return selector + "._domainkey"
+}
+
+// NormalizeDKIMValue reassembles a key that may have been pasted as quoted chunks
+// (as found in BIND zone files) into a single logical value
+func NormalizeDKIMValue(value string) string {
+	return strings.TrimSpace(base.JoinTXTChunks(strings.TrimSpace(value)))
+}
+
+// GetRecords returns the DKIM key as a TXT value split into valid chunks
+func (s *DKIMRecordStrategy) GetRecords(d *schema.ResourceData) []interface{} {
+	value := NormalizeDKIMValue(d.Get("value").(string))
+	return []interface{}{base.ChunkTXTValue(value)}
+}
+
+// SetResourceID sets a stable resource ID for the DKIM record
+func (s *DKIMRecordStrategy) SetResourceID(d *schema.ResourceData, zone, name, recordType string) {
+	d.SetId(fmt.Sprintf("%s/%s", zone, name))
+}
+
+// ValidateRecords validates DKIM records
+func (s *DKIMRecordStrategy) ValidateRecords(records []interface{}) error {
+	if len(records) != 1 {
+		return fmt.Errorf("DKIM record must have exactly one key")
+	}
+
+	value := NormalizeDKIMValue(records[0].(string))
+	if !strings.HasPrefix(value, DKIMKeyPrefix) {
+		return fmt.Errorf("DKIM record must start with %q", DKIMKeyPrefix)
+	}
+
+	return nil
+}
+
+// Create creates the DKIM record
+func (s *DKIMRecordStrategy) Create(client interface{}, d *schema.ResourceData) error {
+	if err := base.ValidateResourceRecords("TXT", d); err != nil {
+		return err
+	}
+
+	// Type assert to get the cached client using shared interface
+	c, ok := client.(base.CachedClientInterface)
+	if !ok {
+		return fmt.Errorf("invalid client type for DKIM record creation")
+	}
+
+	zone := s.GetZone(d)
+	name := DKIMRecordName(d.Get("selector").(string))
+	d.Set("name", name)
+
+	records := s.GetRecords(d)
+	if err := s.ValidateRecords([]interface{}{d.Get("value").(string)}); err != nil {
+		return err
+	}
+
+	s.LogResourceOperation("Creating", "DKIM", zone, name)
+
+	content := records[0].(string)
+	response, err := c.AddRecord("TXT", zone, name, content, nil)
+	if err != nil {
+		if !s.ReconcileAfterTimeout(c, err, zone, name, "TXT", content) {
+			return fmt.Errorf("failed to create DKIM record: %w", err)
+		}
+	} else if err := base.CheckAPIResponseForErrors(response); err != nil {
+		return fmt.Errorf("failed to create DKIM record: %w", err)
+	}
+
+	s.SetResourceID(d, zone, name, "TXT")
+	c.InvalidateZoneCache(zone)
+	return nil
+}
+
+// Read reads the DKIM record from the API
+func (s *DKIMRecordStrategy) Read(client interface{}, d *schema.ResourceData) error {
+	// Type assert to get the cached client using shared interface
+	c, ok := client.(base.CachedClientInterface)
+	if !ok {
+		return fmt.Errorf("invalid client type for DKIM record read")
+	}
+
+	zone := s.GetZone(d)
+	name := s.GetName(d)
+
+	s.LogResourceOperation("Reading", "DKIM", zone, name)
+
+	records, err := c.GetRecordsByType(zone, name, "TXT")
+	if err != nil {
+		return fmt.Errorf("failed to get zone records: %w", err)
+	}
+
+	// Prefer the key matching the configuration when several DKIM keys share the name
+	configured := NormalizeDKIMValue(d.Get("value").(string))
+	var foundValue string
+	for _, rr := range records {
+		value := NormalizeDKIMValue(rr.Content)
+		if !strings.HasPrefix(value, DKIMKeyPrefix) {
+			continue
+		}
+		if foundValue == "" || value == configured {
+			foundValue = value
+		}
+	}
+
+	if foundValue == "" {
+		// No record found, mark as deleted
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("zone", zone)
+	d.Set("name", name)
+	d.Set("selector", strings.TrimSuffix(name, "._domainkey"))
+	d.Set("value", foundValue)
+
+	return nil
+}
+
+// Update replaces the DKIM key
+func (s *DKIMRecordStrategy) Update(client interface{}, d *schema.ResourceData) error {
+	if err := base.ValidateResourceRecords("TXT", d); err != nil {
+		return err
+	}
+
+	// Type assert to get the cached client using shared interface
+	c, ok := client.(base.CachedClientInterface)
+	if !ok {
+		return fmt.Errorf("invalid client type for DKIM record update")
+	}
+
+	zone := s.GetZone(d)
+	name := s.GetName(d)
+
+	s.LogResourceOperation("Updating", "DKIM", zone, name)
+
+	oldValue, newValue := d.GetChange("value")
+	if err := s.ValidateRecords([]interface{}{newValue.(string)}); err != nil {
+		return err
+	}
+
+	if oldStr := NormalizeDKIMValue(oldValue.(string)); oldStr != "" {
+		if err := s.removeKey(c, zone, name, oldStr); err != nil {
+			return fmt.Errorf("failed to delete old DKIM record: %w", err)
+		}
+	}
+
+	content := base.ChunkTXTValue(NormalizeDKIMValue(newValue.(string)))
+	response, err := c.AddRecord("TXT", zone, name, content, nil)
+	if err != nil {
+		if !s.ReconcileAfterTimeout(c, err, zone, name, "TXT", content) {
+			return fmt.Errorf("failed to create new DKIM record: %w", err)
+		}
+	} else if err := base.CheckAPIResponseForErrors(response); err != nil {
+		return fmt.Errorf("failed to create new DKIM record: %w", err)
+	}
+
+	c.InvalidateZoneCache(zone)
+	return nil
+}
+
+// Delete deletes the DKIM record
+func (s *DKIMRecordStrategy) Delete(client interface{}, d *schema.ResourceData) error {
+	// Type assert to get the cached client using shared interface
+	c, ok := client.(base.CachedClientInterface)
+	if !ok {
+		return fmt.Errorf("invalid client type for DKIM record deletion")
+	}
+
+	zone := s.GetZone(d)
+	name := s.GetName(d)
+
+	s.LogResourceOperation("Deleting", "DKIM", zone, name)
+
+	if value := NormalizeDKIMValue(d.Get("value").(string)); value != "" {
+		if err := s.removeKey(c, zone, name, value); err != nil {
+			return fmt.Errorf("failed to delete DKIM record: %w", err)
+		}
+	}
+
+	c.InvalidateZoneCache(zone)
+	return nil
+}
+
+// removeKey removes the TXT record holding the key, using the content as stored by the server
+func (s *DKIMRecordStrategy) removeKey(c base.CachedClientInterface, zone, name, value string) error {
+	content := base.ChunkTXTValue(value)
+
+	// The key may have been chunked differently when it was published outside Terraform
+	if records, err := c.GetRecordsByType(zone, name, "TXT"); err == nil {
+		for _, rr := range records {
+			if NormalizeDKIMValue(rr.Content) == value {
+				content = rr.Content
+			}
+		}
+	}
+
+	response, err := c.RemoveRecord(zone, name, "TXT", content, nil)
+	return base.CheckRemoveResponse(response, err)
+}
+
+// Import imports an existing DKIM record
+func (s *DKIMRecordStrategy) Import(client interface{}, d *schema.ResourceData) error {
+	// Parse the import ID using the common format
+	zone, name, err := s.ParseImportID(client, d.Id())
+	if err != nil {
+		return err
+	}
+
+	if !strings.HasSuffix(name, "._domainkey") {
+		return fmt.Errorf("DKIM record name must end with ._domainkey, got %q", name)
+	}
+
+	d.Set("zone", zone)
+	d.Set("name", name)
+	d.Set("selector", strings.TrimSuffix(name, "._domainkey"))
+
+	return s.Read(client, d)
+}
@@ -0,0 +1,97 @@
+package strategies_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"regexp"
+	"strings"
+	"testing"
+
+	"terraform-provider-regru/internal/fakeclient"
+	"terraform-provider-regru/resource/resources"
+	"terraform-provider-regru/resource/strategies"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// txtStrings matches the quoted character-strings of TXT record content
+var txtStrings = regexp.MustCompile(`"([^"]*)"`)
+
+func TestDKIMRecordRoundTrip(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("encoding key: %v", err)
+	}
+	value := "v=DKIM1; k=rsa; p=" + base64.StdEncoding.EncodeToString(der)
+
+	fake := fakeclient.New("example.com")
+	strategy := strategies.NewDKIMRecordStrategy()
+	resource := resources.ResourceDNSDKIMRecord()
+
+	d := newData(t, resource, map[string]interface{}{
+		"zone":     "example.com",
+		"selector": "mail",
+		"value":    value,
+	})
+	if err := strategy.Create(fake, d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if d.Id() != "example.com/mail._domainkey" {
+		t.Errorf("ID = %q, want example.com/mail._domainkey", d.Id())
+	}
+
+	// The key is stored as one TXT record of strings within the 255-byte limit
+	stored := fake.Records("example.com")
+	if len(stored) != 1 || stored[0].Subname != "mail._domainkey" || stored[0].Rectype != "TXT" {
+		t.Fatalf("zone = %+v, want one TXT record at mail._domainkey", stored)
+	}
+	var joined strings.Builder
+	chunks := txtStrings.FindAllStringSubmatch(stored[0].Content, -1)
+	if len(chunks) < 2 {
+		t.Errorf("content %q is not split into several strings", stored[0].Content)
+	}
+	for _, chunk := range chunks {
+		if len(chunk[1]) > 255 {
+			t.Errorf("string of %d bytes exceeds the 255-byte limit", len(chunk[1]))
+		}
+		joined.WriteString(chunk[1])
+	}
+	if joined.String() != value {
+		t.Errorf("joined strings = %q, want %q", joined.String(), value)
+	}
+
+	// Importing reads the key back as a single value
+	d = importData(resource, "example.com/mail._domainkey")
+	if err := strategy.Import(fake, d); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if err := strategy.Read(fake, d); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if got := d.Get("value"); got != value {
+		t.Errorf("value = %q, want %q", got, value)
+	}
+	if got := d.Get("selector"); got != "mail" {
+		t.Errorf("selector = %q, want mail", got)
+	}
+}
+
+func TestDKIMRecordRequiresVersionPrefix(t *testing.T) {
+	diags := resources.ResourceDNSDKIMRecord().Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+		"zone":     "example.com",
+		"selector": "mail",
+		"value":    "k=rsa; p=MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEA",
+	}))
+	if !diags.HasError() {
+		t.Fatal("value without v=DKIM1 accepted")
+	}
+	if detail := diags[0].Summary + diags[0].Detail; !strings.Contains(detail, "v=DKIM1") {
+		t.Errorf("error %q does not name the required prefix", detail)
+	}
+}
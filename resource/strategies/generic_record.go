@@ -1,10 +1,12 @@
 package strategies
 
 import (
-	"encoding/json"
+	"bytes"
 	"fmt"
 	"log"
+	"net"
 	"sort"
+	"strings"
 	"terraform-provider-regru/resource/base"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -50,6 +52,10 @@ func NewGenericRecordStrategy(recordType string, preprocessor RecordPreprocessor
 
 // Create creates DNS records using the generic pattern
 func (s *GenericRecordStrategy) Create(meta interface{}, d *schema.ResourceData) error {
+	if err := base.ValidateResourceRecords(s.recordType, d); err != nil {
+		return err
+	}
+
 	c := meta.(base.CachedClientInterface)
 	zone := s.GetZone(d)
 	name := s.GetName(d)
@@ -63,21 +69,29 @@ func (s *GenericRecordStrategy) Create(meta interface{}, d *schema.ResourceData)
 		recordStr := record.(string)
 		recordStrings[i] = s.preprocessor(recordStr)
 	}
-	sort.Strings(recordStrings)
-	log.Printf("[DEBUG] Sorted %s records for creation: %v", s.recordType, recordStrings)
+	if !s.isOrdered(d) {
+		s.sortRecords(recordStrings)
+	}
+	log.Printf("[DEBUG] Ordered %s records for creation: %v", s.recordType, recordStrings)
 
 	s.LogResourceOperation("Creating", s.recordType, zone, name)
 
-	// Validate records
-	if err := s.validator(records); err != nil {
-		return err
+	// Validate records; an allowed empty list creates the resource without records
+	if len(records) > 0 || !s.allowEmpty(d) {
+		if err := s.validator(records); err != nil {
+			return err
+		}
 	}
 
 	// Add each record
 	for _, recordStr := range recordStrings {
 		log.Printf("[DEBUG] Adding %s record: %s.%s -> %s", s.recordType, name, zone, recordStr)
-		response, err := c.AddRecord(s.recordType, zone, name, recordStr, nil)
+		response, err := base.AddRecordWithTTL(c, s.recordType, zone, name, s.apiContent(recordStr), nil, s.ttl(d))
 		if err != nil {
+			if s.ReconcileAfterTimeout(c, err, zone, name, s.recordType, s.apiContent(recordStr)) {
+				base.MarkRecordWritten(meta, zone, name, s.recordType, recordStr)
+				continue
+			}
 			return fmt.Errorf("failed to create %s record %s: %w", s.recordType, recordStr, err)
 		}
 
@@ -85,12 +99,13 @@ func (s *GenericRecordStrategy) Create(meta interface{}, d *schema.ResourceData)
 		if err := base.CheckAPIResponseForErrors(response); err != nil {
 			return fmt.Errorf("failed to create %s record %s: %w", s.recordType, recordStr, err)
 		}
+		base.MarkRecordWritten(meta, zone, name, s.recordType, recordStr)
 	}
 
 	// Set resource ID
 	s.SetResourceID(d, zone, name, s.recordType)
 
-	return s.Read(meta, d)
+	return s.ReadAfterWrite(c, d, zone, func() error { return s.Read(meta, d) })
 }
 
 // Read reads DNS records using the generic pattern
@@ -101,47 +116,47 @@ func (s *GenericRecordStrategy) Read(meta interface{}, d *schema.ResourceData) e
 
 	s.LogResourceOperation("Reading", s.recordType, zone, name)
 
-	// Get zone data from API (with caching)
-	response, err := c.GetRecordsWithCache(zone)
+	// Get the records of our type from the cached zone
+	records, err := c.GetRecordsByType(zone, name, s.recordType)
 	if err != nil {
 		return fmt.Errorf("failed to get zone records: %w", err)
 	}
 
-	log.Printf("[DEBUG] API Response: %s", string(response))
-
-	// Parse the response
-	var zoneResponse base.DNSZoneResponse
-	if err := json.Unmarshal(response, &zoneResponse); err != nil {
-		return fmt.Errorf("failed to parse DNS records response: %w", err)
+	if len(records) == 0 {
+		// Only a fresh read can confirm the records are gone
+		if records, err = s.ConfirmAbsent(c, zone, name, s.recordType); err != nil {
+			return err
+		}
 	}
 
-	log.Printf("[DEBUG] Parsed response - domains: %d", len(zoneResponse.Answer.Domains))
-
-	// Find records of our type
 	var foundRecords []string
-	for _, domain := range zoneResponse.Answer.Domains {
-		log.Printf("[DEBUG] Processing domain: %s, records: %d", domain.Dname, len(domain.Rrs))
-		for _, record := range domain.Rrs {
-			log.Printf("[DEBUG] Record: type=%s, subname=%s, content=%s",
-				record.Rectype, record.Subname, record.Content)
-
-			if record.Rectype == s.recordType && record.Subname == name {
-				// Apply preprocessing to normalize the content
-				normalizedContent := s.preprocessor(record.Content)
-				foundRecords = append(foundRecords, normalizedContent)
-			}
-		}
+	for _, record := range records {
+		log.Printf("[DEBUG] Record: type=%s, subname=%s, content=%s",
+			record.Rectype, record.Subname, record.Content)
+
+		// Apply preprocessing to normalize the content
+		foundRecords = append(foundRecords, s.preprocessor(record.Content))
 	}
 
 	if len(foundRecords) == 0 {
 		log.Printf("[DEBUG] No %s records found for %s.%s", s.recordType, name, zone)
+		if s.allowEmpty(d) {
+			// The resource outlives its records and is repopulated by the next apply
+			d.Set("zone", zone)
+			d.Set("name", name)
+			d.Set("records", []interface{}{})
+			return nil
+		}
 		// No records found, mark as deleted
 		d.SetId("")
 		return nil
 	}
 
 	// Sort records for consistent state
-	sort.Strings(foundRecords)
+	s.sortRecords(foundRecords)
+	if s.isOrdered(d) {
+		foundRecords = s.OrderRecordsByConfiguration(foundRecords, s.GetRecords(d))
+	}
 	log.Printf("[DEBUG] Sorted %s records: %v", s.recordType, foundRecords)
 
 	// Set the data
@@ -161,64 +176,86 @@ func (s *GenericRecordStrategy) Read(meta interface{}, d *schema.ResourceData) e
 
 // Update updates DNS records using the generic pattern
 func (s *GenericRecordStrategy) Update(meta interface{}, d *schema.ResourceData) error {
+	if err := base.ValidateResourceRecords(s.recordType, d); err != nil {
+		return err
+	}
+
 	c := meta.(base.CachedClientInterface)
 	zone := s.GetZone(d)
 	name := s.GetName(d)
 
 	s.LogResourceOperation("Updating", s.recordType, zone, name)
 
-	if d.HasChange("records") {
+	if d.HasChange("records") || d.HasChange("ttl") {
 		old, new := d.GetChange("records")
 		oldRecords := old.([]interface{})
 		newRecords := new.([]interface{})
 
+		if len(newRecords) > 0 || !s.allowEmpty(d) {
+			if err := s.validator(newRecords); err != nil {
+				return err
+			}
+		}
+
 		// Apply preprocessing and sort both sets
 		oldRecordsStr := make([]string, len(oldRecords))
 		for i, record := range oldRecords {
 			oldRecordsStr[i] = s.preprocessor(record.(string))
 		}
-		sort.Strings(oldRecordsStr)
 
 		newRecordsStr := make([]string, len(newRecords))
 		for i, record := range newRecords {
 			newRecordsStr[i] = s.preprocessor(record.(string))
 		}
-		sort.Strings(newRecordsStr)
 
-		// Find records to remove
-		recordsToRemove := []string{}
-		for _, oldRecord := range oldRecordsStr {
-			found := false
-			for _, newRecord := range newRecordsStr {
-				if oldRecord == newRecord {
-					found = true
-					break
-				}
-			}
-			if !found {
-				recordsToRemove = append(recordsToRemove, oldRecord)
-			}
+		// A changed order or TTL can only be applied by re-creating the records
+		reorder := s.isOrdered(d) && !equalStrings(oldRecordsStr, newRecordsStr)
+		retime := d.HasChange("ttl") && s.ttl(d) != nil
+		if !s.isOrdered(d) {
+			sort.Strings(oldRecordsStr)
+			sort.Strings(newRecordsStr)
 		}
 
-		// Find records to add
+		recordsToRemove := []string{}
 		recordsToAdd := []string{}
-		for _, newRecord := range newRecordsStr {
-			found := false
+		if reorder || retime {
+			log.Printf("[DEBUG] %s record order or TTL changed, re-creating records", s.recordType)
+			recordsToRemove = append(recordsToRemove, oldRecordsStr...)
+			recordsToAdd = append(recordsToAdd, newRecordsStr...)
+		} else {
+			// Find records to remove
 			for _, oldRecord := range oldRecordsStr {
-				if newRecord == oldRecord {
-					found = true
-					break
+				found := false
+				for _, newRecord := range newRecordsStr {
+					if oldRecord == newRecord {
+						found = true
+						break
+					}
+				}
+				if !found {
+					recordsToRemove = append(recordsToRemove, oldRecord)
 				}
 			}
-			if !found {
-				recordsToAdd = append(recordsToAdd, newRecord)
+
+			// Find records to add
+			for _, newRecord := range newRecordsStr {
+				found := false
+				for _, oldRecord := range oldRecordsStr {
+					if newRecord == oldRecord {
+						found = true
+						break
+					}
+				}
+				if !found {
+					recordsToAdd = append(recordsToAdd, newRecord)
+				}
 			}
 		}
 
 		// Remove old records
 		for _, record := range recordsToRemove {
 			log.Printf("[DEBUG] Removing %s record: %s -> %s", s.recordType, name, record)
-			response, err := c.RemoveRecord(zone, name, s.recordType, record, nil)
+			response, err := c.RemoveRecord(zone, name, s.recordType, s.StoredContent(c, zone, name, s.recordType, s.apiContent(record)), nil)
 			if err != nil {
 				return fmt.Errorf("failed to remove %s record %s: %w", s.recordType, record, err)
 			}
@@ -231,21 +268,26 @@ func (s *GenericRecordStrategy) Update(meta interface{}, d *schema.ResourceData)
 		// Add new records
 		for _, record := range recordsToAdd {
 			log.Printf("[DEBUG] Adding %s record: %s -> %s", s.recordType, name, record)
-			response, err := c.AddRecord(s.recordType, zone, name, record, nil)
+			response, err := base.AddRecordWithTTL(c, s.recordType, zone, name, s.apiContent(record), nil, s.ttl(d))
 			if err != nil {
+				if s.ReconcileAfterTimeout(c, err, zone, name, s.recordType, s.apiContent(record)) {
+					base.MarkRecordWritten(meta, zone, name, s.recordType, record)
+					continue
+				}
 				return fmt.Errorf("failed to add %s record %s: %w", s.recordType, record, err)
 			}
 
 			if err := base.CheckAPIResponseForErrors(response); err != nil {
 				return fmt.Errorf("failed to add %s record %s: %w", s.recordType, record, err)
 			}
+			base.MarkRecordWritten(meta, zone, name, s.recordType, record)
 		}
 
 		// Invalidate cache after updates
 		c.InvalidateZoneCache(zone)
 	}
 
-	return s.Read(meta, d)
+	return s.ReadAfterWrite(c, d, zone, func() error { return s.Read(meta, d) })
 }
 
 // Delete deletes DNS records using the generic pattern
@@ -260,13 +302,13 @@ func (s *GenericRecordStrategy) Delete(meta interface{}, d *schema.ResourceData)
 	// Remove each record
 	for _, record := range records {
 		recordStr := s.preprocessor(record.(string))
-		log.Printf("[DEBUG] Removing %s record: %s -> %s", s.recordType, name, recordStr)
-		response, err := c.RemoveRecord(zone, name, s.recordType, recordStr, nil)
-		if err != nil {
-			return fmt.Errorf("failed to delete %s record %s: %w", s.recordType, recordStr, err)
+		if base.OwnedByReplacement(meta, zone, name, s.recordType, recordStr) {
+			log.Printf("[DEBUG] Keeping %s record %s.%s -> %s, it was added by its replacement", s.recordType, name, zone, recordStr)
+			continue
 		}
-
-		if err := base.CheckAPIResponseForErrors(response); err != nil {
+		log.Printf("[DEBUG] Removing %s record: %s -> %s", s.recordType, name, recordStr)
+		response, err := c.RemoveRecord(zone, name, s.recordType, s.StoredContent(c, zone, name, s.recordType, s.apiContent(recordStr)), nil)
+		if err := base.CheckRemoveResponse(response, err); err != nil {
 			return fmt.Errorf("failed to delete %s record %s: %w", s.recordType, recordStr, err)
 		}
 	}
@@ -279,7 +321,7 @@ func (s *GenericRecordStrategy) Delete(meta interface{}, d *schema.ResourceData)
 
 // Import imports an existing DNS record using the generic pattern
 func (s *GenericRecordStrategy) Import(meta interface{}, d *schema.ResourceData) error {
-	zone, name, err := s.ParseResourceID(d.Id())
+	zone, name, err := s.ParseImportID(meta, d.Id())
 	if err != nil {
 		return err
 	}
@@ -290,6 +332,74 @@ func (s *GenericRecordStrategy) Import(meta interface{}, d *schema.ResourceData)
 	return s.Read(meta, d)
 }
 
+// isOrdered reports whether the resource preserves the configured record order
+func (s *GenericRecordStrategy) isOrdered(d *schema.ResourceData) bool {
+	ordered, ok := d.Get("ordered").(bool)
+	return ok && ordered
+}
+
+// sortRecords sorts record values for consistent state. Addresses of A and AAAA records are
+// sorted by their numeric value, so that 9.0.0.1 comes before 10.0.0.2; other values and
+// values that don't parse as addresses are sorted lexically, after the addresses.
+func (s *GenericRecordStrategy) sortRecords(values []string) {
+	if s.recordType != "A" && s.recordType != "AAAA" {
+		sort.Strings(values)
+		return
+	}
+
+	sort.SliceStable(values, func(i, j int) bool {
+		a, b := net.ParseIP(values[i]), net.ParseIP(values[j])
+		switch {
+		case a != nil && b != nil:
+			if order := bytes.Compare(a.To16(), b.To16()); order != 0 {
+				return order < 0
+			}
+			return values[i] < values[j]
+		case a != nil || b != nil:
+			return a != nil
+		default:
+			return values[i] < values[j]
+		}
+	})
+}
+
+// allowEmpty reports whether the resource may exist without any records
+func (s *GenericRecordStrategy) allowEmpty(d *schema.ResourceData) bool {
+	allowed, ok := d.Get("allow_empty").(bool)
+	return ok && allowed
+}
+
+// apiContent returns the form a record value is sent to the API in. TXT values longer than a
+// single character-string are split into quoted chunks, which the TXT preprocessor joins on read.
+func (s *GenericRecordStrategy) apiContent(value string) string {
+	if s.recordType == "TXT" {
+		return base.ChunkTXTValue(value)
+	}
+	return value
+}
+
+// ttl returns the TTL to add records with, or nil to use the zone default
+func (s *GenericRecordStrategy) ttl(d *schema.ResourceData) *int {
+	if ttl, ok := d.GetOk("ttl"); ok && ttl.(int) > 0 {
+		value := ttl.(int)
+		return &value
+	}
+	return nil
+}
+
+// equalStrings reports whether two string slices hold the same values in the same order
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // Helper functions to create common preprocessors and validators
 
 // NoOpPreprocessor returns the input unchanged
@@ -297,6 +407,16 @@ func NoOpPreprocessor(input string) string {
 	return input
 }
 
+// TXTPreprocessor reassembles a TXT value stored as quoted chunks into the single string it
+// was configured as. Values that fit into one character-string are never chunked and are
+// returned unchanged, quotes included.
+func TXTPreprocessor(input string) string {
+	if len(input) <= base.MaxTXTStringLength {
+		return input
+	}
+	return base.JoinTXTChunks(input)
+}
+
 // NormalizeDomainPreprocessor removes trailing dots from domains
 func NormalizeDomainPreprocessor(input string) string {
 	ops := &base.CommonOperations{}
@@ -306,7 +426,7 @@ func NormalizeDomainPreprocessor(input string) string {
 // AddTrailingDotPreprocessor adds trailing dots to domains
 func AddTrailingDotPreprocessor(input string) string {
 	ops := &base.CommonOperations{}
-	return ops.AddTrailingDot(input)
+	return ops.AddTrailingDot(nil, input)
 }
 
 // DefaultRecordValidator validates that at least one record is provided
@@ -318,3 +438,26 @@ func DefaultRecordValidator(recordType string) RecordValidator {
 		return nil
 	}
 }
+
+// IPRecordValidator returns a validator checking that every value is an address of the
+// record's family: IPv4 for A records and IPv6 for AAAA records
+func IPRecordValidator(recordType string) RecordValidator {
+	return func(records []interface{}) error {
+		if err := DefaultRecordValidator(recordType)(records); err != nil {
+			return err
+		}
+
+		var errs []string
+		for i, record := range records {
+			value, _ := record.(string)
+			if err := base.ValidateRecordSpec(recordType, base.Record{Value: value}); err != nil {
+				errs = append(errs, fmt.Sprintf("records[%d]: %v", i, err))
+			}
+		}
+
+		if len(errs) > 0 {
+			return fmt.Errorf("invalid %s records:\n%s", recordType, strings.Join(errs, "\n"))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,568 @@
+package strategies_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"terraform-provider-regru/internal/fakeclient"
+	"terraform-provider-regru/resource/base"
+	"terraform-provider-regru/resource/resources"
+	"terraform-provider-regru/resource/strategies"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestARecordLifecycle(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	strategy := strategies.NewARecordStrategy()
+	resource := resources.ResourceDNSARecord()
+
+	d := newData(t, resource, map[string]interface{}{
+		"zone":    "example.com",
+		"name":    "www",
+		"records": []interface{}{"192.0.2.2", "192.0.2.1"},
+	})
+	if err := strategy.Create(fake, d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if d.Id() != "example.com/www" {
+		t.Errorf("ID = %q, want example.com/www", d.Id())
+	}
+	expectStrings(t, "zone after create", zoneContents(fake, "example.com", "www", "A"), []string{"192.0.2.1", "192.0.2.2"})
+	expectStrings(t, "records after create", stringList(d.Get("records")), []string{"192.0.2.1", "192.0.2.2"})
+
+	// A record added outside Terraform shows up on refresh
+	fake.SetRecords("example.com", append(fake.Records("example.com"), base.DNSRecord{Subname: "www", Rectype: "A", Content: "192.0.2.3"}))
+	if err := strategy.Read(fake, d); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	expectStrings(t, "records after refresh", stringList(d.Get("records")), []string{"192.0.2.1", "192.0.2.2", "192.0.2.3"})
+
+	// Only the changed addresses are written
+	fake.Calls = nil
+	d = changedData(t, resource, d.State(), map[string]interface{}{
+		"zone":    "example.com",
+		"name":    "www",
+		"records": []interface{}{"192.0.2.1", "192.0.2.4"},
+	})
+	if err := strategy.Update(fake, d); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	expectStrings(t, "update calls", fake.Calls, []string{
+		"remove A www 192.0.2.2",
+		"remove A www 192.0.2.3",
+		"add A www 192.0.2.4",
+	})
+	expectStrings(t, "zone after update", zoneContents(fake, "example.com", "www", "A"), []string{"192.0.2.1", "192.0.2.4"})
+
+	// Records written in a run are kept by deletes in the same run, as they belong to a replacement
+	fake.NewRun()
+	if err := strategy.Delete(fake, d); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	expectStrings(t, "zone after delete", zoneContents(fake, "example.com", "www", "A"), []string{})
+}
+
+func TestARecordTTL(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	strategy := strategies.NewARecordStrategy()
+	resource := resources.ResourceDNSARecord()
+
+	ttls := func() []string {
+		values := []string{}
+		for _, rr := range fake.Records("example.com") {
+			values = append(values, fmt.Sprintf("%s %d", rr.Content, rr.TTL))
+		}
+		sort.Strings(values)
+		return values
+	}
+
+	d := newData(t, resource, map[string]interface{}{
+		"zone":    "example.com",
+		"name":    "www",
+		"records": []interface{}{"192.0.2.1", "192.0.2.2"},
+		"ttl":     600,
+	})
+	if err := strategy.Create(fake, d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	expectStrings(t, "TTLs after create", ttls(), []string{"192.0.2.1 600", "192.0.2.2 600"})
+
+	// A changed TTL re-creates the unchanged records with it
+	d = changedData(t, resource, d.State(), map[string]interface{}{
+		"zone":    "example.com",
+		"name":    "www",
+		"records": []interface{}{"192.0.2.1", "192.0.2.2"},
+		"ttl":     3600,
+	})
+	if err := strategy.Update(fake, d); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	expectStrings(t, "TTLs after update", ttls(), []string{"192.0.2.1 3600", "192.0.2.2 3600"})
+}
+
+func TestARecordAllowEmpty(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	strategy := strategies.NewARecordStrategy()
+	resource := resources.ResourceDNSARecord()
+
+	d := newData(t, resource, map[string]interface{}{
+		"zone":        "example.com",
+		"name":        "www",
+		"records":     []interface{}{"192.0.2.1", "192.0.2.2"},
+		"allow_empty": true,
+	})
+	if err := strategy.Create(fake, d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// An empty list removes every record but keeps the resource
+	d = changedData(t, resource, d.State(), map[string]interface{}{
+		"zone":        "example.com",
+		"name":        "www",
+		"records":     []interface{}{},
+		"allow_empty": true,
+	})
+	if err := strategy.Update(fake, d); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	expectStrings(t, "zone after update", zoneContents(fake, "example.com", "www", "A"), []string{})
+	if err := strategy.Read(fake, d); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if d.Id() != "example.com/www" {
+		t.Errorf("ID = %q after reading an empty record set, want it kept", d.Id())
+	}
+	expectStrings(t, "records after refresh", stringList(d.Get("records")), []string{})
+
+	// The resource can be repopulated later
+	d = changedData(t, resource, d.State(), map[string]interface{}{
+		"zone":        "example.com",
+		"name":        "www",
+		"records":     []interface{}{"192.0.2.3"},
+		"allow_empty": true,
+	})
+	if err := strategy.Update(fake, d); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	expectStrings(t, "zone after repopulating", zoneContents(fake, "example.com", "www", "A"), []string{"192.0.2.3"})
+}
+
+func TestARecordsSortedByAddress(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	strategy := strategies.NewARecordStrategy()
+
+	d := newData(t, resources.ResourceDNSARecord(), map[string]interface{}{
+		"zone":    "example.com",
+		"name":    "www",
+		"records": []interface{}{"10.0.0.2", "192.0.2.1", "9.0.0.1"},
+	})
+	if err := strategy.Create(fake, d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	// Addresses sort by value, where a lexical sort would put 10.0.0.2 first
+	expectStrings(t, "records after create", stringList(d.Get("records")), []string{"9.0.0.1", "10.0.0.2", "192.0.2.1"})
+
+	if err := strategy.Read(fake, d); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	expectStrings(t, "records after refresh", stringList(d.Get("records")), []string{"9.0.0.1", "10.0.0.2", "192.0.2.1"})
+}
+
+func TestARecordRejectsIPv6Values(t *testing.T) {
+	fake := fakeclient.New("example.com")
+
+	d := newData(t, resources.ResourceDNSARecord(), map[string]interface{}{
+		"zone":    "example.com",
+		"name":    "www",
+		"records": []interface{}{"192.0.2.1", "2001:db8::1"},
+	})
+	err := strategies.NewARecordStrategy().Create(fake, d)
+	if err == nil {
+		t.Fatal("Create succeeded with an IPv6 address in an A record")
+	}
+	// The error names the offending value and points at the AAAA resource
+	for _, want := range []string{"2001:db8::1", "regru_dns_aaaa_record"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+	expectStrings(t, "calls", fake.Calls, nil)
+}
+
+func TestARecordReadRemovesDeletedRecords(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	strategy := strategies.NewARecordStrategy()
+
+	d := importData(resources.ResourceDNSARecord(), "example.com/www")
+	d.Set("zone", "example.com")
+	d.Set("name", "www")
+	if err := strategy.Read(fake, d); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if d.Id() != "" {
+		t.Errorf("ID = %q after reading a name without records, want it cleared", d.Id())
+	}
+}
+
+func TestReadMissingZone(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		strategy base.RecordTypeStrategy
+		resource func() *schema.Resource
+	}{
+		{"A", strategies.NewARecordStrategy(), resources.ResourceDNSARecord},
+		{"MX", strategies.NewMXRecordStrategy(), resources.ResourceDNSMXRecord},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// The API answers with no entry for a zone that isn't in the account
+			fake := fakeclient.New("example.com")
+
+			d := importData(tc.resource(), "missing.example/www")
+			d.Set("zone", "missing.example")
+			d.Set("name", "www")
+			err := tc.strategy.Read(fake, d)
+			if !errors.Is(err, base.ErrZoneNotFound) {
+				t.Fatalf("Read error = %v, want ErrZoneNotFound", err)
+			}
+			if d.Id() != "missing.example/www" {
+				t.Errorf("ID = %q after reading a missing zone, want it kept", d.Id())
+			}
+		})
+	}
+}
+
+func TestARecordReadKeepsRecordsSeenOnConfirmation(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	fake.SetRecords("example.com", []base.DNSRecord{{Subname: "www", Rectype: "A", Content: "192.0.2.1"}})
+
+	// The first read misses the record, the fresh read made to confirm the absence finds it
+	d := importData(resources.ResourceDNSARecord(), "example.com/www")
+	d.Set("zone", "example.com")
+	d.Set("name", "www")
+	if err := strategies.NewARecordStrategy().Read(&laggingClient{Client: fake, hidden: 1}, d); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if d.Id() != "example.com/www" {
+		t.Errorf("ID = %q, want example.com/www", d.Id())
+	}
+	expectStrings(t, "records", stringList(d.Get("records")), []string{"192.0.2.1"})
+}
+
+func TestARecordReadInconclusive(t *testing.T) {
+	d := importData(resources.ResourceDNSARecord(), "example.com/www")
+	d.Set("zone", "example.com")
+	d.Set("name", "www")
+	if err := strategies.NewARecordStrategy().Read(failingReadClient{fakeclient.New("example.com")}, d); err == nil {
+		t.Fatal("Read succeeded although the zone could not be read")
+	}
+	if d.Id() != "example.com/www" {
+		t.Errorf("ID = %q after a failed read, want it kept", d.Id())
+	}
+}
+
+func TestARecordImport(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	fake.SetRecords("example.com", []base.DNSRecord{
+		{Subname: "www", Rectype: "A", Content: "192.0.2.10"},
+		{Subname: "www", Rectype: "A", Content: "192.0.2.9"},
+		{Subname: "www", Rectype: "AAAA", Content: "2001:db8::1"},
+	})
+
+	d := importData(resources.ResourceDNSARecord(), "example.com/www")
+	if err := strategies.NewARecordStrategy().Import(fake, d); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if zone, name := d.Get("zone"), d.Get("name"); zone != "example.com" || name != "www" {
+		t.Errorf("zone, name = %v, %v, want example.com, www", zone, name)
+	}
+	// Addresses are sorted numerically, other record types are ignored
+	expectStrings(t, "imported records", stringList(d.Get("records")), []string{"192.0.2.9", "192.0.2.10"})
+}
+
+func TestARecordImportByFQDN(t *testing.T) {
+	fake := fakeclient.New("example.com", "example.co.uk", "sub.example.com")
+	for _, zone := range []string{"example.com", "example.co.uk", "sub.example.com"} {
+		fake.SetRecords(zone, []base.DNSRecord{
+			{Subname: "@", Rectype: "A", Content: "192.0.2.1"},
+			{Subname: "www", Rectype: "A", Content: "192.0.2.2"},
+		})
+	}
+
+	for _, tc := range []struct {
+		id, zone, name string
+	}{
+		{"www.example.com", "example.com", "www"},
+		{"example.com.", "example.com", "@"},
+		{"www.example.co.uk", "example.co.uk", "www"},
+		{"example.co.uk", "example.co.uk", "@"},
+		// A delegated subzone in the account takes precedence over its parent
+		{"www.sub.example.com", "sub.example.com", "www"},
+		{"WWW.Example.COM", "example.com", "www"},
+	} {
+		d := importData(resources.ResourceDNSARecord(), tc.id)
+		if err := strategies.NewARecordStrategy().Import(fake, d); err != nil {
+			t.Errorf("Import %s: %v", tc.id, err)
+			continue
+		}
+		if zone, name := d.Get("zone"), d.Get("name"); zone != tc.zone || name != tc.name {
+			t.Errorf("Import %s: zone, name = %v, %v, want %s, %s", tc.id, zone, name, tc.zone, tc.name)
+		}
+	}
+
+	d := importData(resources.ResourceDNSARecord(), "www.example.org")
+	if err := strategies.NewARecordStrategy().Import(fake, d); err == nil || !strings.Contains(err.Error(), "zone/name") {
+		t.Errorf("Import of an FQDN outside the account: err = %v, want a hint at the zone/name format", err)
+	}
+}
+
+// timeoutClient is a fake client whose add requests time out waiting for the response. The
+// record is added first unless lost is set, as when the request never reached the API.
+type timeoutClient struct {
+	*fakeclient.Client
+	lost bool
+}
+
+func (c timeoutClient) AddRecord(recordType, domainName, subdomain, value string, priority *int) ([]byte, error) {
+	if !c.lost {
+		c.Client.AddRecord(recordType, domainName, subdomain, value, priority)
+	}
+	return nil, errAddTimeout
+}
+
+func (c timeoutClient) AddSRVRecord(domainName, subdomain, target string, priority, weight, port *int) ([]byte, error) {
+	if !c.lost {
+		c.Client.AddSRVRecord(domainName, subdomain, target, priority, weight, port)
+	}
+	return nil, errAddTimeout
+}
+
+func (c timeoutClient) AddCAARecord(domainName, subdomain, value string, flag *int, tag *string) ([]byte, error) {
+	if !c.lost {
+		c.Client.AddCAARecord(domainName, subdomain, value, flag, tag)
+	}
+	return nil, errAddTimeout
+}
+
+func (c timeoutClient) AddSSHFPRecord(domainName, subdomain, fingerprint string, algorithm, fpType *int) ([]byte, error) {
+	if !c.lost {
+		c.Client.AddSSHFPRecord(domainName, subdomain, fingerprint, algorithm, fpType)
+	}
+	return nil, errAddTimeout
+}
+
+func (c timeoutClient) AddTLSARecord(domainName, subdomain, certificate string, usage, selector, matchingType *int) ([]byte, error) {
+	if !c.lost {
+		c.Client.AddTLSARecord(domainName, subdomain, certificate, usage, selector, matchingType)
+	}
+	return nil, errAddTimeout
+}
+
+// errAddTimeout is the error of an add request of timeoutClient
+var errAddTimeout = fmt.Errorf("failed to make request: %w", context.DeadlineExceeded)
+
+func TestARecordCreateAfterTimeout(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	d := newData(t, resources.ResourceDNSARecord(), map[string]interface{}{
+		"zone":    "example.com",
+		"name":    "www",
+		"records": []interface{}{"192.0.2.1"},
+	})
+
+	// The record turns out to be present, so the add is neither failed nor repeated
+	if err := strategies.NewARecordStrategy().Create(timeoutClient{Client: fake}, d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	expectStrings(t, "add calls", fake.Calls, []string{"add A www 192.0.2.1"})
+	expectStrings(t, "zone after create", zoneContents(fake, "example.com", "www", "A"), []string{"192.0.2.1"})
+}
+
+func TestARecordCreateAfterLostRequest(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	d := newData(t, resources.ResourceDNSARecord(), map[string]interface{}{
+		"zone":    "example.com",
+		"name":    "www",
+		"records": []interface{}{"192.0.2.1"},
+	})
+
+	if err := strategies.NewARecordStrategy().Create(timeoutClient{Client: fake, lost: true}, d); err == nil {
+		t.Fatal("Create succeeded although the record was never added")
+	}
+}
+
+// laggingClient is a fake client whose zone reads omit every record until hidden reads were made,
+// as an eventually consistent API does right after a write
+type laggingClient struct {
+	*fakeclient.Client
+	hidden int
+}
+
+func (c *laggingClient) GetRecordsByType(zone, name, recordType string) ([]base.DNSRecord, error) {
+	if c.hidden > 0 {
+		c.hidden--
+		return nil, nil
+	}
+	return c.Client.GetRecordsByType(zone, name, recordType)
+}
+
+func TestARecordCreateWithDelayedVisibility(t *testing.T) {
+	delay := base.ReadAfterWriteDelay
+	base.ReadAfterWriteDelay = time.Millisecond
+	t.Cleanup(func() { base.ReadAfterWriteDelay = delay })
+
+	// The first read after the write and its confirmation miss the record
+	c := &laggingClient{Client: fakeclient.New("example.com"), hidden: 2}
+	d := newData(t, resources.ResourceDNSARecord(), map[string]interface{}{
+		"zone":    "example.com",
+		"name":    "www",
+		"records": []interface{}{"192.0.2.1"},
+	})
+	if err := strategies.NewARecordStrategy().Create(c, d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if d.Id() != "example.com/www" {
+		t.Errorf("ID = %q, want example.com/www", d.Id())
+	}
+	expectStrings(t, "records after create", stringList(d.Get("records")), []string{"192.0.2.1"})
+	expectStrings(t, "add calls", c.Calls, []string{"add A www 192.0.2.1"})
+}
+
+func TestTXTRecordRenameCreatesBeforeDestroying(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	strategy := strategies.NewTXTRecordStrategy()
+	resource := resources.ResourceDNSTXTRecord()
+
+	old := newData(t, resource, map[string]interface{}{
+		"zone":    "example.com",
+		"name":    "verify",
+		"records": []interface{}{"token-1"},
+	})
+	if err := strategy.Create(fake, old); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// With create_before_destroy, a later run creates the renamed record before deleting the old one
+	fake.NewRun()
+	fake.Calls = nil
+	renamed := newData(t, resource, map[string]interface{}{
+		"zone":    "example.com",
+		"name":    "_verify",
+		"records": []interface{}{"token-1"},
+	})
+	if err := strategy.Create(fake, renamed); err != nil {
+		t.Fatalf("Create renamed: %v", err)
+	}
+	expectStrings(t, "zone while both exist", zoneContents(fake, "example.com", "verify", "TXT"), []string{"token-1"})
+
+	if err := strategy.Delete(fake, old); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	expectStrings(t, "calls", fake.Calls, []string{
+		"add TXT _verify token-1",
+		"remove TXT verify token-1",
+	})
+	expectStrings(t, "old name after delete", zoneContents(fake, "example.com", "verify", "TXT"), []string{})
+	expectStrings(t, "new name after delete", zoneContents(fake, "example.com", "_verify", "TXT"), []string{"token-1"})
+
+	// A rename that only changes letter case leaves the value the replacement wrote in place
+	fake.NewRun()
+	recased := newData(t, resource, map[string]interface{}{
+		"zone":    "example.com",
+		"name":    "_VERIFY",
+		"records": []interface{}{"token-1"},
+	})
+	if err := strategy.Create(fake, recased); err != nil {
+		t.Fatalf("Create recased: %v", err)
+	}
+	fake.Calls = nil
+	if err := strategy.Delete(fake, renamed); err != nil {
+		t.Fatalf("Delete renamed: %v", err)
+	}
+	expectStrings(t, "calls deleting the recased record", fake.Calls, nil)
+}
+
+func TestTXTRecordChunking(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	strategy := strategies.NewTXTRecordStrategy()
+
+	short := "v=spf1 " + strings.Repeat("a", 248)
+	long := "v=DKIM1; k=rsa; p=" + strings.Repeat("ключ", 60)
+	d := newData(t, resources.ResourceDNSTXTRecord(), map[string]interface{}{
+		"zone":    "example.com",
+		"name":    "mail",
+		"records": []interface{}{short, long},
+	})
+	if err := strategy.Create(fake, d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// A value of 255 bytes is a single string, a longer one is sent as quoted chunks that
+	// keep multi-byte characters whole
+	stored := zoneContents(fake, "example.com", "mail", "TXT")
+	if len(stored) != 2 || stored[1] != short {
+		t.Fatalf("zone after create = %q, want the 255-byte value unchanged", stored)
+	}
+	if !strings.HasPrefix(stored[0], "\"") || strings.Count(stored[0], "\" \"") != 1 || !utf8.ValidString(stored[0]) {
+		t.Errorf("stored long value = %q, want two quoted strings", stored[0])
+	}
+
+	// Refresh reassembles the chunks into the configured value
+	if err := strategy.Read(fake, d); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	records := stringList(d.Get("records"))
+	if len(records) != 2 || !(records[0] == long || records[1] == long) {
+		t.Errorf("records after refresh = %q, want the long value joined", records)
+	}
+}
+
+func TestARecordOrdered(t *testing.T) {
+	resource := resources.ResourceDNSARecord()
+	strategy := strategies.NewARecordStrategy()
+	config := func(ordered bool, records ...interface{}) map[string]interface{} {
+		return map[string]interface{}{"zone": "example.com", "name": "www", "ordered": ordered, "records": records}
+	}
+
+	for _, ordered := range []bool{false, true} {
+		t.Run(fmt.Sprintf("ordered=%t", ordered), func(t *testing.T) {
+			fake := fakeclient.New("example.com")
+			d := newData(t, resource, config(ordered, "192.0.2.2", "192.0.2.1"))
+			if err := strategy.Create(fake, d); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+
+			created := []string{"192.0.2.1", "192.0.2.2"}
+			if ordered {
+				created = []string{"192.0.2.2", "192.0.2.1"}
+			}
+			expectStrings(t, "records after create", stringList(d.Get("records")), created)
+			expectStrings(t, "add calls", fake.Calls, []string{"add A www " + created[0], "add A www " + created[1]})
+
+			// Reordering the configuration is a change only when the order is preserved
+			fake.Calls = nil
+			d = changedData(t, resource, d.State(), config(ordered, "192.0.2.1", "192.0.2.2"))
+			if d.HasChange("records") != ordered {
+				t.Fatalf("reordered records planned as a change: %t, want %t", d.HasChange("records"), ordered)
+			}
+			if !ordered {
+				return
+			}
+			if err := strategy.Update(fake, d); err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+			expectStrings(t, "update calls", fake.Calls, []string{
+				"remove A www 192.0.2.2",
+				"remove A www 192.0.2.1",
+				"add A www 192.0.2.1",
+				"add A www 192.0.2.2",
+			})
+			expectStrings(t, "records after update", stringList(d.Get("records")), []string{"192.0.2.1", "192.0.2.2"})
+		})
+	}
+}
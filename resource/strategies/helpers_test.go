@@ -0,0 +1,90 @@
+package strategies_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"terraform-provider-regru/internal/fakeclient"
+	"terraform-provider-regru/resource/base"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// newData returns the data of a resource being created with the given configuration
+func newData(t *testing.T, r *schema.Resource, raw map[string]interface{}) *schema.ResourceData {
+	t.Helper()
+	return schema.TestResourceDataRaw(t, r.Schema, raw)
+}
+
+// changedData returns the data of a resource in state being updated to the given configuration
+func changedData(t *testing.T, r *schema.Resource, state *terraform.InstanceState, raw map[string]interface{}) *schema.ResourceData {
+	t.Helper()
+
+	diff, err := schema.InternalMap(r.Schema).Diff(context.Background(), state, terraform.NewResourceConfigRaw(raw), nil, nil, true)
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	d, err := schema.InternalMap(r.Schema).Data(state, diff)
+	if err != nil {
+		t.Fatalf("data: %v", err)
+	}
+	return d
+}
+
+// importData returns the data of a resource being imported with the given ID
+func importData(r *schema.Resource, id string) *schema.ResourceData {
+	d := r.TestResourceData()
+	d.SetId(id)
+	return d
+}
+
+// failingReadClient is a fake client whose records-by-type reads fail, as when the API is
+// briefly unreachable
+type failingReadClient struct {
+	*fakeclient.Client
+}
+
+func (c failingReadClient) GetRecordsByType(zone, name, recordType string) ([]base.DNSRecord, error) {
+	return nil, errors.New("connection reset by peer")
+}
+
+// zoneContents returns the records of a type the fake client holds at a name, sorted. Records
+// with a priority are prefixed with it, e.g. "10 mx1.example.com.".
+func zoneContents(f *fakeclient.Client, zone, name, recordType string) []string {
+	contents := []string{}
+	for _, rr := range f.Records(zone) {
+		if rr.Rectype != recordType || !base.MatchesSubname(rr.Subname, name) {
+			continue
+		}
+		if rr.Prio != 0 {
+			contents = append(contents, fmt.Sprintf("%d %s", rr.Prio, rr.Content))
+		} else {
+			contents = append(contents, rr.Content)
+		}
+	}
+	sort.Strings(contents)
+	return contents
+}
+
+// stringList returns the values of a list attribute holding strings
+func stringList(value interface{}) []string {
+	list, _ := value.([]interface{})
+	result := []string{}
+	for _, item := range list {
+		result = append(result, item.(string))
+	}
+	return result
+}
+
+// expectStrings fails the test when got differs from want
+func expectStrings(t *testing.T, what string, got, want []string) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s = %q, want %q", what, got, want)
+	}
+}
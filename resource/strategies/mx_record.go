@@ -18,13 +18,11 @@ type MXRecordStrategy struct {
 
 // GetRecords returns the MX records from the resource data
 func (s *MXRecordStrategy) GetRecords(d *schema.ResourceData) []interface{} {
-	mxRecords := d.Get("record").([]interface{})
+	mxRecords, _ := d.Get("record").([]interface{})
 	var allRecords []interface{}
 
-	for _, mxRecord := range mxRecords {
-		mxRecordMap := mxRecord.(map[string]interface{})
-		servers := mxRecordMap["servers"].([]interface{})
-		allRecords = append(allRecords, servers...)
+	for _, record := range base.RecordsFromSchema("MX", mxRecords) {
+		allRecords = append(allRecords, record.Value)
 	}
 
 	return allRecords
@@ -71,6 +69,10 @@ func (s *MXRecordStrategy) ValidateRecords(records []interface{}) error {
 
 // Create creates MX records
 func (s *MXRecordStrategy) Create(client interface{}, d *schema.ResourceData) error {
+	if err := base.ValidateResourceRecords("MX", d); err != nil {
+		return err
+	}
+
 	// Type assert to get the cached client using shared interface
 	c, ok := client.(base.CachedClientInterface)
 	if !ok {
@@ -88,6 +90,7 @@ func (s *MXRecordStrategy) Create(client interface{}, d *schema.ResourceData) er
 		mxRecordMap := mxRecord.(map[string]interface{})
 		priority := mxRecordMap["priority"].(int)
 		servers := mxRecordMap["servers"].([]interface{})
+		ttl, _ := mxRecordMap["ttl"].(int)
 
 		// Convert to string slice and sort alphabetically for consistent ordering
 		serverStrings := make([]string, len(servers))
@@ -101,9 +104,12 @@ func (s *MXRecordStrategy) Create(client interface{}, d *schema.ResourceData) er
 			log.Printf("[DEBUG] Creating MX record: %s %s %s (priority: %d)", zone, name, serverStr, priority)
 
 			// For MX records, we need to add trailing dots for domain names
-			apiRecord := s.AddTrailingDot(serverStr)
-			response, err := c.AddRecord("MX", zone, name, apiRecord, &priority)
+			apiRecord := s.AddTrailingDot(c, serverStr)
+			response, err := base.AddRecordWithTTL(c, "MX", zone, name, apiRecord, &priority, mxTTL(ttl))
 			if err != nil {
+				if s.ReconcileAfterTimeout(c, err, zone, name, "MX", apiRecord) {
+					continue
+				}
 				return fmt.Errorf("failed to create MX record %s: %w", serverStr, err)
 			}
 
@@ -141,46 +147,47 @@ func (s *MXRecordStrategy) Read(client interface{}, d *schema.ResourceData) erro
 	if err := json.Unmarshal(response, &zoneResponse); err != nil {
 		return fmt.Errorf("failed to parse DNS records response: %w", err)
 	}
+	if err := zoneResponse.CheckZone(zone); err != nil {
+		return err
+	}
+
+	// Keep the configured spelling of servers so refresh doesn't report drift
+	configured := s.GetRecords(d)
+	configuredBlocks, _ := d.Get("record").([]interface{})
+	configuredRecords := base.RecordsFromSchema("MX", configuredBlocks)
 
-	// Group MX records by priority
-	priorityGroups := make(map[int][]string)
+	// Collect MX records for this subdomain
+	var found []base.Record
 	for _, domain := range zoneResponse.Answer.Domains {
-		if domain.Dname == zone {
+		if base.MatchesZone(domain.Dname, zone) {
 			for _, rr := range domain.Rrs {
-				if rr.Subname == name && rr.Rectype == "MX" {
-					// Remove trailing dot from content for consistency
-					content := s.NormalizeDomain(rr.Content)
-					priorityGroups[rr.Prio] = append(priorityGroups[rr.Prio], content)
+				if base.MatchesSubname(rr.Subname, name) && rr.Rectype == "MX" {
+					found = append(found, s.foundRecord(rr, configured, configuredRecords))
 				}
 			}
-			break
 		}
 	}
 
-	if len(priorityGroups) == 0 {
+	if len(found) == 0 {
+		// Only a fresh read can confirm the records are gone
+		records, err := s.ConfirmAbsent(c, zone, name, "MX")
+		if err != nil {
+			return err
+		}
+		for _, rr := range records {
+			found = append(found, s.foundRecord(rr, configured, configuredRecords))
+		}
+	}
+
+	if len(found) == 0 {
 		// No records found, mark as deleted
 		d.SetId("")
 		return nil
 	}
 
-	// Convert to the new mx_records structure
-	var mxRecords []map[string]interface{}
-	for priority, records := range priorityGroups {
-		// Sort records alphabetically for consistent state
-		sort.Strings(records)
-		log.Printf("[DEBUG] MX records with priority %d: %v", priority, records)
-
-		mxRecord := map[string]interface{}{
-			"priority": priority,
-			"servers":  records,
-		}
-		mxRecords = append(mxRecords, mxRecord)
-	}
-
-	// Sort by priority for consistent ordering
-	sort.Slice(mxRecords, func(i, j int) bool {
-		return mxRecords[i]["priority"].(int) < mxRecords[j]["priority"].(int)
-	})
+	// Keep the configured blocks when they match, otherwise group by priority and TTL into sorted blocks
+	mxRecords := base.ConfiguredBlocks("MX", found, configuredBlocks)
+	log.Printf("[DEBUG] MX record blocks: %v", mxRecords)
 
 	// Set the data
 	d.Set("zone", zone)
@@ -190,8 +197,40 @@ func (s *MXRecordStrategy) Read(client interface{}, d *schema.ResourceData) erro
 	return nil
 }
 
+// foundRecord converts an MX record read from the API into its configured form. The TTL is
+// only tracked for servers configured with one, so sets using the zone default don't drift,
+// and the configured TTL is kept when the API doesn't report one.
+func (s *MXRecordStrategy) foundRecord(rr base.DNSRecord, configured []interface{}, configuredRecords []base.Record) base.Record {
+	record := base.Record{
+		Priority: rr.Prio,
+		Value:    s.ConfiguredForm(rr.Content, configured),
+	}
+	for _, configuredRecord := range configuredRecords {
+		if configuredRecord.Priority == record.Priority && configuredRecord.Value == record.Value && configuredRecord.TTL > 0 {
+			record.TTL = configuredRecord.TTL
+			if rr.TTL > 0 {
+				record.TTL = rr.TTL
+			}
+			break
+		}
+	}
+	return record
+}
+
+// mxTTL returns the TTL to add an MX record with, or nil to use the zone default
+func mxTTL(ttl int) *int {
+	if ttl <= 0 {
+		return nil
+	}
+	return &ttl
+}
+
 // Update updates MX records using surgical approach - only change what actually changed
 func (s *MXRecordStrategy) Update(client interface{}, d *schema.ResourceData) error {
+	if err := base.ValidateResourceRecords("MX", d); err != nil {
+		return err
+	}
+
 	// Type assert to get the cached client using shared interface
 	c, ok := client.(base.CachedClientInterface)
 	if !ok {
@@ -226,8 +265,8 @@ func (s *MXRecordStrategy) Update(client interface{}, d *schema.ResourceData) er
 	// Remove records that are no longer needed
 	for _, record := range toRemove {
 		log.Printf("[DEBUG] Removing MX record: %s (priority: %d)", record.Server, record.Priority)
-		apiRecord := s.AddTrailingDot(record.Server)
-		response, err := c.RemoveRecord(zone, name, "MX", apiRecord, &record.Priority)
+		apiRecord := s.AddTrailingDot(c, record.Server)
+		response, err := c.RemoveRecord(zone, name, "MX", s.StoredContent(c, zone, name, "MX", apiRecord), &record.Priority)
 		if err != nil {
 			if err := s.HandleAPIError(err, "remove"); err != nil {
 				return fmt.Errorf("failed to remove MX record %s: %w", record.Server, err)
@@ -244,10 +283,13 @@ func (s *MXRecordStrategy) Update(client interface{}, d *schema.ResourceData) er
 
 	// Add new records
 	for _, record := range toAdd {
-		log.Printf("[DEBUG] Adding MX record: %s (priority: %d)", record.Server, record.Priority)
-		apiRecord := s.AddTrailingDot(record.Server)
-		response, err := c.AddRecord("MX", zone, name, apiRecord, &record.Priority)
+		log.Printf("[DEBUG] Adding MX record: %s (priority: %d, ttl: %d)", record.Server, record.Priority, record.TTL)
+		apiRecord := s.AddTrailingDot(c, record.Server)
+		response, err := base.AddRecordWithTTL(c, "MX", zone, name, apiRecord, &record.Priority, mxTTL(record.TTL))
 		if err != nil {
+			if s.ReconcileAfterTimeout(c, err, zone, name, "MX", apiRecord) {
+				continue
+			}
 			return fmt.Errorf("failed to add MX record %s: %w", record.Server, err)
 		}
 
@@ -277,9 +319,10 @@ func (s *MXRecordStrategy) recreateAllRecords(client interface{}, d *schema.Reso
 	return nil
 }
 
-// MXRecord represents a single MX record for comparison
+// MXRecord represents a single MX record for comparison. A TTL of 0 means the zone default.
 type MXRecord struct {
 	Priority int
+	TTL      int
 	Server   string
 }
 
@@ -287,31 +330,12 @@ type MXRecord struct {
 func (s *MXRecordStrategy) parseRecordsFromState(records []interface{}) []MXRecord {
 	var mxRecords []MXRecord
 
-	for _, recordInterface := range records {
-		recordMap, ok := recordInterface.(map[string]interface{})
-		if !ok {
-			continue
-		}
-
-		priority, priorityOk := recordMap["priority"].(int)
-		if !priorityOk {
-			continue
-		}
-
-		serversInterface, serversOk := recordMap["servers"].([]interface{})
-		if !serversOk {
-			continue
-		}
-
-		// Convert each server in this priority group to individual MXRecord
-		for _, serverInterface := range serversInterface {
-			if server, serverOk := serverInterface.(string); serverOk {
-				mxRecords = append(mxRecords, MXRecord{
-					Priority: priority,
-					Server:   server,
-				})
-			}
-		}
+	for _, record := range base.RecordsFromSchema("MX", records) {
+		mxRecords = append(mxRecords, MXRecord{
+			Priority: record.Priority,
+			TTL:      record.TTL,
+			Server:   record.Value,
+		})
 	}
 
 	return mxRecords
@@ -324,7 +348,7 @@ func (s *MXRecordStrategy) findRecordsToRemove(oldRecords, newRecords []MXRecord
 	for _, oldRecord := range oldRecords {
 		found := false
 		for _, newRecord := range newRecords {
-			if oldRecord.Priority == newRecord.Priority && oldRecord.Server == newRecord.Server {
+			if oldRecord == newRecord {
 				found = true
 				break
 			}
@@ -344,7 +368,7 @@ func (s *MXRecordStrategy) findRecordsToAdd(oldRecords, newRecords []MXRecord) [
 	for _, newRecord := range newRecords {
 		found := false
 		for _, oldRecord := range oldRecords {
-			if newRecord.Priority == oldRecord.Priority && newRecord.Server == oldRecord.Server {
+			if newRecord == oldRecord {
 				found = true
 				break
 			}
@@ -383,27 +407,19 @@ func (s *MXRecordStrategy) Delete(client interface{}, d *schema.ResourceData) er
 
 	// Remove all MX records for this subdomain
 	for _, domain := range zoneResponse.Answer.Domains {
-		if domain.Dname == zone {
+		if base.MatchesZone(domain.Dname, zone) {
 			for _, rr := range domain.Rrs {
-				if rr.Subname == name && rr.Rectype == "MX" {
+				if base.MatchesSubname(rr.Subname, name) && rr.Rectype == "MX" {
 					log.Printf("[DEBUG] Removing MX record: %s (priority: %d)", rr.Content, rr.Prio)
 
 					// For MX records, we need to add trailing dots when removing
-					apiRecord := s.AddTrailingDot(rr.Content)
+					apiRecord := s.AddTrailingDot(c, rr.Content)
 					response, err := c.RemoveRecord(zone, name, "MX", apiRecord, &rr.Prio)
-					if err != nil {
-						if err := s.HandleAPIError(err, "remove"); err != nil {
-							return err
-						}
-					}
-
-					// Check API response for errors
-					if err := base.CheckAPIResponseForErrors(response); err != nil {
+					if err := base.CheckRemoveResponse(response, err); err != nil {
 						return fmt.Errorf("failed to remove MX record %s: %w", rr.Content, err)
 					}
 				}
 			}
-			break
 		}
 	}
 
@@ -415,7 +431,7 @@ func (s *MXRecordStrategy) Delete(client interface{}, d *schema.ResourceData) er
 // Import imports an existing MX record
 func (s *MXRecordStrategy) Import(client interface{}, d *schema.ResourceData) error {
 	// Parse the import ID using the common format
-	zone, name, err := s.ParseResourceID(d.Id())
+	zone, name, err := s.ParseImportID(client, d.Id())
 	if err != nil {
 		return err
 	}
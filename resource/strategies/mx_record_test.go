@@ -0,0 +1,279 @@
+package strategies_test
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"terraform-provider-regru/internal/fakeclient"
+	"terraform-provider-regru/resource/base"
+	"terraform-provider-regru/resource/resources"
+	"terraform-provider-regru/resource/strategies"
+)
+
+func TestMXRecordLifecycle(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	strategy := strategies.NewMXRecordStrategy()
+	resource := resources.ResourceDNSMXRecord()
+
+	d := newData(t, resource, map[string]interface{}{
+		"zone": "example.com",
+		"name": "@",
+		"record": []interface{}{
+			map[string]interface{}{"priority": 10, "servers": []interface{}{"mx2.example.com", "mx1.example.com"}},
+			map[string]interface{}{"priority": 20, "servers": []interface{}{"backup.example.net"}},
+		},
+	})
+	if err := strategy.Create(fake, d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if d.Id() != "example.com/@" {
+		t.Errorf("ID = %q, want example.com/@", d.Id())
+	}
+	// Servers are written with a trailing dot and the priority of their set
+	expectStrings(t, "zone after create", zoneContents(fake, "example.com", "@", "MX"), []string{
+		"10 mx1.example.com.",
+		"10 mx2.example.com.",
+		"20 backup.example.net.",
+	})
+
+	// Refresh keeps the configured spelling of the servers
+	if err := strategy.Read(fake, d); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	blocks := d.Get("record").([]interface{})
+	if len(blocks) != 2 {
+		t.Fatalf("record blocks after refresh = %v, want 2", blocks)
+	}
+	expectStrings(t, "servers after refresh", stringList(blocks[0].(map[string]interface{})["servers"]), []string{"mx2.example.com", "mx1.example.com"})
+
+	// Only the replaced server is written
+	fake.Calls = nil
+	d = changedData(t, resource, d.State(), map[string]interface{}{
+		"zone": "example.com",
+		"name": "@",
+		"record": []interface{}{
+			map[string]interface{}{"priority": 10, "servers": []interface{}{"mx2.example.com", "mx1.example.com"}},
+			map[string]interface{}{"priority": 20, "servers": []interface{}{"backup.example.org"}},
+		},
+	})
+	if err := strategy.Update(fake, d); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	expectStrings(t, "update calls", fake.Calls, []string{
+		"remove MX @ backup.example.net.",
+		"add MX @ backup.example.org.",
+	})
+
+	if err := strategy.Delete(fake, d); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	expectStrings(t, "zone after delete", zoneContents(fake, "example.com", "@", "MX"), []string{})
+}
+
+func TestMXRecordTTL(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	strategy := strategies.NewMXRecordStrategy()
+	resource := resources.ResourceDNSMXRecord()
+
+	ttls := func() []string {
+		values := []string{}
+		for _, rr := range fake.Records("example.com") {
+			values = append(values, fmt.Sprintf("%d %s %d", rr.Prio, rr.Content, rr.TTL))
+		}
+		sort.Strings(values)
+		return values
+	}
+
+	d := newData(t, resource, map[string]interface{}{
+		"zone": "example.com",
+		"name": "@",
+		"record": []interface{}{
+			map[string]interface{}{"priority": 10, "servers": []interface{}{"mx1.example.com"}, "ttl": 300},
+			map[string]interface{}{"priority": 20, "servers": []interface{}{"backup.example.net"}},
+		},
+	})
+	if err := strategy.Create(fake, d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	// The block without a TTL uses the zone default
+	expectStrings(t, "TTLs after create", ttls(), []string{"10 mx1.example.com. 300", "20 backup.example.net. 0"})
+
+	// Only the servers of the block whose TTL changed are replaced
+	fake.Calls = nil
+	d = changedData(t, resource, d.State(), map[string]interface{}{
+		"zone": "example.com",
+		"name": "@",
+		"record": []interface{}{
+			map[string]interface{}{"priority": 10, "servers": []interface{}{"mx1.example.com"}, "ttl": 3600},
+			map[string]interface{}{"priority": 20, "servers": []interface{}{"backup.example.net"}},
+		},
+	})
+	if err := strategy.Update(fake, d); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	expectStrings(t, "update calls", fake.Calls, []string{
+		"remove MX @ mx1.example.com.",
+		"add MX @ mx1.example.com.",
+	})
+	expectStrings(t, "TTLs after update", ttls(), []string{"10 mx1.example.com. 3600", "20 backup.example.net. 0"})
+
+	// Refresh reads the TTL back into its block
+	if err := strategy.Read(fake, d); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	blocks := d.Get("record").([]interface{})
+	if len(blocks) != 2 || blocks[0].(map[string]interface{})["ttl"] != 3600 || blocks[1].(map[string]interface{})["ttl"] != 0 {
+		t.Errorf("record blocks after refresh = %v, want TTL 3600 on the first block only", blocks)
+	}
+}
+
+func TestMXRecordPerServerPriorities(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	strategy := strategies.NewMXRecordStrategy()
+
+	// One block per server, as migrated from a flat record list, with a priority shared out of order
+	d := newData(t, resources.ResourceDNSMXRecord(), map[string]interface{}{
+		"zone": "example.com",
+		"name": "@",
+		"record": []interface{}{
+			map[string]interface{}{"priority": 10, "servers": []interface{}{"mx1.example.com"}},
+			map[string]interface{}{"priority": 20, "servers": []interface{}{"mx2.example.com"}},
+			map[string]interface{}{"priority": 10, "servers": []interface{}{"mx3.example.com"}},
+		},
+	})
+	if err := strategy.Create(fake, d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	expectStrings(t, "zone after create", zoneContents(fake, "example.com", "@", "MX"), []string{
+		"10 mx1.example.com.",
+		"10 mx3.example.com.",
+		"20 mx2.example.com.",
+	})
+
+	// Refresh keeps a block per server instead of grouping by priority
+	if err := strategy.Read(fake, d); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	blocks := d.Get("record").([]interface{})
+	if len(blocks) != 3 {
+		t.Fatalf("record blocks after refresh = %v, want 3", blocks)
+	}
+	for i, want := range []struct {
+		priority int
+		server   string
+	}{
+		{10, "mx1.example.com"},
+		{20, "mx2.example.com"},
+		{10, "mx3.example.com"},
+	} {
+		block := blocks[i].(map[string]interface{})
+		if block["priority"] != want.priority {
+			t.Errorf("block %d priority = %v, want %d", i, block["priority"], want.priority)
+		}
+		expectStrings(t, "block servers", stringList(block["servers"]), []string{want.server})
+	}
+
+	// A priority changed outside Terraform no longer matches, so the records are grouped again
+	fake.SetRecords("example.com", []base.DNSRecord{
+		{Subname: "@", Rectype: "MX", Content: "mx1.example.com.", Prio: 10},
+		{Subname: "@", Rectype: "MX", Content: "mx2.example.com.", Prio: 20},
+		{Subname: "@", Rectype: "MX", Content: "mx3.example.com.", Prio: 30},
+	})
+	if err := strategy.Read(fake, d); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	blocks = d.Get("record").([]interface{})
+	if len(blocks) != 3 || blocks[2].(map[string]interface{})["priority"] != 30 {
+		t.Errorf("record blocks after drift = %v, want the priority 30 block last", blocks)
+	}
+}
+
+func TestMXRecordReadGroupsByPriority(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	fake.SetRecords("example.com", []base.DNSRecord{
+		{Subname: "@", Rectype: "MX", Content: "mx3.example.com.", Prio: 20},
+		{Subname: "@", Rectype: "MX", Content: "mx2.example.com.", Prio: 10},
+		{Subname: "@", Rectype: "MX", Content: "mx1.example.com.", Prio: 10},
+		{Subname: "mail", Rectype: "MX", Content: "other.example.com.", Prio: 10},
+	})
+
+	d := importData(resources.ResourceDNSMXRecord(), "example.com/@")
+	if err := strategies.NewMXRecordStrategy().Import(fake, d); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	blocks := d.Get("record").([]interface{})
+	if len(blocks) != 2 {
+		t.Fatalf("record blocks = %v, want 2", blocks)
+	}
+	for i, want := range []struct {
+		priority int
+		servers  []string
+	}{
+		{10, []string{"mx1.example.com", "mx2.example.com"}},
+		{20, []string{"mx3.example.com"}},
+	} {
+		block := blocks[i].(map[string]interface{})
+		if block["priority"] != want.priority {
+			t.Errorf("block %d priority = %v, want %d", i, block["priority"], want.priority)
+		}
+		expectStrings(t, "block servers", stringList(block["servers"]), want.servers)
+	}
+}
+
+func TestMXRecordReadRemovesDeletedRecords(t *testing.T) {
+	d := importData(resources.ResourceDNSMXRecord(), "example.com/@")
+	d.Set("zone", "example.com")
+	d.Set("name", "@")
+	if err := strategies.NewMXRecordStrategy().Read(fakeclient.New("example.com"), d); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if d.Id() != "" {
+		t.Errorf("ID = %q after reading a name without records, want it cleared", d.Id())
+	}
+}
+
+func TestMXRecordReadInconclusive(t *testing.T) {
+	// The zone holds no MX records, but the fresh read confirming it fails
+	d := importData(resources.ResourceDNSMXRecord(), "example.com/@")
+	d.Set("zone", "example.com")
+	d.Set("name", "@")
+	if err := strategies.NewMXRecordStrategy().Read(failingReadClient{fakeclient.New("example.com")}, d); err == nil {
+		t.Fatal("Read succeeded although the absence could not be confirmed")
+	}
+	if d.Id() != "example.com/@" {
+		t.Errorf("ID = %q after an inconclusive read, want it kept", d.Id())
+	}
+}
+
+// splitZoneClient is a fake client answering zone reads with the records split over two
+// differently spelled domain entries of the zone, next to an entry of another zone
+type splitZoneClient struct {
+	*fakeclient.Client
+}
+
+func (c splitZoneClient) GetRecordsWithCache(domainName string) ([]byte, error) {
+	return []byte(`{"result":"success","answer":{"domains":[
+		{"dname":"example.com","result":"success","rrs":[
+			{"subname":"@","rectype":"MX","content":"mx1.example.com.","prio":10}]},
+		{"dname":"other.com","result":"success","rrs":[
+			{"subname":"@","rectype":"MX","content":"mx.other.com.","prio":10}]},
+		{"dname":"Example.com.","result":"success","rrs":[
+			{"subname":"@","rectype":"MX","content":"mx2.example.com.","prio":20}]}]}}`), nil
+}
+
+func TestMXRecordReadAcrossDomainEntries(t *testing.T) {
+	d := importData(resources.ResourceDNSMXRecord(), "example.com/@")
+	if err := strategies.NewMXRecordStrategy().Import(splitZoneClient{fakeclient.New("example.com")}, d); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+
+	var got []string
+	for _, block := range d.Get("record").([]interface{}) {
+		block := block.(map[string]interface{})
+		for _, server := range stringList(block["servers"]) {
+			got = append(got, fmt.Sprintf("%d %s", block["priority"], server))
+		}
+	}
+	expectStrings(t, "records", got, []string{"10 mx1.example.com", "20 mx2.example.com"})
+}
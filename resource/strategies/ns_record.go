@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"sort"
 	"terraform-provider-regru/resource/base"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -22,16 +21,11 @@ func NewNSRecordStrategy() *NSRecordStrategy {
 
 // GetRecords returns the NS records from the resource data
 func (s *NSRecordStrategy) GetRecords(d *schema.ResourceData) []interface{} {
-	records := d.Get("record").([]interface{})
+	records, _ := d.Get("record").([]interface{})
 	var allRecords []interface{}
 
-	for _, recordInterface := range records {
-		recordMap := recordInterface.(map[string]interface{})
-		servers := recordMap["servers"].([]interface{})
-
-		for _, server := range servers {
-			allRecords = append(allRecords, server)
-		}
+	for _, record := range base.RecordsFromSchema("NS", records) {
+		allRecords = append(allRecords, record.Value)
 	}
 
 	return allRecords
@@ -60,6 +54,10 @@ func (s *NSRecordStrategy) ValidateRecords(records []interface{}) error {
 
 // Create creates NS records
 func (s *NSRecordStrategy) Create(client interface{}, d *schema.ResourceData) error {
+	if err := base.ValidateResourceRecords("NS", d); err != nil {
+		return err
+	}
+
 	// Type assert to get the cached client using shared interface
 	c, ok := client.(base.CachedClientInterface)
 	if !ok {
@@ -82,9 +80,12 @@ func (s *NSRecordStrategy) Create(client interface{}, d *schema.ResourceData) er
 			server := serverInterface.(string)
 
 			// For NS records, we need to add trailing dots for domain names
-			apiRecord := s.AddTrailingDot(server)
+			apiRecord := s.AddTrailingDot(c, server)
 			response, err := c.AddRecord("NS", zone, name, apiRecord, &priority)
 			if err != nil {
+				if s.ReconcileAfterTimeout(c, err, zone, name, "NS", apiRecord) {
+					continue
+				}
 				return fmt.Errorf("failed to create NS record: %w", err)
 			}
 
@@ -122,48 +123,38 @@ func (s *NSRecordStrategy) Read(client interface{}, d *schema.ResourceData) erro
 	if err := json.Unmarshal(response, &zoneResponse); err != nil {
 		return fmt.Errorf("failed to parse DNS records response: %w", err)
 	}
+	if err := zoneResponse.CheckZone(zone); err != nil {
+		return err
+	}
 
-	// Find NS records for this subdomain
-	var nsRecords []map[string]interface{}
-	priorityGroups := make(map[int][]string)
+	// Keep the configured spelling of servers so refresh doesn't report drift
+	configured := s.GetRecords(d)
 
+	// Find NS records for this subdomain
+	var found []base.Record
 	for _, domain := range zoneResponse.Answer.Domains {
-		if domain.Dname == zone {
+		if base.MatchesZone(domain.Dname, zone) {
 			for _, rr := range domain.Rrs {
-				if rr.Subname == name && rr.Rectype == "NS" {
+				if base.MatchesSubname(rr.Subname, name) && rr.Rectype == "NS" {
 					// Remove trailing dot from content for consistency
-					server := s.NormalizeDomain(rr.Content)
-					priority := rr.Prio // Use the priority from the record
-
-					priorityGroups[priority] = append(priorityGroups[priority], server)
+					found = append(found, base.Record{
+						Priority: rr.Prio, // Use the priority from the record
+						Value:    s.ConfiguredForm(rr.Content, configured),
+					})
 				}
 			}
-			break
 		}
 	}
 
-	if len(priorityGroups) == 0 {
+	if len(found) == 0 {
 		// No records found, mark as deleted
 		d.SetId("")
 		return nil
 	}
 
-	// Convert priority groups to record blocks
-	for priority, servers := range priorityGroups {
-		// Sort servers for consistent ordering
-		sort.Strings(servers)
-
-		record := map[string]interface{}{
-			"priority": priority,
-			"servers":  servers,
-		}
-		nsRecords = append(nsRecords, record)
-	}
-
-	// Sort by priority for consistent ordering
-	sort.Slice(nsRecords, func(i, j int) bool {
-		return nsRecords[i]["priority"].(int) < nsRecords[j]["priority"].(int)
-	})
+	// Keep the configured blocks when they match, otherwise group by priority into sorted blocks
+	configuredBlocks, _ := d.Get("record").([]interface{})
+	nsRecords := base.ConfiguredBlocks("NS", found, configuredBlocks)
 
 	// Set the data
 	d.Set("zone", zone)
@@ -175,6 +166,10 @@ func (s *NSRecordStrategy) Read(client interface{}, d *schema.ResourceData) erro
 
 // Update updates NS records using surgical approach - only change what actually changed
 func (s *NSRecordStrategy) Update(client interface{}, d *schema.ResourceData) error {
+	if err := base.ValidateResourceRecords("NS", d); err != nil {
+		return err
+	}
+
 	// Type assert to get the cached client using shared interface
 	c, ok := client.(base.CachedClientInterface)
 	if !ok {
@@ -209,8 +204,8 @@ func (s *NSRecordStrategy) Update(client interface{}, d *schema.ResourceData) er
 	// Remove records that are no longer needed
 	for _, record := range toRemove {
 		log.Printf("[DEBUG] Removing NS record: %s (priority: %d)", record.Server, record.Priority)
-		apiRecord := s.AddTrailingDot(record.Server)
-		response, err := c.RemoveRecord(zone, name, "NS", apiRecord, &record.Priority)
+		apiRecord := s.AddTrailingDot(c, record.Server)
+		response, err := c.RemoveRecord(zone, name, "NS", s.StoredContent(c, zone, name, "NS", apiRecord), &record.Priority)
 		if err != nil {
 			return fmt.Errorf("failed to remove NS record %s: %w", record.Server, err)
 		}
@@ -226,9 +221,12 @@ func (s *NSRecordStrategy) Update(client interface{}, d *schema.ResourceData) er
 	// Add new records
 	for _, record := range toAdd {
 		log.Printf("[DEBUG] Adding NS record: %s (priority: %d)", record.Server, record.Priority)
-		apiRecord := s.AddTrailingDot(record.Server)
+		apiRecord := s.AddTrailingDot(c, record.Server)
 		response, err := c.AddRecord("NS", zone, name, apiRecord, &record.Priority)
 		if err != nil {
+			if s.ReconcileAfterTimeout(c, err, zone, name, "NS", apiRecord) {
+				continue
+			}
 			return fmt.Errorf("failed to add NS record %s: %w", record.Server, err)
 		}
 
@@ -264,31 +262,11 @@ type NSRecord struct {
 func (s *NSRecordStrategy) parseRecordsFromState(records []interface{}) []NSRecord {
 	var nsRecords []NSRecord
 
-	for _, recordInterface := range records {
-		recordMap, ok := recordInterface.(map[string]interface{})
-		if !ok {
-			continue
-		}
-
-		priority, priorityOk := recordMap["priority"].(int)
-		if !priorityOk {
-			continue
-		}
-
-		serversInterface, serversOk := recordMap["servers"].([]interface{})
-		if !serversOk {
-			continue
-		}
-
-		// Convert each server in this priority group to individual NSRecord
-		for _, serverInterface := range serversInterface {
-			if server, serverOk := serverInterface.(string); serverOk {
-				nsRecords = append(nsRecords, NSRecord{
-					Priority: priority,
-					Server:   server,
-				})
-			}
-		}
+	for _, record := range base.RecordsFromSchema("NS", records) {
+		nsRecords = append(nsRecords, NSRecord{
+			Priority: record.Priority,
+			Server:   record.Value,
+		})
 	}
 
 	return nsRecords
@@ -348,29 +326,26 @@ func (s *NSRecordStrategy) Delete(client interface{}, d *schema.ResourceData) er
 	s.LogResourceOperation("Deleting", "NS", zone, name)
 
 	// Get the old NS records to remove
+	removed := 0
 	oldRecords, _ := d.GetChange("record")
-	if oldRecords != nil {
-		oldRecordsList := oldRecords.([]interface{})
-		for _, recordInterface := range oldRecordsList {
-			recordMap := recordInterface.(map[string]interface{})
-			priority := recordMap["priority"].(int)
-			servers := recordMap["servers"].([]interface{})
-
-			for _, serverInterface := range servers {
-				server := serverInterface.(string)
-
-				// For NS records, we need to add trailing dots for domain names
-				apiRecord := s.AddTrailingDot(server)
-				response, err := c.RemoveRecord(zone, name, "NS", apiRecord, &priority)
-				if err != nil {
-					return fmt.Errorf("failed to delete NS record: %w", err)
-				}
-
-				// Check API response for errors
-				if err := base.CheckAPIResponseForErrors(response); err != nil {
-					return fmt.Errorf("failed to delete NS record: %w", err)
-				}
+	if oldRecordsList, ok := oldRecords.([]interface{}); ok {
+		for _, record := range s.parseRecordsFromState(oldRecordsList) {
+			// For NS records, we need to add trailing dots for domain names
+			apiRecord := s.AddTrailingDot(c, record.Server)
+			response, err := c.RemoveRecord(zone, name, "NS", s.StoredContent(c, zone, name, "NS", apiRecord), &record.Priority)
+			if err := base.CheckRemoveResponse(response, err); err != nil {
+				return fmt.Errorf("failed to delete NS record: %w", err)
 			}
+			removed++
+		}
+	}
+
+	// If the state yielded nothing to remove, fall back to the zone contents
+	// so the records are not left orphaned in DNS
+	if removed == 0 {
+		log.Printf("[DEBUG] No NS records found in state for %s.%s, removing from zone data", name, zone)
+		if err := s.deleteFromZone(c, zone, name); err != nil {
+			return err
 		}
 	}
 
@@ -379,10 +354,41 @@ func (s *NSRecordStrategy) Delete(client interface{}, d *schema.ResourceData) er
 	return nil
 }
 
+// deleteFromZone removes all NS records for the subdomain as currently present in the zone
+func (s *NSRecordStrategy) deleteFromZone(c base.CachedClientInterface, zone, name string) error {
+	response, err := c.GetRecordsWithCache(zone)
+	if err != nil {
+		return fmt.Errorf("failed to get zone records for deletion: %w", err)
+	}
+
+	var zoneResponse base.DNSZoneResponse
+	if err := json.Unmarshal(response, &zoneResponse); err != nil {
+		return fmt.Errorf("failed to parse DNS records response for deletion: %w", err)
+	}
+
+	for _, domain := range zoneResponse.Answer.Domains {
+		if base.MatchesZone(domain.Dname, zone) {
+			for _, rr := range domain.Rrs {
+				if base.MatchesSubname(rr.Subname, name) && rr.Rectype == "NS" {
+					log.Printf("[DEBUG] Removing NS record: %s (priority: %d)", rr.Content, rr.Prio)
+
+					apiRecord := s.AddTrailingDot(c, rr.Content)
+					response, err := c.RemoveRecord(zone, name, "NS", apiRecord, &rr.Prio)
+					if err := base.CheckRemoveResponse(response, err); err != nil {
+						return fmt.Errorf("failed to delete NS record %s: %w", rr.Content, err)
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
 // Import imports an existing NS record
 func (s *NSRecordStrategy) Import(client interface{}, d *schema.ResourceData) error {
 	// Parse the import ID using the common format
-	zone, name, err := s.ParseResourceID(d.Id())
+	zone, name, err := s.ParseImportID(client, d.Id())
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,30 @@
+package strategies_test
+
+import (
+	"testing"
+
+	"terraform-provider-regru/internal/fakeclient"
+	"terraform-provider-regru/resource/base"
+	"terraform-provider-regru/resource/resources"
+	"terraform-provider-regru/resource/strategies"
+)
+
+func TestNSRecordDeleteWithoutRecordsInState(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	fake.SetRecords("example.com", []base.DNSRecord{
+		{Subname: "sub", Rectype: "NS", Content: "ns1.example.net.", Prio: 10},
+		{Subname: "sub", Rectype: "NS", Content: "ns2.example.net.", Prio: 10},
+		{Subname: "other", Rectype: "NS", Content: "ns1.example.net.", Prio: 10},
+	})
+
+	// State without record blocks, as left by an interrupted import, still removes the
+	// records at the name instead of orphaning them
+	d := importData(resources.ResourceDNSNSRecord(), "example.com/sub")
+	d.Set("zone", "example.com")
+	d.Set("name", "sub")
+	if err := strategies.NewNSRecordStrategy().Delete(fake, d); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	expectStrings(t, "zone after delete", zoneContents(fake, "example.com", "sub", "NS"), []string{})
+	expectStrings(t, "other name after delete", zoneContents(fake, "example.com", "other", "NS"), []string{"10 ns1.example.net."})
+}
@@ -11,7 +11,7 @@ func NewARecordStrategy() *GenericRecordStrategy {
 	return NewGenericRecordStrategy(
 		"A",
 		NoOpPreprocessor, // A records don't need preprocessing
-		DefaultRecordValidator("A"),
+		IPRecordValidator("A"),
 	)
 }
 
@@ -20,7 +20,7 @@ func NewAAAARecordStrategy() *GenericRecordStrategy {
 	return NewGenericRecordStrategy(
 		"AAAA",
 		NoOpPreprocessor, // AAAA records don't need preprocessing
-		DefaultRecordValidator("AAAA"),
+		IPRecordValidator("AAAA"),
 	)
 }
 
@@ -28,11 +28,22 @@ func NewAAAARecordStrategy() *GenericRecordStrategy {
 func NewTXTRecordStrategy() *GenericRecordStrategy {
 	return NewGenericRecordStrategy(
 		"TXT",
-		NoOpPreprocessor, // TXT records don't need preprocessing
+		TXTPreprocessor, // Long TXT values are stored as chunks of 255 bytes
 		DefaultRecordValidator("TXT"),
 	)
 }
 
+// NewPTRRecordStrategy creates a new PTR record strategy
+func NewPTRRecordStrategy() *TargetRecordStrategy {
+	return NewTargetRecordStrategy("PTR", "ptrdname")
+}
+
+// NewDNAMERecordStrategy creates a new DNAME record strategy. DNAME records redirect the
+// whole subtree below a name to another domain.
+func NewDNAMERecordStrategy() *TargetRecordStrategy {
+	return NewTargetRecordStrategy("DNAME", "target")
+}
+
 // NewNSRecordStrategy creates a new NS record strategy
 // This is now implemented in ns_record.go with custom logic
 
@@ -50,5 +61,10 @@ func NewMXRecordStrategy() *MXRecordStrategy {
 
 // NewCAARecordStrategy creates a new CAA record strategy (already defined in caa_record.go)
 
-// Interface compliance check - ensure generic strategy implements the interface
+// NewSSHFPRecordStrategy creates a new SSHFP record strategy (already defined in sshfp_record.go)
+
+// NewTLSARecordStrategy creates a new TLSA record strategy (already defined in tlsa_record.go)
+
+// Interface compliance checks - ensure the shared strategies implement the interface
 var _ base.RecordTypeStrategy = (*GenericRecordStrategy)(nil)
+var _ base.RecordTypeStrategy = (*TargetRecordStrategy)(nil)
@@ -42,34 +42,30 @@ func (s *SRVRecordStrategy) SetResourceID(d *schema.ResourceData, zone, name, re
 
 // parseSRVRecords converts the records from schema to SRVRecord structs
 func (s *SRVRecordStrategy) parseSRVRecords(d *schema.ResourceData) ([]SRVRecord, error) {
-	srvRecordBlocks := d.Get("record").([]interface{})
-	var srvRecords []SRVRecord
+	recordBlocks, _ := d.Get("record").([]interface{})
+	return s.srvRecordsFromBlocks(recordBlocks), nil
+}
 
-	for _, recordBlock := range srvRecordBlocks {
-		recordMap := recordBlock.(map[string]interface{})
-		
-		priority := recordMap["priority"].(int)
-		weight := recordMap["weight"].(int)
-		port := recordMap["port"].(int)
-		targets := recordMap["targets"].([]interface{})
-
-		for _, target := range targets {
-			targetStr := target.(string)
-			srvRecord := SRVRecord{
-				Priority: priority,
-				Weight:   weight,
-				Port:     port,
-				Target:   targetStr,
-			}
-			srvRecords = append(srvRecords, srvRecord)
-		}
+// srvRecordsFromBlocks converts record blocks to SRVRecord structs
+func (s *SRVRecordStrategy) srvRecordsFromBlocks(blocks []interface{}) []SRVRecord {
+	var srvRecords []SRVRecord
+	for _, record := range base.RecordsFromSchema("SRV", blocks) {
+		srvRecords = append(srvRecords, SRVRecord{
+			Priority: record.Priority,
+			Weight:   record.Weight,
+			Port:     record.Port,
+			Target:   record.Value,
+		})
 	}
-
-	return srvRecords, nil
+	return srvRecords
 }
 
 // Create creates SRV records
 func (s *SRVRecordStrategy) Create(meta interface{}, d *schema.ResourceData) error {
+	if err := base.ValidateResourceRecords("SRV", d); err != nil {
+		return err
+	}
+
 	c := meta.(base.CachedClientInterface)
 	zone := s.GetZone(d)
 	name := s.GetName(d)
@@ -100,11 +96,10 @@ func (s *SRVRecordStrategy) Create(meta interface{}, d *schema.ResourceData) err
 
 		response, err := c.AddSRVRecord(zone, name, srvRecord.Target, &srvRecord.Priority, &srvRecord.Weight, &srvRecord.Port)
 		if err != nil {
-			return fmt.Errorf("failed to create SRV record %s: %w", srvRecord.Target, err)
-		}
-
-		// Check API response for errors
-		if err := base.CheckAPIResponseForErrors(response); err != nil {
+			if !s.reconcileSRVAdd(c, err, zone, name, srvRecord) {
+				return fmt.Errorf("failed to create SRV record %s: %w", srvRecord.Target, err)
+			}
+		} else if err := base.CheckAPIResponseForErrors(response); err != nil {
 			return fmt.Errorf("failed to create SRV record %s: %w", srvRecord.Target, err)
 		}
 	}
@@ -112,7 +107,58 @@ func (s *SRVRecordStrategy) Create(meta interface{}, d *schema.ResourceData) err
 	// Set resource ID and common attributes
 	s.SetResourceID(d, zone, name, "SRV")
 
-	return s.Read(meta, d)
+	return s.ReadAfterWrite(c, d, zone, func() error { return s.Read(meta, d) })
+}
+
+// reconcileSRVAdd reports whether an SRV add that timed out was applied anyway, matching the
+// record by priority, weight and port as well as by target
+func (s *SRVRecordStrategy) reconcileSRVAdd(c base.CachedClientInterface, err error, zone, name string, record SRVRecord) bool {
+	target := s.NormalizeDomain(record.Target)
+	return s.ReconcileMatchingAfterTimeout(c, err, zone, name, "SRV", record.String(), func(rr base.DNSRecord) bool {
+		found := parseSRVContent(rr)
+		return found.Priority == record.Priority && found.Weight == record.Weight &&
+			found.Port == record.Port && s.NormalizeDomain(found.Target) == target
+	})
+}
+
+// parseSRVContent builds an SRV record from an API record. Some responses carry the
+// fields only in the content ("weight port target" or "priority weight port target"),
+// leaving the dedicated fields zero; values parsed from the content fill those in.
+func parseSRVContent(record base.DNSRecord) SRVRecord {
+	srvRecord := SRVRecord{
+		Priority: record.Prio,
+		Weight:   record.Weight,
+		Port:     record.Port,
+		Target:   record.Content,
+	}
+
+	parts := strings.Fields(record.Content)
+	if len(parts) < 3 {
+		return srvRecord
+	}
+	srvRecord.Target = parts[len(parts)-1]
+
+	numbers := make([]int, 0, 3)
+	for _, part := range parts[:len(parts)-1] {
+		value, err := strconv.Atoi(part)
+		if err != nil {
+			return srvRecord
+		}
+		numbers = append(numbers, value)
+	}
+
+	if len(numbers) == 3 && srvRecord.Priority == 0 {
+		srvRecord.Priority = numbers[0]
+	}
+	if len(numbers) >= 2 {
+		if srvRecord.Weight == 0 {
+			srvRecord.Weight = numbers[len(numbers)-2]
+		}
+		if srvRecord.Port == 0 {
+			srvRecord.Port = numbers[len(numbers)-1]
+		}
+	}
+	return srvRecord
 }
 
 // Read reads SRV records from the API
@@ -120,7 +166,6 @@ func (s *SRVRecordStrategy) Read(meta interface{}, d *schema.ResourceData) error
 	c := meta.(base.CachedClientInterface)
 	zone := s.GetZone(d)
 	name := s.GetName(d)
-	expectedPriority := s.GetPriority(d)
 
 	s.LogResourceOperation("Reading", "SRV", zone, name)
 
@@ -137,9 +182,20 @@ func (s *SRVRecordStrategy) Read(meta interface{}, d *schema.ResourceData) error
 	if err := json.Unmarshal(response, &zoneResponse); err != nil {
 		return fmt.Errorf("failed to parse DNS records response: %w", err)
 	}
+	if err := zoneResponse.CheckZone(zone); err != nil {
+		return err
+	}
 
 	log.Printf("[DEBUG] Parsed response - domains: %d", len(zoneResponse.Answer.Domains))
 
+	// Keep the configured spelling of targets so refresh doesn't report drift
+	var configuredTargets []interface{}
+	if configured, err := s.parseSRVRecords(d); err == nil {
+		for _, record := range configured {
+			configuredTargets = append(configuredTargets, record.Target)
+		}
+	}
+
 	// Parse response and find SRV records
 	var foundSRVRecords []SRVRecord
 	for _, domain := range zoneResponse.Answer.Domains {
@@ -148,30 +204,9 @@ func (s *SRVRecordStrategy) Read(meta interface{}, d *schema.ResourceData) error
 			log.Printf("[DEBUG] Record: type=%s, subname=%s, content=%s, prio=%d, weight=%d, port=%d",
 				record.Rectype, record.Subname, record.Content, record.Prio, record.Weight, record.Port)
 
-			if record.Rectype == "SRV" && record.Subname == name {
-				// For SRV records, match by priority if specified
-				if expectedPriority != nil && record.Prio != *expectedPriority {
-					log.Printf("[DEBUG] Priority mismatch: expected %d, got %d", *expectedPriority, record.Prio)
-					continue
-				}
-
-				// SRV content format: "weight port target" or just target
-				// The API should provide weight and port in separate fields
-				target := record.Content
-				if strings.Contains(target, " ") {
-					// Parse "weight port target" format if API returns combined format
-					parts := strings.Fields(target)
-					if len(parts) >= 3 {
-						target = parts[2] // Extract just the target
-					}
-				}
-
-				srvRecord := SRVRecord{
-					Priority: record.Prio,
-					Weight:   record.Weight,
-					Port:     record.Port,
-					Target:   s.NormalizeDomain(target),
-				}
+			if record.Rectype == "SRV" && base.MatchesSubname(record.Subname, name) {
+				srvRecord := parseSRVContent(record)
+				srvRecord.Target = s.ConfiguredForm(srvRecord.Target, configuredTargets)
 
 				foundSRVRecords = append(foundSRVRecords, srvRecord)
 			}
@@ -196,51 +231,17 @@ func (s *SRVRecordStrategy) Read(meta interface{}, d *schema.ResourceData) error
 	d.Set("zone", zone)
 	d.Set("name", name)
 
-	// Group SRV records by priority, weight, and port
-	recordGroups := make(map[string][]string)
+	// Group SRV records by priority, weight, and port into record blocks
+	var records []base.Record
 	for _, srvRecord := range foundSRVRecords {
-		key := fmt.Sprintf("%d_%d_%d", srvRecord.Priority, srvRecord.Weight, srvRecord.Port)
-		recordGroups[key] = append(recordGroups[key], srvRecord.Target)
-	}
-
-	// Convert to the new record block structure
-	var recordBlocks []map[string]interface{}
-	for key, targets := range recordGroups {
-		parts := strings.Split(key, "_")
-		priority, _ := strconv.Atoi(parts[0])
-		weight, _ := strconv.Atoi(parts[1])
-		port, _ := strconv.Atoi(parts[2])
-		
-		// Sort targets for consistent state
-		sort.Strings(targets)
-		
-		recordBlock := map[string]interface{}{
-			"priority": priority,
-			"weight":   weight,
-			"port":     port,
-			"targets":  targets,
-		}
-		recordBlocks = append(recordBlocks, recordBlock)
+		records = append(records, base.Record{
+			Priority: srvRecord.Priority,
+			Weight:   srvRecord.Weight,
+			Port:     srvRecord.Port,
+			Value:    srvRecord.Target,
+		})
 	}
-
-	// Sort record blocks by priority, weight, port for consistent state
-	sort.Slice(recordBlocks, func(i, j int) bool {
-		prioI := recordBlocks[i]["priority"].(int)
-		prioJ := recordBlocks[j]["priority"].(int)
-		if prioI != prioJ {
-			return prioI < prioJ
-		}
-		
-		weightI := recordBlocks[i]["weight"].(int)
-		weightJ := recordBlocks[j]["weight"].(int)
-		if weightI != weightJ {
-			return weightI < weightJ
-		}
-		
-		portI := recordBlocks[i]["port"].(int)
-		portJ := recordBlocks[j]["port"].(int)
-		return portI < portJ
-	})
+	recordBlocks := base.RecordsToSchema("SRV", records)
 
 	d.Set("record", recordBlocks)
 
@@ -250,6 +251,10 @@ func (s *SRVRecordStrategy) Read(meta interface{}, d *schema.ResourceData) error
 
 // Update updates SRV records
 func (s *SRVRecordStrategy) Update(meta interface{}, d *schema.ResourceData) error {
+	if err := base.ValidateResourceRecords("SRV", d); err != nil {
+		return err
+	}
+
 	c := meta.(base.CachedClientInterface)
 	zone := s.GetZone(d)
 	name := s.GetName(d)
@@ -326,10 +331,10 @@ func (s *SRVRecordStrategy) Update(meta interface{}, d *schema.ResourceData) err
 				record.Priority, record.Weight, record.Port, record.Target)
 			response, err := c.AddSRVRecord(zone, name, record.Target, &record.Priority, &record.Weight, &record.Port)
 			if err != nil {
-				return fmt.Errorf("failed to add SRV record %s: %w", record.Target, err)
-			}
-
-			if err := base.CheckAPIResponseForErrors(response); err != nil {
+				if !s.reconcileSRVAdd(c, err, zone, name, record) {
+					return fmt.Errorf("failed to add SRV record %s: %w", record.Target, err)
+				}
+			} else if err := base.CheckAPIResponseForErrors(response); err != nil {
 				return fmt.Errorf("failed to add SRV record %s: %w", record.Target, err)
 			}
 		}
@@ -338,36 +343,14 @@ func (s *SRVRecordStrategy) Update(meta interface{}, d *schema.ResourceData) err
 		c.InvalidateZoneCache(zone)
 	}
 
-	return s.Read(meta, d)
+	return s.ReadAfterWrite(c, d, zone, func() error { return s.Read(meta, d) })
 }
 
 // getOldSRVRecords reconstructs old SRV records from the change data
 func (s *SRVRecordStrategy) getOldSRVRecords(d *schema.ResourceData) ([]SRVRecord, error) {
 	old, _ := d.GetChange("record")
-	oldRecordBlocks := old.([]interface{})
-
-	var srvRecords []SRVRecord
-	for _, recordBlock := range oldRecordBlocks {
-		recordMap := recordBlock.(map[string]interface{})
-		
-		priority := recordMap["priority"].(int)
-		weight := recordMap["weight"].(int)
-		port := recordMap["port"].(int)
-		targets := recordMap["targets"].([]interface{})
-
-		for _, target := range targets {
-			targetStr := target.(string)
-			srvRecord := SRVRecord{
-				Priority: priority,
-				Weight:   weight,
-				Port:     port,
-				Target:   targetStr,
-			}
-			srvRecords = append(srvRecords, srvRecord)
-		}
-	}
-
-	return srvRecords, nil
+	oldRecordBlocks, _ := old.([]interface{})
+	return s.srvRecordsFromBlocks(oldRecordBlocks), nil
 }
 
 // Delete deletes SRV records
@@ -388,11 +371,7 @@ func (s *SRVRecordStrategy) Delete(meta interface{}, d *schema.ResourceData) err
 		log.Printf("[DEBUG] Removing SRV record: %s -> %d %d %d %s", name,
 			srvRecord.Priority, srvRecord.Weight, srvRecord.Port, srvRecord.Target)
 		response, err := c.RemoveSRVRecord(zone, name, srvRecord.Target, &srvRecord.Priority, &srvRecord.Weight, &srvRecord.Port)
-		if err != nil {
-			return fmt.Errorf("failed to delete SRV record %s: %w", srvRecord.Target, err)
-		}
-
-		if err := base.CheckAPIResponseForErrors(response); err != nil {
+		if err := base.CheckRemoveResponse(response, err); err != nil {
 			return fmt.Errorf("failed to delete SRV record %s: %w", srvRecord.Target, err)
 		}
 	}
@@ -406,7 +385,7 @@ func (s *SRVRecordStrategy) Delete(meta interface{}, d *schema.ResourceData) err
 // Import imports an existing SRV record
 func (s *SRVRecordStrategy) Import(meta interface{}, d *schema.ResourceData) error {
 	// Parse the import ID using the common format
-	zone, name, err := s.ParseResourceID(d.Id())
+	zone, name, err := s.ParseImportID(meta, d.Id())
 	if err != nil {
 		return err
 	}
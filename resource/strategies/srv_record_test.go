@@ -0,0 +1,232 @@
+package strategies_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"terraform-provider-regru/internal/fakeclient"
+	"terraform-provider-regru/resource/base"
+	"terraform-provider-regru/resource/resources"
+	"terraform-provider-regru/resource/strategies"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// srvContents returns the SRV records the fake client holds at a name as
+// "priority weight port target", sorted
+func srvContents(f *fakeclient.Client, zone, name string) []string {
+	contents := []string{}
+	for _, rr := range f.Records(zone) {
+		if rr.Rectype == "SRV" && rr.Subname == name {
+			contents = append(contents, fmt.Sprintf("%d %d %d %s", rr.Prio, rr.Weight, rr.Port, rr.Content))
+		}
+	}
+	sort.Strings(contents)
+	return contents
+}
+
+func TestSRVRecordLifecycle(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	strategy := strategies.NewSRVRecordStrategy()
+	resource := resources.ResourceDNSSRVRecord()
+
+	d := newData(t, resource, map[string]interface{}{
+		"zone": "example.com",
+		"name": "_sip._tcp",
+		"record": []interface{}{
+			map[string]interface{}{"priority": 10, "weight": 5, "port": 5060, "targets": []interface{}{"sip1.example.com", "sip2.example.com"}},
+		},
+	})
+	if err := strategy.Create(fake, d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	expectStrings(t, "zone after create", srvContents(fake, "example.com", "_sip._tcp"), []string{
+		"10 5 5060 sip1.example.com",
+		"10 5 5060 sip2.example.com",
+	})
+
+	// Changing the port of a target replaces only that target's record
+	fake.Calls = nil
+	d = changedData(t, resource, d.State(), map[string]interface{}{
+		"zone": "example.com",
+		"name": "_sip._tcp",
+		"record": []interface{}{
+			map[string]interface{}{"priority": 10, "weight": 5, "port": 5060, "targets": []interface{}{"sip1.example.com"}},
+			map[string]interface{}{"priority": 10, "weight": 5, "port": 5061, "targets": []interface{}{"sip2.example.com"}},
+		},
+	})
+	if err := strategy.Update(fake, d); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	expectStrings(t, "update calls", fake.Calls, []string{
+		"remove SRV _sip._tcp sip2.example.com",
+		"add SRV _sip._tcp sip2.example.com",
+	})
+	expectStrings(t, "zone after update", srvContents(fake, "example.com", "_sip._tcp"), []string{
+		"10 5 5060 sip1.example.com",
+		"10 5 5061 sip2.example.com",
+	})
+
+	if err := strategy.Delete(fake, d); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	expectStrings(t, "zone after delete", srvContents(fake, "example.com", "_sip._tcp"), []string{})
+}
+
+func TestSRVRecordReadAllPriorities(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	fake.SetRecords("example.com", []base.DNSRecord{
+		{Subname: "_sip._tcp", Rectype: "SRV", Content: "sip1.example.com.", Prio: 10, Weight: 5, Port: 5060},
+		{Subname: "_sip._tcp", Rectype: "SRV", Content: "sip2.example.com.", Prio: 20, Weight: 5, Port: 5060},
+		{Subname: "_sip._tcp", Rectype: "SRV", Content: "sip3.example.com.", Prio: 0, Weight: 0, Port: 5060},
+	})
+
+	// SRV has no top-level priority, so every record at the name is read whatever its priority
+	d := importData(resources.ResourceDNSSRVRecord(), "example.com/_sip._tcp")
+	if err := strategies.NewSRVRecordStrategy().Import(fake, d); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	var records []string
+	for _, block := range d.Get("record").([]interface{}) {
+		block := block.(map[string]interface{})
+		for _, target := range stringList(block["targets"]) {
+			records = append(records, fmt.Sprintf("%d %d %d %s", block["priority"], block["weight"], block["port"], target))
+		}
+	}
+	sort.Strings(records)
+	expectStrings(t, "records", records, []string{
+		"0 0 5060 sip3.example.com",
+		"10 5 5060 sip1.example.com",
+		"20 5 5060 sip2.example.com",
+	})
+}
+
+func TestSRVRecordUpdateWithoutChanges(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	strategy := strategies.NewSRVRecordStrategy()
+	resource := resources.ResourceDNSSRVRecord()
+	config := map[string]interface{}{
+		"zone": "example.com",
+		"name": "_xmpp._tcp",
+		"record": []interface{}{
+			map[string]interface{}{"priority": 5, "weight": 0, "port": 5222, "targets": []interface{}{"xmpp.example.com"}},
+		},
+	}
+
+	d := newData(t, resource, config)
+	if err := strategy.Create(fake, d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	fake.Calls = nil
+	d = changedData(t, resource, d.State(), config)
+	if err := strategy.Update(fake, d); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	expectStrings(t, "update calls", fake.Calls, nil)
+}
+
+func TestSRVRecordFieldsOnlyInContent(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	strategy := strategies.NewSRVRecordStrategy()
+	resource := resources.ResourceDNSSRVRecord()
+	config := map[string]interface{}{
+		"zone": "example.com",
+		"name": "_https._tcp",
+		"record": []interface{}{
+			map[string]interface{}{"priority": 10, "weight": 5, "port": 443, "targets": []interface{}{"web.example.com"}},
+		},
+	}
+
+	d := newData(t, resource, config)
+	if err := strategy.Create(fake, d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// The API reports weight and port only in the content, leaving the dedicated fields zero
+	fake.SetRecords("example.com", []base.DNSRecord{
+		{Subname: "_https._tcp", Rectype: "SRV", Content: "5 443 web.example.com.", Prio: 10},
+	})
+	if diags := resource.ReadContext(context.Background(), d, fake); diags.HasError() {
+		t.Fatalf("Read: %v", diags)
+	}
+	block := d.Get("record").([]interface{})[0].(map[string]interface{})
+	if block["weight"] != 5 || block["port"] != 443 {
+		t.Errorf("record after refresh = %v, want weight 5 and port 443", block)
+	}
+	expectStrings(t, "targets after refresh", stringList(block["targets"]), []string{"web.example.com"})
+
+	diff, err := schema.InternalMap(resource.Schema).Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(config), nil, nil, true)
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	if !diff.Empty() {
+		t.Errorf("plan after refresh shows changes: %v", diff)
+	}
+}
+
+func TestSRVRecordRootTarget(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	resource := resources.ResourceDNSSRVRecord()
+	// A target of "." announces that the service is not available at the domain
+	config := map[string]interface{}{
+		"zone": "example.com",
+		"name": "_imap._tcp",
+		"record": []interface{}{
+			map[string]interface{}{"priority": 0, "weight": 0, "port": 0, "targets": []interface{}{"."}},
+		},
+	}
+
+	d := newData(t, resource, config)
+	if diags := resource.CreateContext(context.Background(), d, fake); diags.HasError() {
+		t.Fatalf("Create: %v", diags)
+	}
+	expectStrings(t, "zone after create", srvContents(fake, "example.com", "_imap._tcp"), []string{"0 0 0 ."})
+
+	if diags := resource.ReadContext(context.Background(), d, fake); diags.HasError() {
+		t.Fatalf("Read: %v", diags)
+	}
+	block := d.Get("record").([]interface{})[0].(map[string]interface{})
+	expectStrings(t, "targets after refresh", stringList(block["targets"]), []string{"."})
+
+	diff, err := schema.InternalMap(resource.Schema).Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(config), nil, nil, true)
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	if !diff.Empty() {
+		t.Errorf("plan after refresh shows changes: %v", diff)
+	}
+}
+
+func TestSRVRecordAddAfterTimeout(t *testing.T) {
+	fake := fakeclient.New("example.com")
+	strategy := strategies.NewSRVRecordStrategy()
+	resource := resources.ResourceDNSSRVRecord()
+	config := func(port int) map[string]interface{} {
+		return map[string]interface{}{
+			"zone": "example.com",
+			"name": "_sip._tcp",
+			"record": []interface{}{
+				map[string]interface{}{"priority": 10, "weight": 5, "port": port, "targets": []interface{}{"sip.example.com"}},
+			},
+		}
+	}
+
+	// The record turns out to be present, so the add is neither failed nor repeated
+	d := newData(t, resource, config(5060))
+	if err := strategy.Create(timeoutClient{Client: fake}, d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	expectStrings(t, "zone after create", srvContents(fake, "example.com", "_sip._tcp"), []string{"10 5 5060 sip.example.com"})
+
+	// A lost add is reported even though a record with the same target is present at another port
+	fake.SetRecords("example.com", append(fake.Records("example.com"),
+		base.DNSRecord{Subname: "_sip._tcp", Rectype: "SRV", Content: "sip.example.com", Prio: 10, Weight: 5, Port: 5070}))
+	d = changedData(t, resource, d.State(), config(5061))
+	if err := strategy.Update(timeoutClient{Client: fake, lost: true}, d); err == nil {
+		t.Fatal("Update succeeded although the record was never added")
+	}
+}
@@ -0,0 +1,295 @@
+package strategies
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"terraform-provider-regru/resource/base"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// SSHFPRecordStrategy implements the strategy for SSHFP records
+type SSHFPRecordStrategy struct {
+	base.BaseStrategy
+}
+
+// NewSSHFPRecordStrategy creates a new SSHFP record strategy
+func NewSSHFPRecordStrategy() *SSHFPRecordStrategy {
+	return &SSHFPRecordStrategy{}
+}
+
+// SSHFPRecord represents a single SSHFP record
+type SSHFPRecord struct {
+	Algorithm   int    `json:"algorithm"`
+	FpType      int    `json:"fp_type"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// String returns a sortable string representation of the SSHFP record.
+// Fingerprints are hexadecimal, so they are compared regardless of letter case.
+func (sshfp SSHFPRecord) String() string {
+	return fmt.Sprintf("%d_%d_%s", sshfp.Algorithm, sshfp.FpType, strings.ToLower(sshfp.Fingerprint))
+}
+
+// SetResourceID sets a stable resource ID for the SSHFP record
+func (s *SSHFPRecordStrategy) SetResourceID(d *schema.ResourceData, zone, name, recordType string) {
+	d.SetId(fmt.Sprintf("%s/%s", zone, name))
+}
+
+// parseSSHFPRecords converts the records from schema to SSHFPRecord structs
+func (s *SSHFPRecordStrategy) parseSSHFPRecords(d *schema.ResourceData) ([]SSHFPRecord, error) {
+	recordBlocks, _ := d.Get("record").([]interface{})
+	return s.sshfpRecordsFromBlocks(recordBlocks), nil
+}
+
+// sshfpRecordsFromBlocks converts record blocks to SSHFPRecord structs
+func (s *SSHFPRecordStrategy) sshfpRecordsFromBlocks(blocks []interface{}) []SSHFPRecord {
+	var sshfpRecords []SSHFPRecord
+	for _, record := range base.RecordsFromSchema("SSHFP", blocks) {
+		sshfpRecords = append(sshfpRecords, SSHFPRecord{
+			Algorithm:   record.Algorithm,
+			FpType:      record.FpType,
+			Fingerprint: record.Value,
+		})
+	}
+	return sshfpRecords
+}
+
+// Create creates SSHFP records
+func (s *SSHFPRecordStrategy) Create(meta interface{}, d *schema.ResourceData) error {
+	if err := base.ValidateResourceRecords("SSHFP", d); err != nil {
+		return err
+	}
+
+	c := meta.(base.CachedClientInterface)
+	zone := s.GetZone(d)
+	name := s.GetName(d)
+
+	sshfpRecords, err := s.parseSSHFPRecords(d)
+	if err != nil {
+		return err
+	}
+
+	s.LogResourceOperation("Creating", "SSHFP", zone, name)
+
+	if len(sshfpRecords) == 0 {
+		return fmt.Errorf("at least one SSHFP record must be specified")
+	}
+
+	// Sort records for consistent processing
+	sort.Slice(sshfpRecords, func(i, j int) bool {
+		return sshfpRecords[i].String() < sshfpRecords[j].String()
+	})
+
+	for _, sshfpRecord := range sshfpRecords {
+		log.Printf("[DEBUG] Adding SSHFP record: %s.%s -> %d %d %s", name, zone,
+			sshfpRecord.Algorithm, sshfpRecord.FpType, sshfpRecord.Fingerprint)
+
+		response, err := c.AddSSHFPRecord(zone, name, sshfpRecord.Fingerprint, &sshfpRecord.Algorithm, &sshfpRecord.FpType)
+		if err != nil {
+			if !s.reconcileSSHFPAdd(c, err, zone, name, sshfpRecord) {
+				return fmt.Errorf("failed to create SSHFP record %s: %w", sshfpRecord.Fingerprint, err)
+			}
+		} else if err := base.CheckAPIResponseForErrors(response); err != nil {
+			return fmt.Errorf("failed to create SSHFP record %s: %w", sshfpRecord.Fingerprint, err)
+		}
+	}
+
+	s.SetResourceID(d, zone, name, "SSHFP")
+
+	return s.ReadAfterWrite(c, d, zone, func() error { return s.Read(meta, d) })
+}
+
+// reconcileSSHFPAdd reports whether an SSHFP add that timed out was applied anyway, matching the
+// record by algorithm and fingerprint type as well as by fingerprint
+func (s *SSHFPRecordStrategy) reconcileSSHFPAdd(c base.CachedClientInterface, err error, zone, name string, record SSHFPRecord) bool {
+	return s.ReconcileMatchingAfterTimeout(c, err, zone, name, "SSHFP", record.String(), func(rr base.DNSRecord) bool {
+		parsed := base.ParseSSHFPContent(rr)
+		found := SSHFPRecord{Algorithm: parsed.Algorithm, FpType: parsed.FpType, Fingerprint: parsed.Value}
+		return found.String() == record.String()
+	})
+}
+
+// Read reads SSHFP records from the API
+func (s *SSHFPRecordStrategy) Read(meta interface{}, d *schema.ResourceData) error {
+	c := meta.(base.CachedClientInterface)
+	zone := s.GetZone(d)
+	name := s.GetName(d)
+
+	s.LogResourceOperation("Reading", "SSHFP", zone, name)
+
+	response, err := c.GetRecordsWithCache(zone)
+	if err != nil {
+		return fmt.Errorf("failed to get zone records: %w", err)
+	}
+
+	var zoneResponse base.DNSZoneResponse
+	if err := json.Unmarshal(response, &zoneResponse); err != nil {
+		return fmt.Errorf("failed to parse DNS records response: %w", err)
+	}
+	if err := zoneResponse.CheckZone(zone); err != nil {
+		return err
+	}
+
+	// Keep the configured letter case of fingerprints so refresh doesn't report drift
+	configured, _ := s.parseSSHFPRecords(d)
+
+	var foundSSHFPRecords []SSHFPRecord
+	for _, domain := range zoneResponse.Answer.Domains {
+		for _, record := range domain.Rrs {
+			if record.Rectype != "SSHFP" || !base.MatchesSubname(record.Subname, name) {
+				continue
+			}
+
+			parsed := base.ParseSSHFPContent(record)
+			sshfpRecord := SSHFPRecord{
+				Algorithm:   parsed.Algorithm,
+				FpType:      parsed.FpType,
+				Fingerprint: parsed.Value,
+			}
+			for _, configuredRecord := range configured {
+				if configuredRecord.String() == sshfpRecord.String() {
+					sshfpRecord.Fingerprint = configuredRecord.Fingerprint
+					break
+				}
+			}
+
+			foundSSHFPRecords = append(foundSSHFPRecords, sshfpRecord)
+		}
+	}
+
+	if len(foundSSHFPRecords) == 0 {
+		log.Printf("[DEBUG] No SSHFP records found for %s.%s", name, zone)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("zone", zone)
+	d.Set("name", name)
+
+	var records []base.Record
+	for _, sshfpRecord := range foundSSHFPRecords {
+		records = append(records, base.Record{
+			Algorithm: sshfpRecord.Algorithm,
+			FpType:    sshfpRecord.FpType,
+			Value:     sshfpRecord.Fingerprint,
+		})
+	}
+	d.Set("record", base.RecordsToSchema("SSHFP", records))
+
+	log.Printf("[DEBUG] Successfully read %d SSHFP records", len(foundSSHFPRecords))
+	return nil
+}
+
+// Update updates SSHFP records
+func (s *SSHFPRecordStrategy) Update(meta interface{}, d *schema.ResourceData) error {
+	if err := base.ValidateResourceRecords("SSHFP", d); err != nil {
+		return err
+	}
+
+	c := meta.(base.CachedClientInterface)
+	zone := s.GetZone(d)
+	name := s.GetName(d)
+
+	s.LogResourceOperation("Updating", "SSHFP", zone, name)
+
+	if d.HasChange("record") {
+		old, _ := d.GetChange("record")
+		oldBlocks, _ := old.([]interface{})
+		oldSSHFPRecords := s.sshfpRecordsFromBlocks(oldBlocks)
+
+		newSSHFPRecords, err := s.parseSSHFPRecords(d)
+		if err != nil {
+			return err
+		}
+
+		// Records are matched by algorithm, fingerprint type and fingerprint together
+		oldKeys := make(map[string]bool, len(oldSSHFPRecords))
+		for _, record := range oldSSHFPRecords {
+			oldKeys[record.String()] = true
+		}
+		newKeys := make(map[string]bool, len(newSSHFPRecords))
+		for _, record := range newSSHFPRecords {
+			newKeys[record.String()] = true
+		}
+
+		for _, record := range oldSSHFPRecords {
+			if newKeys[record.String()] {
+				continue
+			}
+			log.Printf("[DEBUG] Removing SSHFP record: %s -> %d %d %s", name,
+				record.Algorithm, record.FpType, record.Fingerprint)
+			response, err := c.RemoveSSHFPRecord(zone, name, record.Fingerprint, &record.Algorithm, &record.FpType)
+			if err != nil {
+				return fmt.Errorf("failed to remove SSHFP record %s: %w", record.Fingerprint, err)
+			}
+
+			if err := base.CheckAPIResponseForErrors(response); err != nil {
+				return fmt.Errorf("failed to remove SSHFP record %s: %w", record.Fingerprint, err)
+			}
+		}
+
+		for _, record := range newSSHFPRecords {
+			if oldKeys[record.String()] {
+				continue
+			}
+			log.Printf("[DEBUG] Adding SSHFP record: %s -> %d %d %s", name,
+				record.Algorithm, record.FpType, record.Fingerprint)
+			response, err := c.AddSSHFPRecord(zone, name, record.Fingerprint, &record.Algorithm, &record.FpType)
+			if err != nil {
+				if !s.reconcileSSHFPAdd(c, err, zone, name, record) {
+					return fmt.Errorf("failed to add SSHFP record %s: %w", record.Fingerprint, err)
+				}
+			} else if err := base.CheckAPIResponseForErrors(response); err != nil {
+				return fmt.Errorf("failed to add SSHFP record %s: %w", record.Fingerprint, err)
+			}
+		}
+
+		c.InvalidateZoneCache(zone)
+	}
+
+	return s.ReadAfterWrite(c, d, zone, func() error { return s.Read(meta, d) })
+}
+
+// Delete deletes SSHFP records
+func (s *SSHFPRecordStrategy) Delete(meta interface{}, d *schema.ResourceData) error {
+	c := meta.(base.CachedClientInterface)
+	zone := s.GetZone(d)
+	name := s.GetName(d)
+
+	sshfpRecords, err := s.parseSSHFPRecords(d)
+	if err != nil {
+		return err
+	}
+
+	s.LogResourceOperation("Deleting", "SSHFP", zone, name)
+
+	for _, sshfpRecord := range sshfpRecords {
+		log.Printf("[DEBUG] Removing SSHFP record: %s -> %d %d %s", name,
+			sshfpRecord.Algorithm, sshfpRecord.FpType, sshfpRecord.Fingerprint)
+		response, err := c.RemoveSSHFPRecord(zone, name, sshfpRecord.Fingerprint, &sshfpRecord.Algorithm, &sshfpRecord.FpType)
+		if err := base.CheckRemoveResponse(response, err); err != nil {
+			return fmt.Errorf("failed to delete SSHFP record %s: %w", sshfpRecord.Fingerprint, err)
+		}
+	}
+
+	c.InvalidateZoneCache(zone)
+
+	return nil
+}
+
+// Import imports existing SSHFP records
+func (s *SSHFPRecordStrategy) Import(meta interface{}, d *schema.ResourceData) error {
+	zone, name, err := s.ParseImportID(meta, d.Id())
+	if err != nil {
+		return err
+	}
+
+	d.Set("zone", zone)
+	d.Set("name", name)
+
+	return s.Read(meta, d)
+}
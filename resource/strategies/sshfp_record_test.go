@@ -0,0 +1,112 @@
+package strategies_test
+
+import (
+	"strings"
+	"testing"
+
+	"terraform-provider-regru/internal/fakeclient"
+	"terraform-provider-regru/resource/base"
+	"terraform-provider-regru/resource/resources"
+	"terraform-provider-regru/resource/strategies"
+)
+
+// sshfpBlock returns an SSHFP record block of the resource configuration
+func sshfpBlock(algorithm, fpType int, fingerprint string) map[string]interface{} {
+	return map[string]interface{}{"algorithm": algorithm, "fp_type": fpType, "fingerprint": fingerprint}
+}
+
+func TestSSHFPRecordLifecycle(t *testing.T) {
+	ed25519 := strings.Repeat("ab", 32)
+	rsa := strings.Repeat("cd", 32)
+	ecdsa := strings.Repeat("ef", 20)
+
+	fake := fakeclient.New("example.com")
+	strategy := strategies.NewSSHFPRecordStrategy()
+	resource := resources.ResourceDNSSSHFPRecord()
+
+	d := newData(t, resource, map[string]interface{}{
+		"zone":   "example.com",
+		"name":   "host",
+		"record": []interface{}{sshfpBlock(4, 2, ed25519), sshfpBlock(1, 2, rsa)},
+	})
+	if err := strategy.Create(fake, d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if d.Id() != "example.com/host" {
+		t.Errorf("ID = %q, want example.com/host", d.Id())
+	}
+	expectStrings(t, "zone after create", zoneContents(fake, "example.com", "host", "SSHFP"), []string{
+		"1 2 " + rsa,
+		"4 2 " + ed25519,
+	})
+
+	// Only the record whose fields changed is replaced
+	fake.Calls = nil
+	d = changedData(t, resource, d.State(), map[string]interface{}{
+		"zone":   "example.com",
+		"name":   "host",
+		"record": []interface{}{sshfpBlock(4, 2, ed25519), sshfpBlock(3, 1, ecdsa)},
+	})
+	if err := strategy.Update(fake, d); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	expectStrings(t, "update calls", fake.Calls, []string{
+		"remove SSHFP host 1 2 " + rsa,
+		"add SSHFP host 3 1 " + ecdsa,
+	})
+
+	if err := strategy.Delete(fake, d); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	expectStrings(t, "zone after delete", zoneContents(fake, "example.com", "host", "SSHFP"), []string{})
+}
+
+func TestSSHFPRecordImport(t *testing.T) {
+	fingerprint := strings.Repeat("AB", 32)
+	fake := fakeclient.New("example.com")
+	fake.SetRecords("example.com", []base.DNSRecord{
+		{Subname: "host", Rectype: "SSHFP", Content: "4 2 " + fingerprint},
+		{Subname: "other", Rectype: "SSHFP", Content: "1 1 " + strings.Repeat("cd", 20)},
+	})
+
+	d := importData(resources.ResourceDNSSSHFPRecord(), "example.com/host")
+	if err := strategies.NewSSHFPRecordStrategy().Import(fake, d); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	blocks := d.Get("record").([]interface{})
+	if len(blocks) != 1 {
+		t.Fatalf("record blocks = %v, want 1", blocks)
+	}
+	block := blocks[0].(map[string]interface{})
+	if block["algorithm"] != 4 || block["fp_type"] != 2 || !strings.EqualFold(block["fingerprint"].(string), fingerprint) {
+		t.Errorf("record block = %v, want algorithm 4, fp_type 2 and the fingerprint", block)
+	}
+}
+
+func TestSSHFPRecordAddAfterTimeout(t *testing.T) {
+	fingerprint := strings.Repeat("ab", 32)
+	resource := resources.ResourceDNSSSHFPRecord()
+	config := map[string]interface{}{
+		"zone":   "example.com",
+		"name":   "host",
+		"record": []interface{}{sshfpBlock(4, 2, fingerprint)},
+	}
+
+	// The record turns out to be present, so the add is neither failed nor repeated
+	fake := fakeclient.New("example.com")
+	d := newData(t, resource, config)
+	if err := strategies.NewSSHFPRecordStrategy().Create(timeoutClient{Client: fake}, d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	expectStrings(t, "zone after create", zoneContents(fake, "example.com", "host", "SSHFP"), []string{"4 2 " + fingerprint})
+
+	// A lost add is reported even though the fingerprint is present with another algorithm
+	fake = fakeclient.New("example.com")
+	fake.SetRecords("example.com", []base.DNSRecord{
+		{Subname: "host", Rectype: "SSHFP", Content: "1 2 " + fingerprint},
+	})
+	d = newData(t, resource, config)
+	if err := strategies.NewSSHFPRecordStrategy().Create(timeoutClient{Client: fake, lost: true}, d); err == nil {
+		t.Fatal("Create succeeded although the record was never added")
+	}
+}
@@ -0,0 +1,186 @@
+package strategies
+
+import (
+	"fmt"
+	"terraform-provider-regru/resource/base"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// TargetRecordStrategy implements the strategy for record types holding a single domain name
+// in one attribute, such as PTR (ptrdname) and DNAME (target)
+type TargetRecordStrategy struct {
+	base.BaseStrategy
+	recordType string
+	field      string
+}
+
+// NewTargetRecordStrategy creates a strategy for records of the type whose domain name is
+// configured in the given attribute
+func NewTargetRecordStrategy(recordType, field string) *TargetRecordStrategy {
+	return &TargetRecordStrategy{
+		BaseStrategy: base.BaseStrategy{
+			CommonRecord: base.CommonRecord{RecordType: recordType},
+		},
+		recordType: recordType,
+		field:      field,
+	}
+}
+
+// GetRecords returns the configured domain name from the resource data
+func (s *TargetRecordStrategy) GetRecords(d *schema.ResourceData) []interface{} {
+	target := d.Get(s.field).(string)
+	return []interface{}{target}
+}
+
+// SetResourceID sets a stable resource ID for the record
+func (s *TargetRecordStrategy) SetResourceID(d *schema.ResourceData, zone, name, recordType string) {
+	d.SetId(fmt.Sprintf("%s/%s", zone, name))
+}
+
+// Create creates the record
+func (s *TargetRecordStrategy) Create(client interface{}, d *schema.ResourceData) error {
+	if err := base.ValidateResourceRecords(s.recordType, d); err != nil {
+		return err
+	}
+
+	c, ok := client.(base.CachedClientInterface)
+	if !ok {
+		return fmt.Errorf("invalid client type for %s record creation", s.recordType)
+	}
+
+	zone := s.GetZone(d)
+	name := s.GetName(d)
+
+	s.LogResourceOperation("Creating", s.recordType, zone, name)
+
+	// Targets are domain names and get a trailing dot like CNAME targets
+	apiRecord := s.AddTrailingDot(c, d.Get(s.field).(string))
+	response, err := c.AddRecord(s.recordType, zone, name, apiRecord, nil)
+	if err != nil {
+		if !s.ReconcileAfterTimeout(c, err, zone, name, s.recordType, apiRecord) {
+			return fmt.Errorf("failed to create %s record: %w", s.recordType, err)
+		}
+	} else if err := base.CheckAPIResponseForErrors(response); err != nil {
+		return fmt.Errorf("failed to create %s record: %w", s.recordType, err)
+	}
+
+	s.SetResourceID(d, zone, name, s.recordType)
+	c.InvalidateZoneCache(zone)
+	return nil
+}
+
+// Read reads the record from the API
+func (s *TargetRecordStrategy) Read(client interface{}, d *schema.ResourceData) error {
+	c, ok := client.(base.CachedClientInterface)
+	if !ok {
+		return fmt.Errorf("invalid client type for %s record read", s.recordType)
+	}
+
+	zone := s.GetZone(d)
+	name := s.GetName(d)
+
+	s.LogResourceOperation("Reading", s.recordType, zone, name)
+
+	records, err := c.GetRecordsByType(zone, name, s.recordType)
+	if err != nil {
+		return fmt.Errorf("failed to get zone records: %w", err)
+	}
+
+	if len(records) == 0 {
+		// No record found, mark as deleted
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("zone", zone)
+	d.Set("name", name)
+	d.Set(s.field, s.ConfiguredForm(records[0].Content, s.GetRecords(d)))
+
+	return nil
+}
+
+// Update replaces the record with one pointing to the new domain name
+func (s *TargetRecordStrategy) Update(client interface{}, d *schema.ResourceData) error {
+	if err := base.ValidateResourceRecords(s.recordType, d); err != nil {
+		return err
+	}
+
+	c, ok := client.(base.CachedClientInterface)
+	if !ok {
+		return fmt.Errorf("invalid client type for %s record update", s.recordType)
+	}
+
+	zone := s.GetZone(d)
+	name := s.GetName(d)
+
+	s.LogResourceOperation("Updating", s.recordType, zone, name)
+
+	oldTarget, newTarget := d.GetChange(s.field)
+
+	// Remove the old record first so the name never points to two domain names; a name may
+	// hold only one DNAME record
+	if old := oldTarget.(string); old != "" {
+		apiOldRecord := s.AddTrailingDot(c, old)
+		response, err := c.RemoveRecord(zone, name, s.recordType, s.StoredContent(c, zone, name, s.recordType, apiOldRecord), nil)
+		if err != nil {
+			return fmt.Errorf("failed to delete old %s record: %w", s.recordType, err)
+		}
+
+		if err := base.CheckAPIResponseForErrors(response); err != nil {
+			return fmt.Errorf("failed to delete old %s record: %w", s.recordType, err)
+		}
+	}
+
+	if updated := newTarget.(string); updated != "" {
+		apiNewRecord := s.AddTrailingDot(c, updated)
+		response, err := c.AddRecord(s.recordType, zone, name, apiNewRecord, nil)
+		if err != nil {
+			if !s.ReconcileAfterTimeout(c, err, zone, name, s.recordType, apiNewRecord) {
+				return fmt.Errorf("failed to create new %s record: %w", s.recordType, err)
+			}
+		} else if err := base.CheckAPIResponseForErrors(response); err != nil {
+			return fmt.Errorf("failed to create new %s record: %w", s.recordType, err)
+		}
+	}
+
+	c.InvalidateZoneCache(zone)
+	return nil
+}
+
+// Delete deletes the record
+func (s *TargetRecordStrategy) Delete(client interface{}, d *schema.ResourceData) error {
+	c, ok := client.(base.CachedClientInterface)
+	if !ok {
+		return fmt.Errorf("invalid client type for %s record deletion", s.recordType)
+	}
+
+	zone := s.GetZone(d)
+	name := s.GetName(d)
+
+	s.LogResourceOperation("Deleting", s.recordType, zone, name)
+
+	if target := d.Get(s.field).(string); target != "" {
+		apiRecord := s.AddTrailingDot(c, target)
+		response, err := c.RemoveRecord(zone, name, s.recordType, s.StoredContent(c, zone, name, s.recordType, apiRecord), nil)
+		if err := base.CheckRemoveResponse(response, err); err != nil {
+			return fmt.Errorf("failed to delete %s record: %w", s.recordType, err)
+		}
+	}
+
+	c.InvalidateZoneCache(zone)
+	return nil
+}
+
+// Import imports an existing record
+func (s *TargetRecordStrategy) Import(client interface{}, d *schema.ResourceData) error {
+	zone, name, err := s.ParseImportID(client, d.Id())
+	if err != nil {
+		return err
+	}
+
+	d.Set("zone", zone)
+	d.Set("name", name)
+
+	return s.Read(client, d)
+}
@@ -0,0 +1,103 @@
+package strategies_test
+
+import (
+	"testing"
+
+	"terraform-provider-regru/internal/fakeclient"
+	"terraform-provider-regru/resource/base"
+	"terraform-provider-regru/resource/resources"
+	"terraform-provider-regru/resource/strategies"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// targetRecordTypes lists the record types using the single target strategy, with a zone and
+// name to test them at
+var targetRecordTypes = []struct {
+	recordType string
+	field      string
+	zone, name string
+	resource   func() *schema.Resource
+	strategy   func() *strategies.TargetRecordStrategy
+}{
+	{"PTR", "ptrdname", "2.0.192.in-addr.arpa", "1", resources.ResourceDNSPTRRecord, strategies.NewPTRRecordStrategy},
+	{"DNAME", "target", "example.com", "legacy", resources.ResourceDNSDNAMERecord, strategies.NewDNAMERecordStrategy},
+}
+
+func TestTargetRecordLifecycle(t *testing.T) {
+	for _, tc := range targetRecordTypes {
+		t.Run(tc.recordType, func(t *testing.T) {
+			fake := fakeclient.New(tc.zone)
+			strategy := tc.strategy()
+			resource := tc.resource()
+			config := func(target string) map[string]interface{} {
+				return map[string]interface{}{"zone": tc.zone, "name": tc.name, tc.field: target}
+			}
+
+			d := newData(t, resource, config("host.example.net"))
+			if err := strategy.Create(fake, d); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			if want := tc.zone + "/" + tc.name; d.Id() != want {
+				t.Errorf("ID = %q, want %s", d.Id(), want)
+			}
+			// The domain name is written with a trailing dot
+			expectStrings(t, "zone after create", zoneContents(fake, tc.zone, tc.name, tc.recordType), []string{"host.example.net."})
+
+			// Refresh keeps the configured spelling
+			if err := strategy.Read(fake, d); err != nil {
+				t.Fatalf("Read: %v", err)
+			}
+			if got := d.Get(tc.field); got != "host.example.net" {
+				t.Errorf("%s after refresh = %q, want host.example.net", tc.field, got)
+			}
+
+			// The old record is removed before the new one is added
+			fake.Calls = nil
+			d = changedData(t, resource, d.State(), config("other.example.net."))
+			if err := strategy.Update(fake, d); err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+			expectStrings(t, "update calls", fake.Calls, []string{
+				"remove " + tc.recordType + " " + tc.name + " host.example.net.",
+				"add " + tc.recordType + " " + tc.name + " other.example.net.",
+			})
+
+			if err := strategy.Delete(fake, d); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+			expectStrings(t, "zone after delete", zoneContents(fake, tc.zone, tc.name, tc.recordType), []string{})
+		})
+	}
+}
+
+func TestTargetRecordImport(t *testing.T) {
+	for _, tc := range targetRecordTypes {
+		t.Run(tc.recordType, func(t *testing.T) {
+			fake := fakeclient.New(tc.zone)
+			fake.SetRecords(tc.zone, []base.DNSRecord{
+				{Subname: tc.name, Rectype: tc.recordType, Content: "host.example.net."},
+				{Subname: tc.name, Rectype: "TXT", Content: "unrelated"},
+				{Subname: "other", Rectype: tc.recordType, Content: "other.example.net."},
+			})
+
+			d := importData(tc.resource(), tc.zone+"/"+tc.name)
+			if err := tc.strategy().Import(fake, d); err != nil {
+				t.Fatalf("Import: %v", err)
+			}
+			// The trailing dot is dropped, as for CNAME targets
+			if got := d.Get(tc.field); got != "host.example.net" {
+				t.Errorf("%s = %q, want host.example.net", tc.field, got)
+			}
+
+			// A name without a record of the type is gone
+			d = importData(tc.resource(), tc.zone+"/missing")
+			if err := tc.strategy().Import(fake, d); err != nil {
+				t.Fatalf("Import: %v", err)
+			}
+			if d.Id() != "" {
+				t.Errorf("ID = %q after importing a name without records, want it cleared", d.Id())
+			}
+		})
+	}
+}
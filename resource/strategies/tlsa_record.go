@@ -0,0 +1,337 @@
+package strategies
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"terraform-provider-regru/resource/base"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// TLSARecordStrategy implements the strategy for TLSA records
+type TLSARecordStrategy struct {
+	base.BaseStrategy
+}
+
+// NewTLSARecordStrategy creates a new TLSA record strategy
+func NewTLSARecordStrategy() *TLSARecordStrategy {
+	return &TLSARecordStrategy{}
+}
+
+// TLSARecord represents a single TLSA record
+type TLSARecord struct {
+	Usage        int    `json:"usage"`
+	Selector     int    `json:"selector"`
+	MatchingType int    `json:"matching_type"`
+	Certificate  string `json:"certificate"`
+}
+
+// String returns a sortable string representation of the TLSA record.
+// Certificate data is hexadecimal, so it is compared regardless of letter case.
+func (tlsa TLSARecord) String() string {
+	return fmt.Sprintf("%d_%d_%d_%s", tlsa.Usage, tlsa.Selector, tlsa.MatchingType, strings.ToLower(tlsa.Certificate))
+}
+
+// parseTLSARecords converts the record from schema to TLSARecord structs
+func (s *TLSARecordStrategy) parseTLSARecords(d *schema.ResourceData) ([]TLSARecord, error) {
+	recordList, _ := d.Get("record").([]interface{})
+	return s.tlsaRecordsFromBlocks(recordList), nil
+}
+
+// tlsaRecordsFromBlocks converts record blocks to TLSARecord structs
+func (s *TLSARecordStrategy) tlsaRecordsFromBlocks(blocks []interface{}) []TLSARecord {
+	var tlsaRecords []TLSARecord
+	for _, record := range base.RecordsFromSchema("TLSA", blocks) {
+		tlsaRecords = append(tlsaRecords, TLSARecord{
+			Usage:        record.Usage,
+			Selector:     record.Selector,
+			MatchingType: record.MatchingType,
+			Certificate:  record.Value,
+		})
+	}
+	return tlsaRecords
+}
+
+// Create creates TLSA records
+func (s *TLSARecordStrategy) Create(meta interface{}, d *schema.ResourceData) error {
+	if err := base.ValidateResourceRecords("TLSA", d); err != nil {
+		return err
+	}
+
+	c := meta.(base.CachedClientInterface)
+	zone := s.GetZone(d)
+	name := s.GetName(d)
+
+	tlsaRecords, err := s.parseTLSARecords(d)
+	if err != nil {
+		return err
+	}
+
+	s.LogResourceOperation("Creating", "TLSA", zone, name)
+
+	// Validate records
+	if len(tlsaRecords) == 0 {
+		return fmt.Errorf("at least one TLSA record must be specified")
+	}
+
+	// Sort records for consistent processing
+	sort.Slice(tlsaRecords, func(i, j int) bool {
+		return tlsaRecords[i].String() < tlsaRecords[j].String()
+	})
+
+	// Add each TLSA record using the specific AddTLSARecord method
+	for _, tlsaRecord := range tlsaRecords {
+		log.Printf("[DEBUG] Adding TLSA record: %s.%s -> %d %d %d %s", name, zone,
+			tlsaRecord.Usage, tlsaRecord.Selector, tlsaRecord.MatchingType, tlsaRecord.Certificate)
+
+		response, err := c.AddTLSARecord(zone, name, tlsaRecord.Certificate, &tlsaRecord.Usage, &tlsaRecord.Selector, &tlsaRecord.MatchingType)
+		if err != nil {
+			if !s.reconcileTLSAAdd(c, err, zone, name, tlsaRecord) {
+				return fmt.Errorf("failed to create TLSA record %s: %w", tlsaRecord.Certificate, err)
+			}
+		} else if err := base.CheckAPIResponseForErrors(response); err != nil {
+			return fmt.Errorf("failed to create TLSA record %s: %w", tlsaRecord.Certificate, err)
+		}
+	}
+
+	// Set resource ID
+	s.SetResourceID(d, zone, name, "TLSA")
+
+	return s.ReadAfterWrite(c, d, zone, func() error { return s.Read(meta, d) })
+}
+
+// reconcileTLSAAdd reports whether a TLSA add that timed out was applied anyway, matching the
+// record by usage, selector and matching type as well as by certificate data
+func (s *TLSARecordStrategy) reconcileTLSAAdd(c base.CachedClientInterface, err error, zone, name string, record TLSARecord) bool {
+	return s.ReconcileMatchingAfterTimeout(c, err, zone, name, "TLSA", record.String(), func(rr base.DNSRecord) bool {
+		parsed := base.ParseTLSAContent(rr)
+		found := TLSARecord{Usage: parsed.Usage, Selector: parsed.Selector, MatchingType: parsed.MatchingType, Certificate: parsed.Value}
+		return found.String() == record.String()
+	})
+}
+
+// Read reads TLSA records from the API
+func (s *TLSARecordStrategy) Read(meta interface{}, d *schema.ResourceData) error {
+	c := meta.(base.CachedClientInterface)
+	zone := s.GetZone(d)
+	name := s.GetName(d)
+
+	s.LogResourceOperation("Reading", "TLSA", zone, name)
+
+	// Get zone data from API (with caching)
+	response, err := c.GetRecordsWithCache(zone)
+	if err != nil {
+		return fmt.Errorf("failed to get zone records: %w", err)
+	}
+
+	// Parse the response
+	var zoneResponse base.DNSZoneResponse
+	if err := json.Unmarshal(response, &zoneResponse); err != nil {
+		return fmt.Errorf("failed to parse DNS records response: %w", err)
+	}
+	if err := zoneResponse.CheckZone(zone); err != nil {
+		return err
+	}
+
+	// Keep the configured letter case of certificate data so refresh doesn't report drift
+	configured, _ := s.parseTLSARecords(d)
+
+	// Parse response and find TLSA records
+	var foundTLSARecords []TLSARecord
+	for _, domain := range zoneResponse.Answer.Domains {
+		for _, record := range domain.Rrs {
+			if record.Rectype != "TLSA" || !base.MatchesSubname(record.Subname, name) {
+				continue
+			}
+
+			parsed := base.ParseTLSAContent(record)
+			tlsaRecord := TLSARecord{
+				Usage:        parsed.Usage,
+				Selector:     parsed.Selector,
+				MatchingType: parsed.MatchingType,
+				Certificate:  parsed.Value,
+			}
+			for _, configuredRecord := range configured {
+				if configuredRecord.String() == tlsaRecord.String() {
+					tlsaRecord.Certificate = configuredRecord.Certificate
+					break
+				}
+			}
+
+			foundTLSARecords = append(foundTLSARecords, tlsaRecord)
+		}
+	}
+
+	if len(foundTLSARecords) == 0 {
+		log.Printf("[DEBUG] No TLSA records found for %s.%s", name, zone)
+		// No records found, mark as deleted
+		d.SetId("")
+		return nil
+	}
+
+	// Sort records for consistent state
+	sort.Slice(foundTLSARecords, func(i, j int) bool {
+		return foundTLSARecords[i].String() < foundTLSARecords[j].String()
+	})
+
+	// Set the data
+	d.Set("zone", zone)
+	d.Set("name", name)
+
+	// Convert to record blocks for Terraform record schema
+	var records []base.Record
+	for _, tlsaRecord := range foundTLSARecords {
+		records = append(records, base.Record{
+			Usage:        tlsaRecord.Usage,
+			Selector:     tlsaRecord.Selector,
+			MatchingType: tlsaRecord.MatchingType,
+			Value:        tlsaRecord.Certificate,
+		})
+	}
+	d.Set("record", base.RecordsToSchema("TLSA", records))
+
+	log.Printf("[DEBUG] Successfully read %d TLSA records", len(foundTLSARecords))
+	return nil
+}
+
+// Update updates TLSA records
+func (s *TLSARecordStrategy) Update(meta interface{}, d *schema.ResourceData) error {
+	if err := base.ValidateResourceRecords("TLSA", d); err != nil {
+		return err
+	}
+
+	c := meta.(base.CachedClientInterface)
+	zone := s.GetZone(d)
+	name := s.GetName(d)
+
+	s.LogResourceOperation("Updating", "TLSA", zone, name)
+
+	if d.HasChange("record") {
+		// Get old and new configurations
+		oldTLSARecords, err := s.getOldTLSARecords(d)
+		if err != nil {
+			return err
+		}
+
+		newTLSARecords, err := s.parseTLSARecords(d)
+		if err != nil {
+			return err
+		}
+
+		// Find records to remove
+		recordsToRemove := []TLSARecord{}
+		for _, oldRecord := range oldTLSARecords {
+			found := false
+			for _, newRecord := range newTLSARecords {
+				if oldRecord.String() == newRecord.String() {
+					found = true
+					break
+				}
+			}
+			if !found {
+				recordsToRemove = append(recordsToRemove, oldRecord)
+			}
+		}
+
+		// Find records to add
+		recordsToAdd := []TLSARecord{}
+		for _, newRecord := range newTLSARecords {
+			found := false
+			for _, oldRecord := range oldTLSARecords {
+				if newRecord.String() == oldRecord.String() {
+					found = true
+					break
+				}
+			}
+			if !found {
+				recordsToAdd = append(recordsToAdd, newRecord)
+			}
+		}
+
+		// Remove old records
+		for _, record := range recordsToRemove {
+			log.Printf("[DEBUG] Removing TLSA record: %s -> %d %d %d %s", name,
+				record.Usage, record.Selector, record.MatchingType, record.Certificate)
+			response, err := c.RemoveTLSARecord(zone, name, record.Certificate, &record.Usage, &record.Selector, &record.MatchingType)
+			if err != nil {
+				return fmt.Errorf("failed to remove TLSA record %s: %w", record.Certificate, err)
+			}
+
+			if err := base.CheckAPIResponseForErrors(response); err != nil {
+				return fmt.Errorf("failed to remove TLSA record %s: %w", record.Certificate, err)
+			}
+		}
+
+		// Add new records
+		for _, record := range recordsToAdd {
+			log.Printf("[DEBUG] Adding TLSA record: %s -> %d %d %d %s", name,
+				record.Usage, record.Selector, record.MatchingType, record.Certificate)
+			response, err := c.AddTLSARecord(zone, name, record.Certificate, &record.Usage, &record.Selector, &record.MatchingType)
+			if err != nil {
+				if !s.reconcileTLSAAdd(c, err, zone, name, record) {
+					return fmt.Errorf("failed to add TLSA record %s: %w", record.Certificate, err)
+				}
+			} else if err := base.CheckAPIResponseForErrors(response); err != nil {
+				return fmt.Errorf("failed to add TLSA record %s: %w", record.Certificate, err)
+			}
+		}
+
+		// Invalidate cache after updates
+		c.InvalidateZoneCache(zone)
+	}
+
+	return s.ReadAfterWrite(c, d, zone, func() error { return s.Read(meta, d) })
+}
+
+// getOldTLSARecords reconstructs old TLSA records from the change data
+func (s *TLSARecordStrategy) getOldTLSARecords(d *schema.ResourceData) ([]TLSARecord, error) {
+	old, _ := d.GetChange("record")
+	oldRecordList, _ := old.([]interface{})
+	return s.tlsaRecordsFromBlocks(oldRecordList), nil
+}
+
+// Delete deletes TLSA records
+func (s *TLSARecordStrategy) Delete(meta interface{}, d *schema.ResourceData) error {
+	c := meta.(base.CachedClientInterface)
+	zone := s.GetZone(d)
+	name := s.GetName(d)
+
+	tlsaRecords, err := s.parseTLSARecords(d)
+	if err != nil {
+		return err
+	}
+
+	s.LogResourceOperation("Deleting", "TLSA", zone, name)
+
+	// Remove each TLSA record
+	for _, tlsaRecord := range tlsaRecords {
+		log.Printf("[DEBUG] Removing TLSA record: %s -> %d %d %d %s", name,
+			tlsaRecord.Usage, tlsaRecord.Selector, tlsaRecord.MatchingType, tlsaRecord.Certificate)
+		response, err := c.RemoveTLSARecord(zone, name, tlsaRecord.Certificate, &tlsaRecord.Usage, &tlsaRecord.Selector, &tlsaRecord.MatchingType)
+		if err := base.CheckRemoveResponse(response, err); err != nil {
+			return fmt.Errorf("failed to delete TLSA record %s: %w", tlsaRecord.Certificate, err)
+		}
+	}
+
+	// Invalidate cache after deletion
+	c.InvalidateZoneCache(zone)
+
+	return nil
+}
+
+// Import imports existing TLSA records
+func (s *TLSARecordStrategy) Import(meta interface{}, d *schema.ResourceData) error {
+	// Parse the import ID using the common format
+	zone, name, err := s.ParseImportID(meta, d.Id())
+	if err != nil {
+		return err
+	}
+
+	d.Set("zone", zone)
+	d.Set("name", name)
+
+	return s.Read(meta, d)
+}
@@ -0,0 +1,124 @@
+package strategies_test
+
+import (
+	"strings"
+	"testing"
+
+	"terraform-provider-regru/internal/fakeclient"
+	"terraform-provider-regru/resource/base"
+	"terraform-provider-regru/resource/resources"
+	"terraform-provider-regru/resource/strategies"
+)
+
+// tlsaBlock returns a TLSA record block of the resource configuration
+func tlsaBlock(usage, selector, matchingType int, certificate string) map[string]interface{} {
+	return map[string]interface{}{"usage": usage, "selector": selector, "matching_type": matchingType, "certificate": certificate}
+}
+
+func TestTLSARecordLifecycle(t *testing.T) {
+	current := strings.Repeat("ab", 32)
+	next := strings.Repeat("cd", 32)
+
+	fake := fakeclient.New("example.com")
+	strategy := strategies.NewTLSARecordStrategy()
+	resource := resources.ResourceDNSTLSARecord()
+
+	d := newData(t, resource, map[string]interface{}{
+		"zone":   "example.com",
+		"name":   "_443._tcp",
+		"record": []interface{}{tlsaBlock(3, 1, 1, current)},
+	})
+	if err := strategy.Create(fake, d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if d.Id() != "example.com/_443._tcp" {
+		t.Errorf("ID = %q, want example.com/_443._tcp", d.Id())
+	}
+	expectStrings(t, "zone after create", zoneContents(fake, "example.com", "_443._tcp", "TLSA"), []string{"3 1 1 " + current})
+
+	// A certificate rollover publishes the next certificate next to the current one
+	fake.Calls = nil
+	d = changedData(t, resource, d.State(), map[string]interface{}{
+		"zone":   "example.com",
+		"name":   "_443._tcp",
+		"record": []interface{}{tlsaBlock(3, 1, 1, current), tlsaBlock(3, 1, 1, next)},
+	})
+	if err := strategy.Update(fake, d); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	expectStrings(t, "rollover calls", fake.Calls, []string{"add TLSA _443._tcp 3 1 1 " + next})
+
+	// Changing only the usage of a record replaces it
+	fake.Calls = nil
+	d = changedData(t, resource, d.State(), map[string]interface{}{
+		"zone":   "example.com",
+		"name":   "_443._tcp",
+		"record": []interface{}{tlsaBlock(2, 1, 1, next)},
+	})
+	if err := strategy.Update(fake, d); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	expectStrings(t, "zone after update", zoneContents(fake, "example.com", "_443._tcp", "TLSA"), []string{"2 1 1 " + next})
+
+	if err := strategy.Delete(fake, d); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	expectStrings(t, "zone after delete", zoneContents(fake, "example.com", "_443._tcp", "TLSA"), []string{})
+}
+
+func TestTLSARecordImport(t *testing.T) {
+	certificate := strings.Repeat("AB", 32)
+	fake := fakeclient.New("example.com")
+	fake.SetRecords("example.com", []base.DNSRecord{
+		{Subname: "_443._tcp", Rectype: "TLSA", Content: "3 1 1 " + certificate},
+		{Subname: "_25._tcp", Rectype: "TLSA", Content: "3 1 1 " + strings.Repeat("cd", 32)},
+	})
+
+	d := importData(resources.ResourceDNSTLSARecord(), "example.com/_443._tcp")
+	if err := strategies.NewTLSARecordStrategy().Import(fake, d); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	blocks := d.Get("record").([]interface{})
+	if len(blocks) != 1 {
+		t.Fatalf("record blocks = %v, want 1", blocks)
+	}
+	block := blocks[0].(map[string]interface{})
+	if block["usage"] != 3 || block["selector"] != 1 || block["matching_type"] != 1 || !strings.EqualFold(block["certificate"].(string), certificate) {
+		t.Errorf("record block = %v, want 3 1 1 and the certificate", block)
+	}
+}
+
+func TestTLSARecordAddAfterTimeout(t *testing.T) {
+	current := strings.Repeat("ab", 32)
+	next := strings.Repeat("cd", 32)
+	resource := resources.ResourceDNSTLSARecord()
+	config := func(records ...interface{}) map[string]interface{} {
+		return map[string]interface{}{"zone": "example.com", "name": "_443._tcp", "record": records}
+	}
+
+	// The rollover record turns out to be present, so the add is neither failed nor repeated
+	fake := fakeclient.New("example.com")
+	strategy := strategies.NewTLSARecordStrategy()
+	d := newData(t, resource, config(tlsaBlock(3, 1, 1, current)))
+	if err := strategy.Create(fake, d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	d = changedData(t, resource, d.State(), config(tlsaBlock(3, 1, 1, current), tlsaBlock(3, 1, 1, next)))
+	if err := strategy.Update(timeoutClient{Client: fake}, d); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	expectStrings(t, "zone after update", zoneContents(fake, "example.com", "_443._tcp", "TLSA"), []string{
+		"3 1 1 " + current,
+		"3 1 1 " + next,
+	})
+
+	// A lost add is reported even though the certificate data is present with another usage
+	fake = fakeclient.New("example.com")
+	fake.SetRecords("example.com", []base.DNSRecord{
+		{Subname: "_443._tcp", Rectype: "TLSA", Content: "2 1 1 " + current},
+	})
+	d = newData(t, resource, config(tlsaBlock(3, 1, 1, current)))
+	if err := strategy.Create(timeoutClient{Client: fake, lost: true}, d); err == nil {
+		t.Fatal("Create succeeded although the record was never added")
+	}
+}